@@ -0,0 +1,393 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: order/v1/order.proto
+
+package orderv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	OrderService_GetOrderBook_FullMethodName        = "/order.v1.OrderService/GetOrderBook"
+	OrderService_SaveOrderBook_FullMethodName       = "/order.v1.OrderService/SaveOrderBook"
+	OrderService_GetOrderHistory_FullMethodName     = "/order.v1.OrderService/GetOrderHistory"
+	OrderService_FindOrderHistory_FullMethodName    = "/order.v1.OrderService/FindOrderHistory"
+	OrderService_SaveOrderHistory_FullMethodName    = "/order.v1.OrderService/SaveOrderHistory"
+	OrderService_SubscribeOrderBooks_FullMethodName = "/order.v1.OrderService/SubscribeOrderBooks"
+	OrderService_SubscribeFills_FullMethodName      = "/order.v1.OrderService/SubscribeFills"
+)
+
+// OrderServiceClient is the client API for OrderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OrderServiceClient interface {
+	GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*GetOrderBookResponse, error)
+	SaveOrderBook(ctx context.Context, in *SaveOrderBookRequest, opts ...grpc.CallOption) (*SaveResult, error)
+	GetOrderHistory(ctx context.Context, in *GetOrderHistoryRequest, opts ...grpc.CallOption) (*GetOrderHistoryResponse, error)
+	FindOrderHistory(ctx context.Context, in *HistoryFilter, opts ...grpc.CallOption) (*GetOrderHistoryResponse, error)
+	SaveOrderHistory(ctx context.Context, in *SaveOrderHistoryRequest, opts ...grpc.CallOption) (*SaveResult, error)
+	SubscribeOrderBooks(ctx context.Context, in *SubscribeOrderBooksRequest, opts ...grpc.CallOption) (OrderService_SubscribeOrderBooksClient, error)
+	SubscribeFills(ctx context.Context, in *SubscribeFillsRequest, opts ...grpc.CallOption) (OrderService_SubscribeFillsClient, error)
+}
+
+type orderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrderServiceClient(cc grpc.ClientConnInterface) OrderServiceClient {
+	return &orderServiceClient{cc}
+}
+
+func (c *orderServiceClient) GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*GetOrderBookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrderBookResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetOrderBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) SaveOrderBook(ctx context.Context, in *SaveOrderBookRequest, opts ...grpc.CallOption) (*SaveResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SaveResult)
+	err := c.cc.Invoke(ctx, OrderService_SaveOrderBook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetOrderHistory(ctx context.Context, in *GetOrderHistoryRequest, opts ...grpc.CallOption) (*GetOrderHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrderHistoryResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetOrderHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) FindOrderHistory(ctx context.Context, in *HistoryFilter, opts ...grpc.CallOption) (*GetOrderHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrderHistoryResponse)
+	err := c.cc.Invoke(ctx, OrderService_FindOrderHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) SaveOrderHistory(ctx context.Context, in *SaveOrderHistoryRequest, opts ...grpc.CallOption) (*SaveResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SaveResult)
+	err := c.cc.Invoke(ctx, OrderService_SaveOrderHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) SubscribeOrderBooks(ctx context.Context, in *SubscribeOrderBooksRequest, opts ...grpc.CallOption) (OrderService_SubscribeOrderBooksClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[0], OrderService_SubscribeOrderBooks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &orderServiceSubscribeOrderBooksClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OrderService_SubscribeOrderBooksClient interface {
+	Recv() (*OrderBookMessage, error)
+	grpc.ClientStream
+}
+
+type orderServiceSubscribeOrderBooksClient struct {
+	grpc.ClientStream
+}
+
+func (x *orderServiceSubscribeOrderBooksClient) Recv() (*OrderBookMessage, error) {
+	m := new(OrderBookMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *orderServiceClient) SubscribeFills(ctx context.Context, in *SubscribeFillsRequest, opts ...grpc.CallOption) (OrderService_SubscribeFillsClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[1], OrderService_SubscribeFills_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &orderServiceSubscribeFillsClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OrderService_SubscribeFillsClient interface {
+	Recv() (*FillEvent, error)
+	grpc.ClientStream
+}
+
+type orderServiceSubscribeFillsClient struct {
+	grpc.ClientStream
+}
+
+func (x *orderServiceSubscribeFillsClient) Recv() (*FillEvent, error) {
+	m := new(FillEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// OrderServiceServer is the server API for OrderService service.
+// All implementations must embed UnimplementedOrderServiceServer
+// for forward compatibility
+type OrderServiceServer interface {
+	GetOrderBook(context.Context, *GetOrderBookRequest) (*GetOrderBookResponse, error)
+	SaveOrderBook(context.Context, *SaveOrderBookRequest) (*SaveResult, error)
+	GetOrderHistory(context.Context, *GetOrderHistoryRequest) (*GetOrderHistoryResponse, error)
+	FindOrderHistory(context.Context, *HistoryFilter) (*GetOrderHistoryResponse, error)
+	SaveOrderHistory(context.Context, *SaveOrderHistoryRequest) (*SaveResult, error)
+	SubscribeOrderBooks(*SubscribeOrderBooksRequest, OrderService_SubscribeOrderBooksServer) error
+	SubscribeFills(*SubscribeFillsRequest, OrderService_SubscribeFillsServer) error
+	mustEmbedUnimplementedOrderServiceServer()
+}
+
+// UnimplementedOrderServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedOrderServiceServer struct {
+}
+
+func (UnimplementedOrderServiceServer) GetOrderBook(context.Context, *GetOrderBookRequest) (*GetOrderBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderBook not implemented")
+}
+func (UnimplementedOrderServiceServer) SaveOrderBook(context.Context, *SaveOrderBookRequest) (*SaveResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveOrderBook not implemented")
+}
+func (UnimplementedOrderServiceServer) GetOrderHistory(context.Context, *GetOrderHistoryRequest) (*GetOrderHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderHistory not implemented")
+}
+func (UnimplementedOrderServiceServer) FindOrderHistory(context.Context, *HistoryFilter) (*GetOrderHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindOrderHistory not implemented")
+}
+func (UnimplementedOrderServiceServer) SaveOrderHistory(context.Context, *SaveOrderHistoryRequest) (*SaveResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveOrderHistory not implemented")
+}
+func (UnimplementedOrderServiceServer) SubscribeOrderBooks(*SubscribeOrderBooksRequest, OrderService_SubscribeOrderBooksServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeOrderBooks not implemented")
+}
+func (UnimplementedOrderServiceServer) SubscribeFills(*SubscribeFillsRequest, OrderService_SubscribeFillsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeFills not implemented")
+}
+func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
+
+// UnsafeOrderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrderServiceServer will
+// result in compilation errors.
+type UnsafeOrderServiceServer interface {
+	mustEmbedUnimplementedOrderServiceServer()
+}
+
+func RegisterOrderServiceServer(s grpc.ServiceRegistrar, srv OrderServiceServer) {
+	s.RegisterService(&OrderService_ServiceDesc, srv)
+}
+
+func _OrderService_GetOrderBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetOrderBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetOrderBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetOrderBook(ctx, req.(*GetOrderBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_SaveOrderBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveOrderBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).SaveOrderBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_SaveOrderBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).SaveOrderBook(ctx, req.(*SaveOrderBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetOrderHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetOrderHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetOrderHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetOrderHistory(ctx, req.(*GetOrderHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_FindOrderHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryFilter)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).FindOrderHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_FindOrderHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).FindOrderHistory(ctx, req.(*HistoryFilter))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_SaveOrderHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveOrderHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).SaveOrderHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_SaveOrderHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).SaveOrderHistory(ctx, req.(*SaveOrderHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_SubscribeOrderBooks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeOrderBooksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).SubscribeOrderBooks(m, &orderServiceSubscribeOrderBooksServer{ServerStream: stream})
+}
+
+type OrderService_SubscribeOrderBooksServer interface {
+	Send(*OrderBookMessage) error
+	grpc.ServerStream
+}
+
+type orderServiceSubscribeOrderBooksServer struct {
+	grpc.ServerStream
+}
+
+func (x *orderServiceSubscribeOrderBooksServer) Send(m *OrderBookMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _OrderService_SubscribeFills_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeFillsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).SubscribeFills(m, &orderServiceSubscribeFillsServer{ServerStream: stream})
+}
+
+type OrderService_SubscribeFillsServer interface {
+	Send(*FillEvent) error
+	grpc.ServerStream
+}
+
+type orderServiceSubscribeFillsServer struct {
+	grpc.ServerStream
+}
+
+func (x *orderServiceSubscribeFillsServer) Send(m *FillEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "order.v1.OrderService",
+	HandlerType: (*OrderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetOrderBook",
+			Handler:    _OrderService_GetOrderBook_Handler,
+		},
+		{
+			MethodName: "SaveOrderBook",
+			Handler:    _OrderService_SaveOrderBook_Handler,
+		},
+		{
+			MethodName: "GetOrderHistory",
+			Handler:    _OrderService_GetOrderHistory_Handler,
+		},
+		{
+			MethodName: "FindOrderHistory",
+			Handler:    _OrderService_FindOrderHistory_Handler,
+		},
+		{
+			MethodName: "SaveOrderHistory",
+			Handler:    _OrderService_SaveOrderHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeOrderBooks",
+			Handler:       _OrderService_SubscribeOrderBooks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeFills",
+			Handler:       _OrderService_SubscribeFills_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "order/v1/order.proto",
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/db"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+)
+
+const apiKeyUsage = `usage: vortex-test apikey -name name -scopes scope,... [flags]
+
+Creates an API key and prints it. The key is not stored and cannot be shown
+again, only its hash is. Scopes are books:read, books:write, history:read,
+history:write and admin. Use it to create the first admin key; further keys
+can be created with POST /admin/api-keys.
+
+`
+
+// runAPIKey runs the apikey subcommand and returns the process exit code.
+func runAPIKey(args []string) int {
+	fs := flag.NewFlagSet("apikey", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), apiKeyUsage)
+		fs.PrintDefaults()
+	}
+
+	name := fs.String("name", "", "who or what the key is for")
+	scopes := fs.String("scopes", "", "comma separated scopes")
+	clients := fs.String("clients", "", "comma separated client names the history scopes are restricted to, every client when empty")
+	ttl := fs.Duration("ttl", 0, "how long the key is valid, forever when zero")
+	env := fs.String("env", ".env", "file with the ClickHouse connection settings")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	key := entity.APIKey{Name: *name}
+	for _, scope := range splitList(*scopes) {
+		key.Scopes = append(key.Scopes, entity.Scope(scope))
+	}
+	key.ClientNames = splitList(*clients)
+	if *ttl > 0 {
+		expiresAt := time.Now().Add(*ttl)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := key.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		fs.Usage()
+		return 2
+	}
+
+	database, err := db.Connect(*env)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to database: %v\n", err)
+		return 1
+	}
+	if err := db.Migrate(database); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to migrate database: %v\n", err)
+		return 1
+	}
+
+	token, _, err := service.NewAuthService(repository.NewAPIKeyRepository(database), nil).CreateAPIKey(key)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Println(token)
+	return 0
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
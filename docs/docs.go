@@ -15,62 +15,76 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/orderbook": {
+        "/admin/api-keys": {
             "get": {
-                "description": "Retrieve the order book for a specific exchange and trading pair.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "List the API keys that are not revoked, without their secrets.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "order"
-                ],
-                "summary": "Get Order Book",
-                "parameters": [
-                    {
-                        "description": "Order Book Request",
-                        "name": "orderBookRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.OrderBookRequest"
-                        }
-                    }
+                    "auth"
                 ],
+                "summary": "List API Keys",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/entity.OrderBook"
+                                "$ref": "#/definitions/entity.APIKey"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Save a new order book entry.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create an API key with scopes, optionally restricted to the fills of some clients and expiring.\nThe key is only returned by this request; store it, only its hash is kept.",
                 "consumes": [
                     "application/json"
                 ],
@@ -78,119 +92,212 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "auth"
                 ],
-                "summary": "Save Order Book",
+                "summary": "Create API Key",
                 "parameters": [
                     {
-                        "description": "Order Books",
-                        "name": "orderBooks",
+                        "description": "API Key",
+                        "name": "apiKey",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.OrderBook"
-                            }
+                            "$ref": "#/definitions/entity.APIKey"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/entity.CreatedAPIKey"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/orderhistory": {
-            "get": {
-                "description": "Retrieve the order history for a specific client.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+        "/admin/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Revoke an API key. Other instances of the service may accept it for up to a minute.",
                 "tags": [
-                    "order"
+                    "auth"
                 ],
-                "summary": "Get Order History",
+                "summary": "Revoke API Key",
                 "parameters": [
                     {
-                        "description": "Client",
-                        "name": "client",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "API Key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
                         "schema": {
-                            "$ref": "#/definitions/entity.Client"
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/rate-limits": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     }
                 ],
+                "description": "List the rate quotas in force: the defaults of the route groups and the quotas set for routes, groups and API keys.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List Rate Quotas",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/entity.HistoryOrder"
+                                "$ref": "#/definitions/entity.RateQuota"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     }
                 }
             },
-            "post": {
-                "description": "Save a new history order entry.",
-                "consumes": [
-                    "application/json"
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
-                "produces": [
+                "description": "Set the requests per second and burst of a route group, such as read or write, or of a single route, such as GET /v1/history/{clientName},\nfor one API key or, without key_id, for every caller. The quota applies at once on this instance and within a minute on the others.",
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "order"
+                    "admin"
                 ],
-                "summary": "Save Order History",
+                "summary": "Set Rate Quota",
                 "parameters": [
                     {
-                        "description": "History Order",
-                        "name": "historyOrder",
+                        "description": "Rate Quota",
+                        "name": "rateQuota",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.HistoryOrder"
+                            "$ref": "#/definitions/entity.RateQuota"
                         }
                     }
                 ],
@@ -204,129 +311,4514 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "entity.Client": {
-            "type": "object",
-            "properties": {
-                "client_name": {
-                    "type": "string"
-                },
-                "exchange_name": {
-                    "type": "string"
-                },
-                "label": {
-                    "type": "string"
-                },
-                "pair": {
-                    "type": "string"
-                }
-            }
-        },
-        "entity.DepthOrder": {
-            "type": "object",
-            "properties": {
-                "base_qty": {
-                    "type": "number"
-                },
-                "price": {
-                    "type": "number"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete a quota set for a route or group, so the default of the route group applies again.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete Rate Quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Route group or method and pattern",
+                        "name": "route",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "API Key ID",
+                        "name": "key_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "entity.HistoryOrder": {
-            "type": "object",
-            "properties": {
-                "algorithm_name_placed": {
-                    "type": "string"
-                },
-                "base_qty": {
-                    "type": "number"
-                },
-                "client_name": {
-                    "type": "string"
-                },
-                "commission_quote_qty": {
-                    "type": "number"
-                },
-                "exchange_name": {
-                    "type": "string"
-                },
-                "highest_buy_prc": {
-                    "type": "number"
-                },
-                "label": {
-                    "type": "string"
-                },
-                "lowest_sell_prc": {
-                    "type": "number"
-                },
-                "pair": {
-                    "type": "string"
-                },
+        "/analytics/algorithms": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Rank algorithms by fill count, traded notional, realized PnL, slippage or fees over a time range and set of pairs.\nFilters left out are not applied; list filters may be repeated or comma-separated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Get Algorithm Performance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "client_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Trading Pairs",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Labels",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Algorithm Names",
+                        "name": "algorithm_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest fill time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest fill time, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "fill_count, traded_notional, realized_pnl, net_pnl (default), avg_slippage_bps or fees",
+                        "name": "sort_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.AlgorithmPerformance"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/analytics/bars": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "OHLCV bars of our own fills for an exchange and pair with buy/sell volume, trade count and VWAP.\nalgorithm_name may be repeated or hold comma-separated values.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Get Trade Bars",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trading Pair",
+                        "name": "pair",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bar width, such as 1m or 1h",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "client_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Algorithm Names",
+                        "name": "algorithm_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest fill time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest fill time, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.TradeBar"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/analytics/markouts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Side-signed move of the stored order book mid at horizons after each order, aggregated per algorithm and pair.\nFilters left out are not applied; list filters may be repeated or comma-separated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Get Markouts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "client_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Trading Pairs",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Labels",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Algorithm Names",
+                        "name": "algorithm_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest fill time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest fill time, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Horizons after each order, such as 1s or 5m",
+                        "name": "horizon",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.MarkoutAnalysis"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/analytics/slippage": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Per-order slippage versus mid and touch with distributions grouped by exchange, pair and algorithm.\nFilters left out are not applied; list filters may be repeated or comma-separated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Get Slippage Analysis",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "client_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Trading Pairs",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Labels",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Algorithm Names",
+                        "name": "algorithm_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest fill time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest fill time, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Width of the distribution buckets in bps",
+                        "name": "bucket_bps",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the slippage of every order",
+                        "name": "include_orders",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.SlippageAnalysis"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/clients": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List registered client accounts, optionally of a single client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "client"
+                ],
+                "summary": "List Client Accounts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "client_name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ClientAccount"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Register a client account on an exchange under a label, optionally restricted to a set of pairs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "client"
+                ],
+                "summary": "Create Client Account",
+                "parameters": [
+                    {
+                        "description": "Client Account",
+                        "name": "clientAccount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ClientAccount"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/clients/{clientName}/{exchangeName}/{label}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve a registered client account.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "client"
+                ],
+                "summary": "Get Client Account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "clientName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchangeName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label",
+                        "name": "label",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ClientAccount"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replace the pairs of a registered client account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "client"
+                ],
+                "summary": "Update Client Account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "clientName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchangeName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label",
+                        "name": "label",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Client Account",
+                        "name": "clientAccount",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ClientAccount"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove a registered client account. Its history is kept.",
+                "tags": [
+                    "client"
+                ],
+                "summary": "Delete Client Account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "clientName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchangeName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label",
+                        "name": "label",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fees/schedules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List fee schedules, optionally of a single exchange.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fees"
+                ],
+                "summary": "List Fee Schedules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange_name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.FeeSchedule"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Set the fee tiers of an exchange from a point in time, or a client override of them. Negative rates are rebates.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fees"
+                ],
+                "summary": "Set Fee Schedule",
+                "parameters": [
+                    {
+                        "description": "Fee Schedule",
+                        "name": "feeSchedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.FeeSchedule"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Answer 200 while the process is running, whatever the state of its dependencies.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness Probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve the order history for a specific client.\nKept for existing callers, use GET /v1/history/{clientName} instead.",
+                "consumes": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Get Order History",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Client",
+                        "name": "client",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.Client"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.HistoryOrder"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Save a new history order entry. An order already saved under the same dedup key is reported as a duplicate.",
+                "consumes": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Save Order History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Replays the result of an earlier request with the same key and body",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "History Order",
+                        "name": "historyOrder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.HistoryOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SaveResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unregistered client account or Idempotency-Key reused with another body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orderbook": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve the order book for a specific exchange and trading pair.\nKept for existing callers, use GET /v1/orderbooks/{exchange}/{pair} instead.",
+                "consumes": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Get Order Book",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Order Book Request",
+                        "name": "orderBookRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.OrderBookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.OrderBook"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Save new order book entries. Snapshots already saved under the same dedup key are reported as duplicates.",
+                "consumes": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Save Order Book",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Replays the result of an earlier request with the same key and body",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Order Books",
+                        "name": "orderBooks",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.OrderBook"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.SaveResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key reused with another body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/events": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Move an order to a new lifecycle status. Illegal transitions are rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Save Order Event",
+                "parameters": [
+                    {
+                        "description": "Order Event",
+                        "name": "orderEvent",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.OrderEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{orderID}/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve the lifecycle events and fills of an order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Get Order Timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "orderID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OrderTimeline"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Check that ClickHouse can be reached, that the migrations created every table and column and that the ingestion queue is not saturated.\nAnswer 200 if every check passed and 503 otherwise, with the outcome of each check.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness Probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Readiness"
+                        }
+                    },
+                    "503": {
+                        "description": "Not Ready",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Readiness"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/commission": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Aggregate commissions by client, exchange, pair, algorithm and liquidity per day or month.\ngroup_by may be repeated or hold comma-separated dimensions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get Commission Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "client_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trading Pair",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Algorithm Name",
+                        "name": "algorithm_name_placed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest fill time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest fill time, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day (default) or month",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Dimensions to group by",
+                        "name": "group_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.CommissionReport"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/commission/converted": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Total the commissions and notionals of filtered fills per exchange, valued in a single currency at the order book mid prices when each fill was placed.\nFills charged in an asset that cannot be valued are counted as unpriced and left out of the totals.\nFilters left out are not applied; list filters may be repeated or comma-separated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Get Converted Commissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "client_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Trading Pairs",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Labels",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Algorithm Names",
+                        "name": "algorithm_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest fill time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest fill time, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to value commissions in, such as USDT",
+                        "name": "currency",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ConvertedCommission"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/fees": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Recompute the commission of filtered fills from the fee schedules and list the fills charged differently.\nFilters left out are not applied; list filters may be repeated or comma-separated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Reconcile Commissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "client_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Trading Pairs",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Labels",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Algorithm Names",
+                        "name": "algorithm_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest fill time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest fill time, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Difference from the expected commission still counted as a match",
+                        "name": "tolerance_bps",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FeeReconciliation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/risk/breaches": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the latest limits exceeded by saved fills, optionally of a single client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "List Risk Breaches",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "client_name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.RiskBreach"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/risk/check": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Check a proposed order against the risk limits of the client, its current position and the latest order book.\nA rejected order is still answered with 200, allowed is false and the breaches list the limits it would exceed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Check Order",
+                "parameters": [
+                    {
+                        "description": "Proposed Order",
+                        "name": "riskCheckRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RiskCheckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.RiskCheck"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "No price to value the order at",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/risk/limits": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List risk limits, optionally of a single client.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "List Risk Limits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "client_name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.RiskLimit"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Set the risk limits of a client on an exchange for a pair, or for every pair with an empty pair. A zero limit is not enforced.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Set Risk Limit",
+                "parameters": [
+                    {
+                        "description": "Risk Limit",
+                        "name": "riskLimit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RiskLimit"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the version and uptime of the service, its readiness checks, the latency of ClickHouse and the error reaching it, the row counts of its tables and the ingestion queue.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Service Status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ServiceStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tca": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Transaction cost analysis of parent orders grouped by label or algorithm, as JSON or a CSV download.\nFilters left out are not applied; list filters may be repeated or comma-separated.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Get TCA Reports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Set to csv to download the report as CSV",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "client_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Trading Pairs",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Labels",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Algorithm Names",
+                        "name": "algorithm_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest fill time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest fill time, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "label (default) or algorithm",
+                        "name": "group_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.TCAReport"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/history/{clientName}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve the fills of a client, oldest first. Filters left out are not applied; list filters may be repeated or comma-separated.",
+                "produces": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Get Order History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "clientName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Trading Pairs",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Labels",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Algorithm Names",
+                        "name": "algorithm_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest fill time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest fill time, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.HistoryOrder"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/history/{clientName}/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stream the fills of a client as a CSV or Parquet download, oldest first. Filters left out are not applied; list filters may be repeated or comma-separated.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.apache.parquet"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export Order History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "clientName",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or parquet",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Trading Pairs",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Labels",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Algorithm Names",
+                        "name": "algorithm_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest fill time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Latest fill time, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ingest/orderbooks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Open a WebSocket on which a collector pushes order book snapshots and deltas as entity.IngestMessage messages.\nThe Sec-WebSocket-Protocol header picks their encoding and that of the replies: json, the default, protobuf, with the IngestMessage and IngestReply messages of api/order/v1/order.proto, or msgpack.\nThe collector authenticates once with an Authorization: Bearer header holding an API key with the books:write scope.\nThe server first sends a welcome entity.IngestReply with the window, the number of messages that may be sent before waiting for acks,\nthen an ack or nack for every message once its books are saved, in batches, or rejected. Deltas apply to the latest book of the pair sent on the same connection.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Ingest Order Books",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/entity.IngestReply"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orderbooks/{exchange}/{pair}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve the order book snapshots of an exchange and trading pair. A pair containing a slash is URL-encoded, as in BTC%2FUSDT.",
+                "produces": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Get Order Book",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trading Pair",
+                        "name": "pair",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.OrderBook"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orderbooks/{exchange}/{pair}/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stream the order book snapshots of an exchange and trading pair as a CSV or Parquet download. A pair containing a slash is URL-encoded, as in BTC%2FUSDT.\nThe levels layout flattens the first depth levels of each side into columns, the rows layout writes one row per price level.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.apache.parquet"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export Order Book",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trading Pair",
+                        "name": "pair",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or parquet",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "levels (default) or rows",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Levels per side in the levels layout, 10 by default",
+                        "name": "depth",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stream/fills": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stream fills as Server-Sent Events as they are saved, each a \"fill\" event whose data is an entity.HistoryOrder and whose id increases with every fill.\nA client reconnecting with the Last-Event-ID header, or the last_event_id parameter, first receives the fills it missed that are still kept; the latest 1000 fills are kept.\nSubscribers that fall too far behind are disconnected and can resume the same way.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Stream Fills",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the last fill received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the last fill received, for clients that cannot set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Client Name",
+                        "name": "client_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exchange Name",
+                        "name": "exchange_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Pairs, repeated or comma-separated",
+                        "name": "pair",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Labels, repeated or comma-separated",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Algorithm Names, repeated or comma-separated",
+                        "name": "algorithm_name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.HistoryOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stream/orderbooks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Open a WebSocket streaming order books as entity.OrderBookMessage JSON messages: the latest stored snapshot of each book, then every snapshot saved after it.\nThe server pings every 30 seconds and disconnects subscribers that do not answer within 60 seconds.\nSubscribers that fall too far behind are disconnected with close code 1008 and reason \"slow consumer\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order"
+                ],
+                "summary": "Stream Order Books",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Books as exchange:pair, repeated or comma-separated, e.g. binance:BTC/USDT",
+                        "name": "book",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/entity.OrderBookMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Storage Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Storage Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entity.APIKey": {
+            "type": "object",
+            "properties": {
+                "client_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Scope"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AlgorithmPerformance": {
+            "type": "object",
+            "properties": {
+                "algorithm_name_placed": {
+                    "type": "string"
+                },
+                "avg_slippage_bps": {
+                    "type": "number"
+                },
+                "fees": {
+                    "type": "number"
+                },
+                "fill_count": {
+                    "type": "integer"
+                },
+                "net_pnl": {
+                    "type": "number"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "realized_pnl": {
+                    "type": "number"
+                },
+                "traded_notional": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.Check": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "entity.Client": {
+            "type": "object",
+            "properties": {
+                "client_name": {
+                    "type": "string"
+                },
+                "exchange_name": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "pair": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ClientAccount": {
+            "type": "object",
+            "properties": {
+                "client_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "exchange_name": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "pairs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CommissionReport": {
+            "type": "object",
+            "properties": {
+                "algorithm_name_placed": {
+                    "type": "string"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "commission": {
+                    "type": "number"
+                },
+                "exchange_name": {
+                    "type": "string"
+                },
+                "fee_rate_bps": {
+                    "type": "number"
+                },
+                "liquidity": {
+                    "type": "string"
+                },
+                "notional": {
+                    "type": "number"
+                },
+                "order_count": {
+                    "type": "integer"
+                },
+                "pair": {
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ConvertedCommission": {
+            "type": "object",
+            "properties": {
+                "commission": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "exchange_name": {
+                    "type": "string"
+                },
+                "fee_rate_bps": {
+                    "type": "number"
+                },
+                "fills": {
+                    "type": "integer"
+                },
+                "notional": {
+                    "type": "number"
+                },
+                "unpriced": {
+                    "type": "integer"
+                },
+                "unpriced_assets": {
+                    "description": "UnpricedAssets lists the assets that could not be valued.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entity.CreatedAPIKey": {
+            "type": "object",
+            "properties": {
+                "client_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Scope"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.DatabaseStatus": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "reachable": {
+                    "type": "boolean"
+                },
+                "tables": {
+                    "description": "Tables lists the tables of the database; it is empty if the database cannot be reached.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TableStatus"
+                    }
+                }
+            }
+        },
+        "entity.DepthOrder": {
+            "type": "object",
+            "properties": {
+                "base_qty": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.Distribution": {
+            "type": "object",
+            "properties": {
+                "histogram": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.HistogramBucket"
+                    }
+                },
+                "max": {
+                    "type": "number"
+                },
+                "mean": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "p50": {
+                    "type": "number"
+                },
+                "p90": {
+                    "type": "number"
+                },
+                "p95": {
+                    "type": "number"
+                },
+                "p99": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.ErrorBody": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is a machine-readable code such as not_found, validation_failed or client_exists.",
+                    "type": "string"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "RequestID is also sent in the X-Request-Id header and logged with server errors.",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/entity.ErrorBody"
+                }
+            }
+        },
+        "entity.FeeDiscrepancy": {
+            "type": "object",
+            "properties": {
+                "actual_commission": {
+                    "type": "number"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "dedup_key": {
+                    "type": "string"
+                },
+                "difference": {
+                    "type": "number"
+                },
+                "difference_bps": {
+                    "type": "number"
+                },
+                "exchange_name": {
+                    "type": "string"
+                },
+                "expected_commission": {
+                    "type": "number"
+                },
+                "liquidity": {
+                    "type": "string"
+                },
+                "notional": {
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "pair": {
+                    "type": "string"
+                },
+                "tier_volume": {
+                    "type": "number"
+                },
+                "time_placed": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.OrderType"
+                }
+            }
+        },
+        "entity.FeeReconciliation": {
+            "type": "object",
+            "properties": {
+                "discrepancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FeeDiscrepancy"
+                    }
+                },
+                "fills": {
+                    "description": "Fills counts the fills matching the filter, Unscheduled those of exchanges without a fee schedule\nand Unpriced those charged in an asset that could not be valued in quote.",
+                    "type": "integer"
+                },
+                "overcharged": {
+                    "description": "Overcharged and Undercharged total the differences of the discrepancies.",
+                    "type": "number"
+                },
+                "undercharged": {
+                    "type": "number"
+                },
+                "unpriced": {
+                    "type": "integer"
+                },
+                "unscheduled": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.FeeSchedule": {
+            "type": "object",
+            "properties": {
+                "client_name": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "exchange_name": {
+                    "type": "string"
+                },
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FeeTier"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "volume_window_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.FeeTier": {
+            "type": "object",
+            "properties": {
+                "maker_bps": {
+                    "type": "number"
+                },
+                "min_volume": {
+                    "type": "number"
+                },
+                "taker_bps": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.HistogramBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "lower_bps": {
+                    "type": "number"
+                },
+                "upper_bps": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.HistoryOrder": {
+            "type": "object",
+            "properties": {
+                "algorithm_name_placed": {
+                    "type": "string"
+                },
+                "base_qty": {
+                    "type": "number"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "commission_asset": {
+                    "type": "string"
+                },
+                "commission_qty": {
+                    "type": "number"
+                },
+                "commission_quote_qty": {
+                    "type": "number"
+                },
+                "dedup_key": {
+                    "type": "string"
+                },
+                "exchange_name": {
+                    "type": "string"
+                },
+                "exchange_order_id": {
+                    "type": "string"
+                },
+                "fill_id": {
+                    "type": "string"
+                },
+                "highest_buy_prc": {
+                    "type": "number"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "lowest_sell_prc": {
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "pair": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "side": {
+                    "$ref": "#/definitions/entity.Side"
+                },
+                "time_placed": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.OrderType"
+                }
+            }
+        },
+        "entity.HorizonMarkout": {
+            "type": "object",
+            "properties": {
+                "adverse_ratio": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "horizon": {
+                    "type": "string"
+                },
+                "mean_bps": {
+                    "type": "number"
+                },
+                "median_bps": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.IngestReply": {
+            "type": "object",
+            "properties": {
+                "books": {
+                    "type": "integer"
+                },
+                "error": {
+                    "$ref": "#/definitions/entity.ErrorBody"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "window": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.IngestStatus": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "pending": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.MarkoutAnalysis": {
+            "type": "object",
+            "properties": {
+                "algorithm_name_placed": {
+                    "type": "string"
+                },
+                "horizons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.HorizonMarkout"
+                    }
+                },
+                "order_count": {
+                    "type": "integer"
+                },
+                "pair": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OrderBook": {
+            "type": "object",
+            "properties": {
+                "asks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DepthOrder"
+                    }
+                },
+                "bids": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DepthOrder"
+                    }
+                },
+                "dedupKey": {
+                    "type": "string"
+                },
+                "exchange": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "pair": {
+                    "type": "string"
+                },
+                "sequence": {
+                    "type": "integer",
+                    "format": "int64"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OrderBookMessage": {
+            "type": "object",
+            "properties": {
+                "book": {
+                    "$ref": "#/definitions/entity.OrderBook"
+                },
+                "type": {
+                    "description": "Type is snapshot or update.",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OrderBookRequest": {
+            "type": "object",
+            "properties": {
+                "exchange": {
+                    "type": "string"
+                },
+                "pair": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OrderEvent": {
+            "type": "object",
+            "properties": {
+                "client_name": {
+                    "type": "string"
+                },
+                "event_time": {
+                    "type": "string"
+                },
+                "exchange_name": {
+                    "type": "string"
+                },
+                "exchange_order_id": {
+                    "type": "string"
+                },
+                "filled_qty": {
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "pair": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.OrderStatus"
+                }
+            }
+        },
+        "entity.OrderSlippage": {
+            "type": "object",
+            "properties": {
+                "algorithm_name_placed": {
+                    "type": "string"
+                },
+                "exchange_name": {
+                    "type": "string"
+                },
+                "mid_price": {
+                    "type": "number"
+                },
+                "mid_slippage_bps": {
+                    "type": "number"
+                },
+                "outside_spread": {
+                    "type": "boolean"
+                },
+                "pair": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "side": {
+                    "$ref": "#/definitions/entity.Side"
+                },
+                "time_placed": {
+                    "type": "string"
+                },
+                "touch_slippage_bps": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.OrderStatus": {
+            "type": "string",
+            "enum": [
+                "new",
+                "partially_filled",
+                "filled",
+                "cancelled",
+                "rejected",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "OrderStatusNew",
+                "OrderStatusPartiallyFilled",
+                "OrderStatusFilled",
+                "OrderStatusCancelled",
+                "OrderStatusRejected",
+                "OrderStatusExpired"
+            ]
+        },
+        "entity.OrderTimeline": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OrderEvent"
+                    }
+                },
+                "fills": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.HistoryOrder"
+                    }
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.OrderStatus"
+                }
+            }
+        },
+        "entity.OrderType": {
+            "type": "string",
+            "enum": [
+                "market",
+                "limit",
+                "stop",
+                "stop-limit",
+                "post-only",
+                "ioc",
+                "fok"
+            ],
+            "x-enum-varnames": [
+                "OrderTypeMarket",
+                "OrderTypeLimit",
+                "OrderTypeStop",
+                "OrderTypeStopLimit",
+                "OrderTypePostOnly",
+                "OrderTypeIOC",
+                "OrderTypeFOK"
+            ]
+        },
+        "entity.RateQuota": {
+            "type": "object",
+            "properties": {
+                "burst": {
+                    "type": "integer"
+                },
+                "key_id": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "route": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Readiness": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Check"
+                    }
+                },
+                "ready": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "entity.RiskBreach": {
+            "type": "object",
+            "properties": {
+                "client_name": {
+                    "type": "string"
+                },
+                "detected_at": {
+                    "type": "string"
+                },
+                "exchange_name": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "pair": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.RiskCheck": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean"
+                },
+                "breaches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.RiskBreach"
+                    }
+                },
+                "daily_notional": {
+                    "type": "number"
+                },
+                "limit": {
+                    "$ref": "#/definitions/entity.RiskLimit"
+                },
+                "mark_price": {
+                    "type": "number"
+                },
+                "order_notional": {
+                    "type": "number"
+                },
+                "pnl": {
+                    "type": "number"
+                },
+                "position": {
+                    "type": "number"
+                },
+                "projected_daily_notional": {
+                    "type": "number"
+                },
+                "projected_position": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.RiskCheckRequest": {
+            "type": "object",
+            "properties": {
+                "base_qty": {
+                    "type": "number"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "exchange_name": {
+                    "type": "string"
+                },
+                "pair": {
+                    "type": "string"
+                },
                 "price": {
                     "type": "number"
                 },
                 "side": {
+                    "$ref": "#/definitions/entity.Side"
+                }
+            }
+        },
+        "entity.RiskLimit": {
+            "type": "object",
+            "properties": {
+                "client_name": {
                     "type": "string"
                 },
-                "time_placed": {
+                "exchange_name": {
                     "type": "string"
                 },
-                "type": {
+                "max_daily_notional": {
+                    "type": "number"
+                },
+                "max_loss": {
+                    "type": "number"
+                },
+                "max_order_notional": {
+                    "type": "number"
+                },
+                "max_position": {
+                    "type": "number"
+                },
+                "pair": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "entity.OrderBook": {
+        "entity.SaveResult": {
             "type": "object",
             "properties": {
-                "asks": {
+                "duplicates": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.DepthOrder"
+                        "type": "string"
                     }
                 },
-                "bids": {
+                "replayed": {
+                    "type": "boolean"
+                },
+                "risk_breaches": {
+                    "description": "RiskBreaches lists the risk limits a saved fill exceeded.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.DepthOrder"
+                        "$ref": "#/definitions/entity.RiskBreach"
                     }
                 },
-                "exchange": {
+                "saved": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.Scope": {
+            "type": "string",
+            "enum": [
+                "books:read",
+                "books:write",
+                "history:read",
+                "history:write",
+                "admin"
+            ],
+            "x-enum-varnames": [
+                "ScopeReadBooks",
+                "ScopeWriteBooks",
+                "ScopeReadHistory",
+                "ScopeWriteHistory",
+                "ScopeAdmin"
+            ]
+        },
+        "entity.ServiceStatus": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "$ref": "#/definitions/entity.DatabaseStatus"
+                },
+                "ingest": {
+                    "$ref": "#/definitions/entity.IngestStatus"
+                },
+                "readiness": {
+                    "$ref": "#/definitions/entity.Readiness"
+                },
+                "started_at": {
                     "type": "string"
                 },
-                "id": {
+                "uptime_seconds": {
+                    "type": "number"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Side": {
+            "type": "string",
+            "enum": [
+                "buy",
+                "sell"
+            ],
+            "x-enum-varnames": [
+                "SideBuy",
+                "SideSell"
+            ]
+        },
+        "entity.SlippageAnalysis": {
+            "type": "object",
+            "properties": {
+                "algorithm_name_placed": {
+                    "type": "string"
+                },
+                "exchange_name": {
+                    "type": "string"
+                },
+                "mid_slippage": {
+                    "$ref": "#/definitions/entity.Distribution"
+                },
+                "order_count": {
+                    "type": "integer"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.OrderSlippage"
+                    }
+                },
+                "outside_spread_count": {
                     "type": "integer"
                 },
                 "pair": {
                     "type": "string"
+                },
+                "touch_slippage": {
+                    "$ref": "#/definitions/entity.Distribution"
                 }
             }
         },
-        "entity.OrderBookRequest": {
+        "entity.TCAReport": {
             "type": "object",
             "properties": {
-                "exchange": {
+                "arrival_price": {
+                    "type": "number"
+                },
+                "avg_buy_price": {
+                    "type": "number"
+                },
+                "avg_fill_price": {
+                    "type": "number"
+                },
+                "avg_sell_price": {
+                    "type": "number"
+                },
+                "buy_qty": {
+                    "type": "number"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "exchange_name": {
                     "type": "string"
                 },
+                "fees": {
+                    "type": "number"
+                },
+                "fees_bps": {
+                    "type": "number"
+                },
+                "fill_count": {
+                    "type": "integer"
+                },
+                "interval_twap": {
+                    "type": "number"
+                },
+                "interval_vwap": {
+                    "type": "number"
+                },
                 "pair": {
                     "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "participation": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "sell_qty": {
+                    "type": "number"
+                },
+                "shortfall_bps": {
+                    "type": "number"
+                },
+                "side": {
+                    "$ref": "#/definitions/entity.Side"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "total_cost_bps": {
+                    "type": "number"
+                },
+                "twap_slippage_bps": {
+                    "type": "number"
+                },
+                "vwap_slippage_bps": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.TableStatus": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.TradeBar": {
+            "type": "object",
+            "properties": {
+                "buy_volume": {
+                    "type": "number"
+                },
+                "close": {
+                    "type": "number"
+                },
+                "high": {
+                    "type": "number"
+                },
+                "low": {
+                    "type": "number"
+                },
+                "notional": {
+                    "type": "number"
+                },
+                "open": {
+                    "type": "number"
+                },
+                "sell_volume": {
+                    "type": "number"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "trade_count": {
+                    "type": "integer"
+                },
+                "volume": {
+                    "type": "number"
+                },
+                "vwap": {
+                    "type": "number"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "An API key or, when JWT_SECRET is set, a JWT, as \"Bearer \u003ctoken\u003e\".",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/egorque1/vortex-test/internal/db"
+	"github.com/egorque1/vortex-test/internal/importer"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+)
+
+const importUsage = `usage: vortex-test import -kind history|orderbook [flags] file...
+
+Imports history orders or order book snapshots from CSV or NDJSON files.
+CSV files need a header row. Column names are the JSON field names of the
+imported entity unless renamed with -map. Order book depth is a JSON array
+of {"price", "base_qty"} levels in the asks and bids columns.
+
+`
+
+/*
+runImport runs the import subcommand and returns the process exit code.
+A dry run validates the files without connecting to the database, so it does not check client accounts.
+*/
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), importUsage)
+		fs.PrintDefaults()
+	}
+
+	kind := fs.String("kind", "", "what the files hold: history or orderbook")
+	format := fs.String("format", "", "csv or ndjson, taken from the file extension when empty")
+	mapping := fs.String("map", "", "comma separated source=field column renames, e.g. qty=base_qty,ts=time_placed")
+	batchSize := fs.Int("batch", importer.DefaultBatchSize, "records saved per insert")
+	dryRun := fs.Bool("dry-run", false, "validate every record and report the invalid ones without saving")
+	resume := fs.Bool("resume", true, "keep a checkpoint next to each file and continue an interrupted import")
+	env := fs.String("env", ".env", "file with the ClickHouse connection settings")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *kind != importer.KindHistory && *kind != importer.KindOrderBook || fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	columns, err := parseMapping(*mapping)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var repo repository.OrderRepository
+	var clients service.ClientService
+	if !*dryRun {
+		database, err := db.Connect(*env)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to connect to database: %v\n", err)
+			return 1
+		}
+		if err := db.Migrate(database); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to migrate database: %v\n", err)
+			return 1
+		}
+		repo = repository.NewOrderRepository(database)
+		clients = service.NewClientService(repository.NewClientRepository(database))
+	}
+
+	imp := importer.New(repo, clients)
+	opts := importer.Options{
+		Kind:      *kind,
+		Format:    *format,
+		Mapping:   columns,
+		BatchSize: *batchSize,
+		DryRun:    *dryRun,
+		Resume:    *resume,
+		Progress:  os.Stderr,
+	}
+
+	code := 0
+	for _, path := range fs.Args() {
+		stats, err := imp.ImportFile(path, opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if stats.Invalid > 0 {
+			code = 1
+		}
+	}
+
+	return code
+}
+
+func parseMapping(s string) (map[string]string, error) {
+	mapping := map[string]string{}
+	if s == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		from, to, ok := strings.Cut(pair, "=")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid column mapping %q, expected source=field", pair)
+		}
+		mapping[strings.TrimSpace(from)] = strings.TrimSpace(to)
+	}
+	return mapping, nil
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// Cache is a concurrency-safe in-process map whose entries expire after a fixed TTL.
+type Cache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	items     map[string]entry
+	lastSweep time.Time
+}
+
+func New(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, items: make(map[string]entry), lastSweep: time.Now()}
+}
+
+/*
+Get returns the value stored under key.
+The second result is false if the key was never set or has expired.
+*/
+func (c *Cache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(c.items, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+/*
+Set stores value under key for the cache TTL.
+Expired entries are swept at most once per TTL.
+*/
+func (c *Cache) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.items[key] = entry{value: value, expiresAt: now.Add(c.ttl)}
+
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	for k, e := range c.items {
+		if now.After(e.expiresAt) {
+			delete(c.items, k)
+		}
+	}
+	c.lastSweep = now
+}
+
+// Delete removes key from the cache.
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, key)
+}
@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	c := New(50 * time.Millisecond)
+
+	_, ok := c.Get("key1")
+	assert.False(t, ok)
+
+	c.Set("key1", 1)
+	v, ok := c.Get("key1")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	time.Sleep(60 * time.Millisecond)
+
+	_, ok = c.Get("key1")
+	assert.False(t, ok)
+
+	c.Set("key2", 2)
+	assert.Len(t, c.items, 1)
+}
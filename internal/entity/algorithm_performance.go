@@ -0,0 +1,46 @@
+package entity
+
+const (
+	SortByFillCount      = "fill_count"
+	SortByTradedNotional = "traded_notional"
+	SortByRealizedPnL    = "realized_pnl"
+	SortByNetPnL         = "net_pnl"
+	SortBySlippage       = "avg_slippage_bps"
+	SortByFees           = "fees"
+)
+
+type AlgorithmPerformanceRequest struct {
+	HistoryFilter
+	SortBy string `json:"sort_by"`
+}
+
+type AlgorithmPerformance struct {
+	Rank                int     `json:"rank"`
+	AlgorithmNamePlaced string  `json:"algorithm_name_placed"`
+	FillCount           int     `json:"fill_count"`
+	TradedNotional      float64 `json:"traded_notional"`
+	RealizedPnL         float64 `json:"realized_pnl"`
+	Fees                float64 `json:"fees"`
+	NetPnL              float64 `json:"net_pnl"`
+	AvgSlippageBps      float64 `json:"avg_slippage_bps"`
+}
+
+/*
+Validate checks the time range and sort key.
+An empty sort key defaults to ranking by net PnL.
+*/
+func (r *AlgorithmPerformanceRequest) Validate() error {
+	if err := r.HistoryFilter.Validate(); err != nil {
+		return err
+	}
+
+	switch r.SortBy {
+	case "":
+		r.SortBy = SortByNetPnL
+	case SortByFillCount, SortByTradedNotional, SortByRealizedPnL, SortByNetPnL, SortBySlippage, SortByFees:
+	default:
+		return Invalid("sort_by", "unknown sort key %q", r.SortBy)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Scope is a group of endpoints an API key may call.
+type Scope string
+
+const (
+	ScopeReadBooks    Scope = "books:read"
+	ScopeWriteBooks   Scope = "books:write"
+	ScopeReadHistory  Scope = "history:read"
+	ScopeWriteHistory Scope = "history:write"
+	// ScopeAdmin allows every endpoint, including client, risk, fee and key management.
+	ScopeAdmin Scope = "admin"
+)
+
+var scopes = []Scope{ScopeReadBooks, ScopeWriteBooks, ScopeReadHistory, ScopeWriteHistory, ScopeAdmin}
+
+var ErrInvalidAPIKey error = &Error{Kind: ErrUnauthorized, Code: "invalid_api_key", Message: "a valid API key is required"}
+
+/*
+APIKey is a credential of a caller. Only the SHA-256 hash of its secret is stored.
+ClientNames restricts the history scopes to the fills of those clients; a key without listed clients may see every client.
+*/
+type APIKey struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	SecretHash  string     `json:"-"`
+	Scopes      []Scope    `json:"scopes" gorm:"serializer:json"`
+	ClientNames []string   `json:"client_names" gorm:"serializer:json"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Revoked     bool       `json:"-"`
+}
+
+// CreatedAPIKey is a newly created key together with the API key to present, which is not shown again.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// Validate checks that the key is named and has known scopes.
+func (k *APIKey) Validate() error {
+	if err := Required("name", k.Name); err != nil {
+		return err
+	}
+	if len(k.Scopes) == 0 {
+		return Invalid("scopes", "scopes must list at least one of %v", scopes)
+	}
+	for _, s := range k.Scopes {
+		if !slices.Contains(scopes, s) {
+			return Invalid("scopes", "scope %q is not one of %v", s, scopes)
+		}
+	}
+	return nil
+}
+
+// Expired reports whether the key has an expiry time before now.
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && now.After(*k.ExpiresAt)
+}
+
+// Has reports whether the key was granted scope. An admin key has every scope.
+func (k *APIKey) Has(scope Scope) bool {
+	return slices.Contains(k.Scopes, ScopeAdmin) || slices.Contains(k.Scopes, scope)
+}
+
+// AllClients reports whether the key may see the fills of every client.
+func (k *APIKey) AllClients() bool {
+	return len(k.ClientNames) == 0 || slices.Contains(k.Scopes, ScopeAdmin)
+}
+
+// Require checks that the key has scope, whatever clients it is restricted to. Returns an error of kind ErrForbidden if not.
+func (k *APIKey) Require(scope Scope) error {
+	if !k.Has(scope) {
+		return forbidden("scope %s is required", scope)
+	}
+	return nil
+}
+
+/*
+Allow checks that the key has scope and, if clientName is not empty, may act for that client.
+An empty clientName stands for every client, which only a key without listed clients may ask for.
+Returns an error of kind ErrForbidden otherwise.
+*/
+func (k *APIKey) Allow(scope Scope, clientName string) error {
+	if err := k.Require(scope); err != nil {
+		return err
+	}
+	if k.AllClients() {
+		return nil
+	}
+	if clientName == "" {
+		return forbidden("the API key is restricted to clients %v, name one of them", k.ClientNames)
+	}
+	if !slices.Contains(k.ClientNames, clientName) {
+		return forbidden("the API key does not allow client %s", clientName)
+	}
+	return nil
+}
+
+func forbidden(format string, args ...any) error {
+	return &Error{Kind: ErrForbidden, Code: "forbidden", Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+var (
+	ErrClientExists       error = &Error{Kind: ErrConflict, Code: "client_exists", Message: "client account already exists"}
+	ErrUnregisteredClient error = &Error{Kind: ErrUnprocessable, Code: "unregistered_client", Message: "client account is not registered"}
+)
+
+type ClientAccount struct {
+	ClientName   string    `json:"client_name"`
+	ExchangeName string    `json:"exchange_name"`
+	Label        string    `json:"label"`
+	Pairs        []string  `json:"pairs" gorm:"serializer:json"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Deleted      bool      `json:"-"`
+}
+
+// Validate checks that the account is fully identified.
+func (a *ClientAccount) Validate() error {
+	return Required("client_name", a.ClientName, "exchange_name", a.ExchangeName, "label", a.Label)
+}
+
+/*
+AllowsPair reports whether the account may trade pair, matching the listed pairs regardless of case and separator,
+so BTC/USDT allows btc-usdt. An account without listed pairs allows any.
+*/
+func (a *ClientAccount) AllowsPair(pair string) bool {
+	if len(a.Pairs) == 0 {
+		return true
+	}
+	base, quote, ok := SplitPair(pair)
+	for _, p := range a.Pairs {
+		if b, q, pOK := SplitPair(p); ok && pOK {
+			if b == base && q == quote {
+				return true
+			}
+		} else if strings.EqualFold(p, pair) {
+			return true
+		}
+	}
+	return false
+}
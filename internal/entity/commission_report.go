@@ -0,0 +1,76 @@
+package entity
+
+import "time"
+
+const (
+	ReportPeriodDay   = "day"
+	ReportPeriodMonth = "month"
+)
+
+// Dimensions a commission report can be grouped by. They match the column names
+// of the commission_daily table.
+var CommissionReportDimensions = []string{
+	"client_name",
+	"exchange_name",
+	"pair",
+	"algorithm_name_placed",
+	"liquidity",
+}
+
+type CommissionReportRequest struct {
+	ClientName          string    `json:"client_name"`
+	ExchangeName        string    `json:"exchange_name"`
+	Pair                string    `json:"pair"`
+	AlgorithmNamePlaced string    `json:"algorithm_name_placed"`
+	From                time.Time `json:"from"`
+	To                  time.Time `json:"to"`
+	Period              string    `json:"period"`
+	GroupBy             []string  `json:"group_by"`
+}
+
+type CommissionReport struct {
+	Period              time.Time `json:"period"`
+	ClientName          string    `json:"client_name,omitempty"`
+	ExchangeName        string    `json:"exchange_name,omitempty"`
+	Pair                string    `json:"pair,omitempty"`
+	AlgorithmNamePlaced string    `json:"algorithm_name_placed,omitempty"`
+	Liquidity           string    `json:"liquidity,omitempty"`
+	OrderCount          uint64    `json:"order_count"`
+	Notional            float64   `json:"notional"`
+	Commission          float64   `json:"commission"`
+	FeeRateBps          float64   `json:"fee_rate_bps" gorm:"-"`
+}
+
+/*
+Validate checks the report period and grouping dimensions.
+An empty period defaults to a daily report.
+*/
+func (r *CommissionReportRequest) Validate() error {
+	if r.Period == "" {
+		r.Period = ReportPeriodDay
+	}
+	if r.Period != ReportPeriodDay && r.Period != ReportPeriodMonth {
+		return Invalid("period", "unknown report period %q", r.Period)
+	}
+
+	for _, dim := range r.GroupBy {
+		if !isCommissionReportDimension(dim) {
+			return Invalid("group_by", "unknown group_by dimension %q", dim)
+		}
+	}
+
+	if !r.From.IsZero() && !r.To.IsZero() && r.To.Before(r.From) {
+		return Invalid("to", "report range ends before it starts")
+	}
+
+	return nil
+}
+
+func isCommissionReportDimension(dim string) bool {
+	for _, d := range CommissionReportDimensions {
+		if d == dim {
+			return true
+		}
+	}
+	return false
+}
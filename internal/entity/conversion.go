@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// ErrNoConversionRate is returned when no stored order book prices an asset in the requested currency.
+var ErrNoConversionRate error = &Error{Kind: ErrUnprocessable, Code: "no_conversion_rate", Message: "no conversion rate"}
+
+/*
+Conversion is a quantity of an asset to value in a currency when a fill on an exchange was placed.
+The assets of Pair, if set, are tried first as bridges between the asset and the currency.
+Converting sets Value, or Unpriced when no stored order book prices the asset.
+*/
+type Conversion struct {
+	ExchangeName string
+	Pair         string
+	Asset        string
+	Currency     string
+	Qty          float64
+	At           time.Time
+	Value        float64
+	Unpriced     bool
+}
+
+type CommissionConversionRequest struct {
+	HistoryFilter
+	// Currency is the asset commissions and notionals are valued in, such as USDT.
+	Currency string `json:"currency"`
+}
+
+// Validate checks the time range and that a reporting currency is given.
+func (r *CommissionConversionRequest) Validate() error {
+	if err := r.HistoryFilter.Validate(); err != nil {
+		return err
+	}
+	if err := Required("currency", r.Currency); err != nil {
+		return err
+	}
+	r.Currency = strings.ToUpper(r.Currency)
+	return nil
+}
+
+/*
+ConvertedCommission totals the commissions an exchange charged, valued in a single currency
+at the order book mid prices when each fill was placed.
+Fills whose commission or notional cannot be valued are counted as unpriced and left out of the totals.
+*/
+type ConvertedCommission struct {
+	ExchangeName string  `json:"exchange_name"`
+	Currency     string  `json:"currency"`
+	Fills        int     `json:"fills"`
+	Unpriced     int     `json:"unpriced"`
+	Notional     float64 `json:"notional"`
+	Commission   float64 `json:"commission"`
+	FeeRateBps   float64 `json:"fee_rate_bps"`
+	// UnpricedAssets lists the assets that could not be valued.
+	UnpricedAssets []string `json:"unpriced_assets"`
+}
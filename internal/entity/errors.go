@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*
+Kinds of errors the repositories and services return. Every error they raise for a reason
+the caller can act on matches one of them with errors.Is, whatever its detail.
+*/
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrValidation    = errors.New("validation failed")
+	ErrConflict      = errors.New("conflict")
+	ErrUnprocessable = errors.New("unprocessable")
+	ErrUnavailable   = errors.New("storage unavailable")
+	ErrTimeout       = errors.New("timeout")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrForbidden     = errors.New("forbidden")
+)
+
+/*
+Error is a domain error of one kind with a machine-readable code, and the error that caused it if any.
+It matches its kind and, through Unwrap, its cause with errors.Is.
+*/
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// FieldError is a problem with one field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists the fields of a request that are missing or malformed. It matches ErrValidation.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Invalid returns a validation error for a single field.
+func Invalid(field, format string, args ...any) error {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: fmt.Sprintf(format, args...)}}}
+}
+
+/*
+Required takes pairs of field names and values and returns a validation error listing the fields left empty,
+or nil if every field is set.
+*/
+func Required(fieldsAndValues ...string) error {
+	var missing []FieldError
+	for i := 0; i+1 < len(fieldsAndValues); i += 2 {
+		if fieldsAndValues[i+1] == "" {
+			missing = append(missing, FieldError{Field: fieldsAndValues[i], Message: fieldsAndValues[i] + " is required"})
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: missing}
+}
+
+// ErrorResponse is the body of every error response of the API.
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	// Code is a machine-readable code such as not_found, validation_failed or client_exists.
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+	// RequestID is also sent in the X-Request-Id header and logged with server errors.
+	RequestID string `json:"request_id"`
+}
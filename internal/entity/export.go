@@ -0,0 +1,54 @@
+package entity
+
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatParquet = "parquet"
+)
+
+// Order book export layouts: one row per snapshot with a column group per depth level,
+// or one row per price level.
+const (
+	BookLayoutLevels = "levels"
+	BookLayoutRows   = "rows"
+)
+
+// DefaultExportDepth is the number of levels per side flattened into columns when no depth is given.
+const DefaultExportDepth = 10
+
+// maxExportDepth bounds the number of columns of the levels layout.
+const maxExportDepth = 100
+
+type ExportOptions struct {
+	Format string
+	Layout string
+	Depth  int
+}
+
+/*
+Validate checks the export format and order book layout.
+An empty format defaults to CSV, an empty layout to levels and a zero depth to DefaultExportDepth.
+*/
+func (o *ExportOptions) Validate() error {
+	if o.Format == "" {
+		o.Format = ExportFormatCSV
+	}
+	if o.Format != ExportFormatCSV && o.Format != ExportFormatParquet {
+		return Invalid("format", "unknown export format %q", o.Format)
+	}
+
+	if o.Layout == "" {
+		o.Layout = BookLayoutLevels
+	}
+	if o.Layout != BookLayoutLevels && o.Layout != BookLayoutRows {
+		return Invalid("layout", "unknown order book layout %q", o.Layout)
+	}
+
+	if o.Depth == 0 {
+		o.Depth = DefaultExportDepth
+	}
+	if o.Depth < 0 || o.Depth > maxExportDepth {
+		return Invalid("depth", "depth must be between 1 and %d", maxExportDepth)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	LiquidityMaker   = "maker"
+	LiquidityTaker   = "taker"
+	LiquidityUnknown = "unknown"
+)
+
+// DefaultFeeVolumeWindowDays is the trailing window the volume of a fee tier is measured over when none is set.
+const DefaultFeeVolumeWindowDays = 30
+
+// DefaultFeeToleranceBps is the difference from the expected commission, in basis points of notional, still counted as a match.
+const DefaultFeeToleranceBps = 0.1
+
+/*
+Liquidity infers whether an order of this type added or took liquidity.
+Market, IOC and FOK orders always take and post-only orders always make; other types could do either.
+*/
+func (t OrderType) Liquidity() string {
+	switch t {
+	case OrderTypeMarket, OrderTypeIOC, OrderTypeFOK:
+		return LiquidityTaker
+	case OrderTypePostOnly:
+		return LiquidityMaker
+	default:
+		return LiquidityUnknown
+	}
+}
+
+// FeeTier applies from a trailing traded volume, in USDT, up to the next tier. A negative rate is a rebate.
+type FeeTier struct {
+	MinVolume float64 `json:"min_volume"`
+	MakerBps  float64 `json:"maker_bps"`
+	TakerBps  float64 `json:"taker_bps"`
+}
+
+/*
+FeeSchedule holds the fee tiers of an exchange from EffectiveFrom until the next schedule takes effect.
+A schedule with a client name overrides the exchange's schedule for that client.
+*/
+type FeeSchedule struct {
+	ExchangeName     string    `json:"exchange_name"`
+	ClientName       string    `json:"client_name"`
+	EffectiveFrom    time.Time `json:"effective_from"`
+	VolumeWindowDays int       `json:"volume_window_days"`
+	Tiers            []FeeTier `json:"tiers" gorm:"serializer:json"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+/*
+Validate checks that the schedule names its exchange and that its tiers start at zero volume in ascending order.
+A zero volume window defaults to DefaultFeeVolumeWindowDays.
+*/
+func (s *FeeSchedule) Validate() error {
+	if s.ExchangeName == "" {
+		return Required("exchange_name", s.ExchangeName)
+	}
+	if s.VolumeWindowDays == 0 {
+		s.VolumeWindowDays = DefaultFeeVolumeWindowDays
+	}
+	if s.VolumeWindowDays < 0 {
+		return Invalid("volume_window_days", "volume_window_days must not be negative")
+	}
+
+	if len(s.Tiers) == 0 {
+		return Invalid("tiers", "at least one tier is required")
+	}
+	if s.Tiers[0].MinVolume != 0 {
+		return Invalid("tiers[0].min_volume", "the first tier must start at zero volume")
+	}
+	for i := 1; i < len(s.Tiers); i++ {
+		if s.Tiers[i].MinVolume <= s.Tiers[i-1].MinVolume {
+			return Invalid(fmt.Sprintf("tiers[%d].min_volume", i), "tiers must be in ascending order of min_volume")
+		}
+	}
+
+	return nil
+}
+
+// Tier returns the tier in effect at a trailing volume.
+func (s *FeeSchedule) Tier(volume float64) FeeTier {
+	tier := s.Tiers[0]
+	for _, t := range s.Tiers[1:] {
+		if volume < t.MinVolume {
+			break
+		}
+		tier = t
+	}
+	return tier
+}
+
+type FeeReconciliationRequest struct {
+	HistoryFilter
+	// ToleranceBps is the difference from the expected commission still counted as a match.
+	ToleranceBps float64 `json:"tolerance_bps"`
+}
+
+// Validate checks the time range and the tolerance. A zero tolerance defaults to DefaultFeeToleranceBps.
+func (r *FeeReconciliationRequest) Validate() error {
+	if err := r.HistoryFilter.Validate(); err != nil {
+		return err
+	}
+	if r.ToleranceBps == 0 {
+		r.ToleranceBps = DefaultFeeToleranceBps
+	}
+	if r.ToleranceBps < 0 {
+		return Invalid("tolerance_bps", "tolerance_bps must not be negative")
+	}
+	return nil
+}
+
+/*
+FeeDiscrepancy is a fill charged a commission outside of what its fee schedule allows.
+When the liquidity of the fill is unknown any commission between the maker and taker rate is allowed,
+and the expected commission is the bound closest to the one charged.
+A positive difference is an overcharge.
+*/
+type FeeDiscrepancy struct {
+	OrderID            string    `json:"order_id"`
+	DedupKey           string    `json:"dedup_key"`
+	ClientName         string    `json:"client_name"`
+	ExchangeName       string    `json:"exchange_name"`
+	Pair               string    `json:"pair"`
+	Type               OrderType `json:"type"`
+	Liquidity          string    `json:"liquidity"`
+	TimePlaced         time.Time `json:"time_placed"`
+	Notional           float64   `json:"notional"`
+	TierVolume         float64   `json:"tier_volume"`
+	ExpectedCommission float64   `json:"expected_commission"`
+	ActualCommission   float64   `json:"actual_commission"`
+	Difference         float64   `json:"difference"`
+	DifferenceBps      float64   `json:"difference_bps"`
+}
+
+type FeeReconciliation struct {
+	// Fills counts the fills matching the filter, Unscheduled those of exchanges without a fee schedule
+	// and Unpriced those charged in an asset that could not be valued in quote.
+	Fills       int `json:"fills"`
+	Unscheduled int `json:"unscheduled"`
+	Unpriced    int `json:"unpriced"`
+	// Overcharged and Undercharged total the differences of the discrepancies.
+	Overcharged   float64           `json:"overcharged"`
+	Undercharged  float64           `json:"undercharged"`
+	Discrepancies []*FeeDiscrepancy `json:"discrepancies"`
+}
@@ -0,0 +1,8 @@
+package entity
+
+// FillEvent is a saved fill as sent on the fills stream.
+// IDs are the epoch of the process in the upper 32 bits and increase with every fill it saves.
+type FillEvent struct {
+	ID    uint64
+	Order *HistoryOrder
+}
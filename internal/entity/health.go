@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// Check is the outcome of one readiness check, with the reason it failed if it did.
+type Check struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+}
+
+// Readiness tells an orchestrator whether the service can take traffic: it is ready if every check passed.
+type Readiness struct {
+	Ready  bool    `json:"ready"`
+	Checks []Check `json:"checks"`
+}
+
+// TableStatus is the number of rows of a table, as ClickHouse last counted them.
+type TableStatus struct {
+	Name string `json:"name"`
+	Rows uint64 `json:"rows"`
+}
+
+// Column is a column of a table with its ClickHouse type.
+type Column struct {
+	Table string `json:"table"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+}
+
+type DatabaseStatus struct {
+	Reachable bool    `json:"reachable"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	// Tables lists the tables of the database; it is empty if the database cannot be reached.
+	Tables []TableStatus `json:"tables"`
+}
+
+// IngestStatus is the number of order books collectors have sent that are not saved yet, and how many are allowed before the service is not ready.
+type IngestStatus struct {
+	Pending  int `json:"pending"`
+	Capacity int `json:"capacity"`
+}
+
+// ServiceStatus describes the running service and its dependencies in detail.
+type ServiceStatus struct {
+	Version       string         `json:"version"`
+	StartedAt     time.Time      `json:"started_at"`
+	UptimeSeconds float64        `json:"uptime_seconds"`
+	Readiness     Readiness      `json:"readiness"`
+	Database      DatabaseStatus `json:"database"`
+	Ingest        IngestStatus   `json:"ingest"`
+}
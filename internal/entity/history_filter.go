@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"slices"
+	"time"
+)
+
+type HistoryFilter struct {
+	ClientName     string    `json:"client_name"`
+	ExchangeName   string    `json:"exchange_name"`
+	Pairs          []string  `json:"pairs"`
+	Labels         []string  `json:"labels"`
+	AlgorithmNames []string  `json:"algorithm_names"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+}
+
+/*
+Validate checks that the filter time range is well-formed.
+Both ends are optional.
+*/
+func (f *HistoryFilter) Validate() error {
+	if !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+		return Invalid("to", "time range ends before it starts")
+	}
+	return nil
+}
+
+// Matches reports whether a fill passes the filter, as the query of the history repository would select it.
+func (f *HistoryFilter) Matches(o *HistoryOrder) bool {
+	switch {
+	case f.ClientName != "" && o.ClientName != f.ClientName,
+		f.ExchangeName != "" && o.ExchangeName != f.ExchangeName,
+		len(f.Pairs) > 0 && !slices.Contains(f.Pairs, o.Pair),
+		len(f.Labels) > 0 && !slices.Contains(f.Labels, o.Label),
+		len(f.AlgorithmNames) > 0 && !slices.Contains(f.AlgorithmNames, o.AlgorithmNamePlaced),
+		!f.From.IsZero() && o.TimePlaced.Before(f.From),
+		!f.To.IsZero() && o.TimePlaced.After(f.To):
+		return false
+	}
+	return true
+}
@@ -1,19 +1,122 @@
 package entity
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type HistoryOrder struct {
+	OrderID             string    `json:"order_id"`
+	ExchangeOrderID     string    `json:"exchange_order_id"`
+	FillID              string    `json:"fill_id"`
+	DedupKey            string    `json:"dedup_key"`
 	ClientName          string    `json:"client_name"`
 	ExchangeName        string    `json:"exchange_name"`
 	Label               string    `json:"label"`
 	Pair                string    `json:"pair"`
-	Side                string    `json:"side"`
-	Type                string    `json:"type"`
+	Side                Side      `json:"side"`
+	Type                OrderType `json:"type"`
 	BaseQty             float64   `json:"base_qty"`
 	Price               float64   `json:"price"`
 	AlgorithmNamePlaced string    `json:"algorithm_name_placed"`
 	LowestSellPrc       float64   `json:"lowest_sell_prc"`
 	HighestBuyPrc       float64   `json:"highest_buy_prc"`
 	CommissionQuoteQty  float64   `json:"commission_quote_qty"`
+	CommissionAsset     string    `json:"commission_asset"`
+	CommissionQty       float64   `json:"commission_qty"`
 	TimePlaced          time.Time `json:"time_placed"`
 }
+
+/*
+NaturalKey returns the key identifying this fill across retries: the explicit dedup key if set,
+otherwise the order and fill IDs. It is empty when the fill cannot be identified.
+*/
+func (o *HistoryOrder) NaturalKey() string {
+	if o.DedupKey != "" {
+		return o.DedupKey
+	}
+	if o.OrderID == "" || o.FillID == "" {
+		return ""
+	}
+	return o.OrderID + ":" + o.FillID
+}
+
+// Validate checks that the order has a side and an order type, and names the asset of a commission quantity.
+func (o *HistoryOrder) Validate() error {
+	if err := Required("side", string(o.Side), "type", string(o.Type)); err != nil {
+		return err
+	}
+	if o.CommissionQty != 0 && o.CommissionAsset == "" {
+		return Invalid("commission_asset", "commission_asset is required with commission_qty")
+	}
+	return nil
+}
+
+/*
+Commission returns the asset the commission was charged in and its quantity.
+Orders without a commission asset were charged CommissionQuoteQty in the quote asset of the pair.
+*/
+func (o *HistoryOrder) Commission() (string, float64) {
+	if o.CommissionAsset != "" {
+		return strings.ToUpper(o.CommissionAsset), o.CommissionQty
+	}
+	_, quote, _ := SplitPair(o.Pair)
+	return quote, o.CommissionQuoteQty
+}
+
+// HasQuoteCommission reports whether CommissionQuoteQty holds the whole commission valued in the quote asset.
+func (o *HistoryOrder) HasQuoteCommission() bool {
+	if o.CommissionAsset == "" {
+		return true
+	}
+	base, quote, _ := SplitPair(o.Pair)
+	asset := strings.ToUpper(o.CommissionAsset)
+	return asset == quote || asset == base
+}
+
+/*
+NormalizeCommission fills CommissionQuoteQty from a commission charged in the base or quote asset of the pair,
+valuing base at the fill price. A quote quantity that was set explicitly is kept.
+Commissions in other assets are left to be valued from order book prices when reported.
+*/
+func (o *HistoryOrder) NormalizeCommission() {
+	if o.CommissionAsset == "" || o.CommissionQuoteQty != 0 {
+		return
+	}
+
+	base, quote, ok := SplitPair(o.Pair)
+	if !ok {
+		return
+	}
+	switch strings.ToUpper(o.CommissionAsset) {
+	case quote:
+		o.CommissionQuoteQty = o.CommissionQty
+	case base:
+		o.CommissionQuoteQty = o.CommissionQty * o.Price
+	}
+}
+
+/*
+SplitPair returns the upper-cased base and quote assets of a pair written as BASE/QUOTE, BASE-QUOTE or BASE_QUOTE.
+The last result is false when the pair has no separator.
+*/
+func SplitPair(pair string) (string, string, bool) {
+	i := strings.IndexAny(pair, "/-_")
+	if i <= 0 || i == len(pair)-1 {
+		return "", "", false
+	}
+	return strings.ToUpper(pair[:i]), strings.ToUpper(pair[i+1:]), true
+}
+
+/*
+PairSpellings returns the ways a pair of base and quote may be stored: with each separator SplitPair accepts,
+in upper and in lower case, so stored pairs can be matched as they are without changing their case in the query.
+*/
+func PairSpellings(base, quote string) []string {
+	spellings := make([]string, 0, 6)
+	for _, sep := range []string{"/", "-", "_"} {
+		pair := strings.ToUpper(base) + sep + strings.ToUpper(quote)
+		spellings = append(spellings, pair, strings.ToLower(pair))
+	}
+	return spellings
+}
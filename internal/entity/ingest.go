@@ -0,0 +1,126 @@
+package entity
+
+import (
+	"sort"
+	"time"
+)
+
+// Types of the messages a collector sends on the ingestion channel.
+const (
+	IngestSnapshot = "snapshot"
+	IngestDelta    = "delta"
+)
+
+// Types of the messages the server sends on the ingestion channel.
+const (
+	IngestWelcome = "welcome"
+	IngestAck     = "ack"
+	IngestNack    = "nack"
+)
+
+/*
+IngestMessage is a message a collector sends on the ingestion channel: full order book snapshots,
+or a delta to the latest book of a pair sent on the same connection. ID is echoed in its ack or nack.
+*/
+type IngestMessage struct {
+	ID    uint64          `json:"id"`
+	Type  string          `json:"type"`
+	Books []*OrderBook    `json:"books,omitempty"`
+	Delta *OrderBookDelta `json:"delta,omitempty"`
+}
+
+/*
+OrderBookDelta holds the price levels of a book that changed. A level replaces the level at the same price,
+and a level with no quantity removes it. Sequence, when set, must increase.
+*/
+type OrderBookDelta struct {
+	Exchange  string
+	Pair      string
+	Asks      []DepthOrder
+	Bids      []DepthOrder
+	Timestamp time.Time
+	Sequence  int64
+}
+
+/*
+IngestReply is a message the server sends on the ingestion channel. The welcome message gives the window,
+the number of messages a collector may send before waiting for their acks. An ack tells the books of a message
+are stored, or were already; a nack tells they were not, with the reason.
+*/
+type IngestReply struct {
+	Type   string     `json:"type"`
+	ID     uint64     `json:"id,omitempty"`
+	Books  int        `json:"books,omitempty"`
+	Window int        `json:"window,omitempty"`
+	Error  *ErrorBody `json:"error,omitempty"`
+}
+
+// Validate checks that the message carries what its type needs.
+func (m *IngestMessage) Validate() error {
+	switch m.Type {
+	case IngestSnapshot:
+		if len(m.Books) == 0 {
+			return Required("books", "")
+		}
+		for _, b := range m.Books {
+			if b == nil {
+				return Invalid("books", "books must not be null")
+			}
+			if err := Required("books.Exchange", b.Exchange, "books.Pair", b.Pair); err != nil {
+				return err
+			}
+		}
+	case IngestDelta:
+		if m.Delta == nil {
+			return Required("delta", "")
+		}
+		return Required("delta.Exchange", m.Delta.Exchange, "delta.Pair", m.Delta.Pair)
+	default:
+		return Invalid("type", "type must be %s or %s", IngestSnapshot, IngestDelta)
+	}
+	return nil
+}
+
+/*
+Apply returns the book that results from applying d to b, with the levels of each side ordered best first.
+Returns a validation error if d is not newer than b by sequence number.
+*/
+func (b *OrderBook) Apply(d *OrderBookDelta) (*OrderBook, error) {
+	if d.Sequence != 0 && d.Sequence <= b.Sequence {
+		return nil, Invalid("delta.Sequence", "delta sequence %d does not follow book sequence %d", d.Sequence, b.Sequence)
+	}
+
+	asks := applyLevels(b.Asks, d.Asks)
+	sort.Slice(asks, func(i, j int) bool { return asks[i].Price < asks[j].Price })
+	bids := applyLevels(b.Bids, d.Bids)
+	sort.Slice(bids, func(i, j int) bool { return bids[i].Price > bids[j].Price })
+
+	return &OrderBook{
+		Exchange:  b.Exchange,
+		Pair:      b.Pair,
+		Asks:      asks,
+		Bids:      bids,
+		Timestamp: d.Timestamp,
+		Sequence:  d.Sequence,
+	}, nil
+}
+
+func applyLevels(levels, changes []DepthOrder) []DepthOrder {
+	byPrice := make(map[float64]float64, len(levels)+len(changes))
+	for _, l := range levels {
+		byPrice[l.Price] = l.BaseQty
+	}
+	for _, c := range changes {
+		if c.BaseQty == 0 {
+			delete(byPrice, c.Price)
+			continue
+		}
+		byPrice[c.Price] = c.BaseQty
+	}
+
+	result := make([]DepthOrder, 0, len(byPrice))
+	for price, qty := range byPrice {
+		result = append(result, DepthOrder{Price: price, BaseQty: qty})
+	}
+	return result
+}
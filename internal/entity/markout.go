@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+var DefaultMarkoutHorizons = []string{"1s", "10s", "1m", "5m"}
+
+type MarkoutRequest struct {
+	HistoryFilter
+	Horizons  []string        `json:"horizons"`
+	Durations []time.Duration `json:"-"`
+}
+
+type HorizonMarkout struct {
+	Horizon      string  `json:"horizon"`
+	Count        int     `json:"count"`
+	MeanBps      float64 `json:"mean_bps"`
+	MedianBps    float64 `json:"median_bps"`
+	AdverseRatio float64 `json:"adverse_ratio"`
+}
+
+type MarkoutAnalysis struct {
+	Pair                string           `json:"pair"`
+	AlgorithmNamePlaced string           `json:"algorithm_name_placed"`
+	OrderCount          int              `json:"order_count"`
+	Horizons            []HorizonMarkout `json:"horizons"`
+}
+
+/*
+Validate checks the time range and parses the markout horizons into Durations.
+An empty horizon list defaults to DefaultMarkoutHorizons.
+*/
+func (r *MarkoutRequest) Validate() error {
+	if err := r.HistoryFilter.Validate(); err != nil {
+		return err
+	}
+
+	if len(r.Horizons) == 0 {
+		r.Horizons = DefaultMarkoutHorizons
+	}
+
+	r.Durations = make([]time.Duration, 0, len(r.Horizons))
+	for _, h := range r.Horizons {
+		d, err := time.ParseDuration(h)
+		if err != nil {
+			return Invalid("horizons", "invalid horizon %q: %v", h, err)
+		}
+		if d <= 0 {
+			return Invalid("horizons", "horizon %q must be positive", h)
+		}
+		r.Durations = append(r.Durations, d)
+	}
+
+	return nil
+}
@@ -3,22 +3,78 @@ package entity
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 type OrderBook struct {
-	ID       int64
-	Exchange string
-	Pair     string
-	Asks     []DepthOrder
-	Bids     []DepthOrder
+	ID        int64
+	Exchange  string
+	Pair      string
+	Asks      []DepthOrder
+	Bids      []DepthOrder
+	Timestamp time.Time
+	Sequence  int64
+	DedupKey  string
+}
+
+// BookMid is the mid price of the order book snapshot taken at Timestamp, looked up as of At.
+type BookMid struct {
+	At        time.Time
+	Exchange  string
+	Pair      string
+	Timestamp time.Time
+	Mid       float64
 }
 
 type OrderBookDTO struct {
-	ID       int64  `json:"id"`
-	Exchange string `json:"exchange"`
-	Pair     string `json:"pair"`
-	Asks     string `json:"asks"`
-	Bids     string `json:"bids"`
+	ID        int64     `json:"id"`
+	Exchange  string    `json:"exchange"`
+	Pair      string    `json:"pair"`
+	Asks      string    `json:"asks"`
+	Bids      string    `json:"bids"`
+	Timestamp time.Time `json:"timestamp"`
+	Sequence  int64     `json:"sequence"`
+	DedupKey  string    `json:"dedup_key"`
+}
+
+/*
+NaturalKey returns the key identifying this snapshot across retries: the explicit dedup key if set,
+otherwise the exchange, pair and sequence number. It is empty when the snapshot has no sequence.
+*/
+func (b *OrderBook) NaturalKey() string {
+	if b.DedupKey != "" {
+		return b.DedupKey
+	}
+	if b.Sequence == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s:%d", b.Exchange, b.Pair, b.Sequence)
+}
+
+/*
+MidPrice returns the midpoint between the best ask and the best bid.
+The second result is false when either side of the book is empty.
+*/
+func (b *OrderBook) MidPrice() (float64, bool) {
+	if len(b.Asks) == 0 || len(b.Bids) == 0 {
+		return 0, false
+	}
+
+	bestAsk := b.Asks[0].Price
+	for _, a := range b.Asks[1:] {
+		if a.Price < bestAsk {
+			bestAsk = a.Price
+		}
+	}
+
+	bestBid := b.Bids[0].Price
+	for _, bid := range b.Bids[1:] {
+		if bid.Price > bestBid {
+			bestBid = bid.Price
+		}
+	}
+
+	return (bestAsk + bestBid) / 2, true
 }
 
 func ToOrderBookDTO(orderBook *OrderBook) (*OrderBookDTO, error) {
@@ -33,11 +89,14 @@ func ToOrderBookDTO(orderBook *OrderBook) (*OrderBookDTO, error) {
 	}
 
 	return &OrderBookDTO{
-		ID:       orderBook.ID,
-		Exchange: orderBook.Exchange,
-		Pair:     orderBook.Pair,
-		Asks:     string(asksJSON), // Строковое представление JSON данных
-		Bids:     string(bidsJSON), // Строковое представление JSON данных
+		ID:        orderBook.ID,
+		Exchange:  orderBook.Exchange,
+		Pair:      orderBook.Pair,
+		Asks:      string(asksJSON), // Строковое представление JSON данных
+		Bids:      string(bidsJSON), // Строковое представление JSON данных
+		Timestamp: orderBook.Timestamp,
+		Sequence:  orderBook.Sequence,
+		DedupKey:  orderBook.DedupKey,
 	}, nil
 }
 
@@ -53,10 +112,13 @@ func ToOrderBookEntity(dto *OrderBookDTO) (*OrderBook, error) {
 	}
 
 	return &OrderBook{
-		ID:       dto.ID,
-		Exchange: dto.Exchange,
-		Pair:     dto.Pair,
-		Asks:     asks,
-		Bids:     bids,
+		ID:        dto.ID,
+		Exchange:  dto.Exchange,
+		Pair:      dto.Pair,
+		Asks:      asks,
+		Bids:      bids,
+		Timestamp: dto.Timestamp,
+		Sequence:  dto.Sequence,
+		DedupKey:  dto.DedupKey,
 	}, nil
 }
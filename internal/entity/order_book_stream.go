@@ -0,0 +1,97 @@
+package entity
+
+import "strings"
+
+// maxStreamBooks is the number of books one stream may subscribe to.
+const maxStreamBooks = 50
+
+const (
+	OrderBookSnapshot = "snapshot"
+	OrderBookUpdate   = "update"
+)
+
+// BookKey identifies the order book of a trading pair on an exchange.
+type BookKey struct {
+	Exchange string `json:"exchange"`
+	Pair     string `json:"pair"`
+}
+
+// Topic returns the key books are published under. Pairs are matched regardless of case.
+func (k BookKey) Topic() string {
+	return k.Exchange + ":" + strings.ToUpper(k.Pair)
+}
+
+// OrderBookMessage is a message of the order book stream: the latest snapshot on subscribing, then every update.
+type OrderBookMessage struct {
+	// Type is snapshot or update.
+	Type string     `json:"type"`
+	Book *OrderBook `json:"book"`
+}
+
+/*
+ParseBookKeys parses books given as exchange:pair, such as binance:BTC/USDT.
+Returns a validation error if none is given, one is malformed or there are too many.
+*/
+func ParseBookKeys(books []string) ([]BookKey, error) {
+	keys := make([]BookKey, len(books))
+	for i, book := range books {
+		exchange, pair, ok := strings.Cut(book, ":")
+		if !ok || exchange == "" || pair == "" {
+			return nil, Invalid("book", "book %q must be given as exchange:pair", book)
+		}
+		keys[i] = BookKey{Exchange: exchange, Pair: pair}
+	}
+	return keys, ValidateBookKeys(keys)
+}
+
+// ValidateBookKeys checks that at least one book and no more than can be streamed at once are given, each with an exchange and a pair.
+func ValidateBookKeys(keys []BookKey) error {
+	if len(keys) == 0 {
+		return Required("book", "")
+	}
+	if len(keys) > maxStreamBooks {
+		return Invalid("book", "at most %d books can be streamed at once", maxStreamBooks)
+	}
+	for _, key := range keys {
+		if key.Exchange == "" || key.Pair == "" {
+			return Invalid("book", "book %q must have an exchange and a pair", key.Exchange+":"+key.Pair)
+		}
+	}
+	return nil
+}
+
+/*
+SnapshotFilter passes the updates of a stream that started from snapshots. Updates saved while the snapshots
+were read may arrive after them; those that do not follow the snapshot of their book are skipped.
+*/
+type SnapshotFilter map[string]*OrderBook
+
+func NewSnapshotFilter(snapshots []*OrderBook) SnapshotFilter {
+	f := make(SnapshotFilter, len(snapshots))
+	for _, book := range snapshots {
+		f[BookKey{Exchange: book.Exchange, Pair: book.Pair}.Topic()] = book
+	}
+	return f
+}
+
+// Pass reports whether an update should be sent. Once an update of a book follows its snapshot, all later ones pass.
+func (f SnapshotFilter) Pass(update *OrderBook) bool {
+	topic := BookKey{Exchange: update.Exchange, Pair: update.Pair}.Topic()
+	snapshot, ok := f[topic]
+	if !ok {
+		return true
+	}
+	if !update.Follows(snapshot) {
+		return false
+	}
+	delete(f, topic)
+	return true
+}
+
+// Follows reports whether b was taken after other, by timestamp and then by sequence number.
+func (b *OrderBook) Follows(other *OrderBook) bool {
+	if !b.Timestamp.Equal(other.Timestamp) {
+		return b.Timestamp.After(other.Timestamp)
+	}
+	return b.Sequence > other.Sequence
+}
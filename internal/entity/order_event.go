@@ -0,0 +1,75 @@
+package entity
+
+import "time"
+
+type OrderStatus string
+
+const (
+	OrderStatusNew             OrderStatus = "new"
+	OrderStatusPartiallyFilled OrderStatus = "partially_filled"
+	OrderStatusFilled          OrderStatus = "filled"
+	OrderStatusCancelled       OrderStatus = "cancelled"
+	OrderStatusRejected        OrderStatus = "rejected"
+	OrderStatusExpired         OrderStatus = "expired"
+)
+
+var ErrIllegalTransition error = &Error{Kind: ErrConflict, Code: "illegal_transition", Message: "illegal order status transition"}
+
+// orderTransitions lists the statuses each status may move to. An order without events starts from "".
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	"":                         {OrderStatusNew, OrderStatusRejected},
+	OrderStatusNew:             {OrderStatusPartiallyFilled, OrderStatusFilled, OrderStatusCancelled, OrderStatusRejected, OrderStatusExpired},
+	OrderStatusPartiallyFilled: {OrderStatusPartiallyFilled, OrderStatusFilled, OrderStatusCancelled, OrderStatusExpired},
+}
+
+type OrderEvent struct {
+	OrderID         string      `json:"order_id"`
+	ExchangeOrderID string      `json:"exchange_order_id"`
+	ClientName      string      `json:"client_name"`
+	ExchangeName    string      `json:"exchange_name"`
+	Pair            string      `json:"pair"`
+	Status          OrderStatus `json:"status"`
+	FilledQty       float64     `json:"filled_qty"`
+	Reason          string      `json:"reason"`
+	EventTime       time.Time   `json:"event_time"`
+}
+
+type OrderTimeline struct {
+	OrderID string          `json:"order_id"`
+	Status  OrderStatus     `json:"status"`
+	Events  []*OrderEvent   `json:"events"`
+	Fills   []*HistoryOrder `json:"fills"`
+}
+
+// CanTransition reports whether an order in status from may move to status to.
+func CanTransition(from, to OrderStatus) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Validate checks that the event names an order and a known status.
+A missing event time defaults to now.
+*/
+func (e *OrderEvent) Validate() error {
+	if e.OrderID == "" {
+		return Required("order_id", e.OrderID)
+	}
+
+	switch e.Status {
+	case OrderStatusNew, OrderStatusPartiallyFilled, OrderStatusFilled,
+		OrderStatusCancelled, OrderStatusRejected, OrderStatusExpired:
+	default:
+		return Invalid("status", "unknown order status %q", e.Status)
+	}
+
+	if e.EventTime.IsZero() {
+		e.EventTime = time.Now()
+	}
+
+	return nil
+}
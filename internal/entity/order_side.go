@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+type Side string
+
+const (
+	SideBuy  Side = "buy"
+	SideSell Side = "sell"
+)
+
+type OrderType string
+
+const (
+	OrderTypeMarket    OrderType = "market"
+	OrderTypeLimit     OrderType = "limit"
+	OrderTypeStop      OrderType = "stop"
+	OrderTypeStopLimit OrderType = "stop-limit"
+	OrderTypePostOnly  OrderType = "post-only"
+	OrderTypeIOC       OrderType = "ioc"
+	OrderTypeFOK       OrderType = "fok"
+)
+
+// ParseSide parses a side case-insensitively, accepting the aliases collectors send.
+func ParseSide(s string) (Side, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "buy", "b", "bid":
+		return SideBuy, nil
+	case "sell", "s", "ask":
+		return SideSell, nil
+	default:
+		return "", Invalid("side", "unknown side %q", s)
+	}
+}
+
+// ParseOrderType parses an order type case-insensitively, accepting "_" or no separator in compound names.
+func ParseOrderType(s string) (OrderType, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "market":
+		return OrderTypeMarket, nil
+	case "limit":
+		return OrderTypeLimit, nil
+	case "stop":
+		return OrderTypeStop, nil
+	case "stop-limit", "stop_limit", "stoplimit":
+		return OrderTypeStopLimit, nil
+	case "post-only", "post_only", "postonly":
+		return OrderTypePostOnly, nil
+	case "ioc":
+		return OrderTypeIOC, nil
+	case "fok":
+		return OrderTypeFOK, nil
+	default:
+		return "", Invalid("type", "unknown order type %q", s)
+	}
+}
+
+// UnmarshalJSON normalises the side. An empty string decodes to the zero value.
+func (s *Side) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	return s.UnmarshalText([]byte(raw))
+}
+
+// UnmarshalText normalises the side for decoders other than JSON, such as MessagePack.
+func (s *Side) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*s = ""
+		return nil
+	}
+
+	parsed, err := ParseSide(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// UnmarshalJSON normalises the order type. An empty string decodes to the zero value.
+func (t *OrderType) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	return t.UnmarshalText([]byte(raw))
+}
+
+// UnmarshalText normalises the order type for decoders other than JSON, such as MessagePack.
+func (t *OrderType) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*t = ""
+		return nil
+	}
+
+	parsed, err := ParseOrderType(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
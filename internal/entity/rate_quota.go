@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+/*
+RateQuota is how many requests per second callers may make to a route, and how many they may make at once after being idle.
+Route is a route group, such as read or write, or a single route as its method and pattern, such as GET /v1/history/{clientName}.
+A quota with a KeyID applies to that API key only, one without to every caller.
+*/
+type RateQuota struct {
+	KeyID     string    `json:"key_id,omitempty"`
+	Route     string    `json:"route"`
+	Rate      float64   `json:"rate"`
+	Burst     int       `json:"burst"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Deleted   bool      `json:"-"`
+}
+
+// Validate checks that the quota names a route and allows some requests.
+func (q *RateQuota) Validate() error {
+	if err := Required("route", q.Route); err != nil {
+		return err
+	}
+	if method, pattern, ok := strings.Cut(q.Route, " "); ok && (method != strings.ToUpper(method) || !strings.HasPrefix(pattern, "/")) {
+		return Invalid("route", "route must be a route group or a method and pattern, as in GET /v1/history/{clientName}")
+	}
+	if q.Rate <= 0 {
+		return Invalid("rate", "rate must be positive")
+	}
+	if q.Burst < 1 {
+		return Invalid("burst", "burst must be at least 1")
+	}
+	return nil
+}
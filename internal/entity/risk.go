@@ -0,0 +1,111 @@
+package entity
+
+import "time"
+
+var ErrNoMarkPrice error = &Error{Kind: ErrUnprocessable, Code: "no_mark_price", Message: "no price to value the order at"}
+
+// Names of the limits a risk breach refers to.
+const (
+	RiskLimitMaxPosition      = "max_position"
+	RiskLimitMaxOrderNotional = "max_order_notional"
+	RiskLimitMaxDailyNotional = "max_daily_notional"
+	RiskLimitMaxLoss          = "max_loss"
+)
+
+/*
+RiskLimit caps the trading of a client on an exchange. Limits with an empty pair apply to every pair
+the client has no pair-specific limits for. A zero limit is not enforced.
+Position is in base currency, notionals and loss in quote currency.
+*/
+type RiskLimit struct {
+	ClientName       string    `json:"client_name"`
+	ExchangeName     string    `json:"exchange_name"`
+	Pair             string    `json:"pair"`
+	MaxPosition      float64   `json:"max_position"`
+	MaxOrderNotional float64   `json:"max_order_notional"`
+	MaxDailyNotional float64   `json:"max_daily_notional"`
+	MaxLoss          float64   `json:"max_loss"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Validate checks that the limit names its client and exchange and that no limit is negative.
+func (l *RiskLimit) Validate() error {
+	if err := Required("client_name", l.ClientName, "exchange_name", l.ExchangeName); err != nil {
+		return err
+	}
+
+	limits := []struct {
+		field string
+		value float64
+	}{
+		{"max_position", l.MaxPosition},
+		{"max_order_notional", l.MaxOrderNotional},
+		{"max_daily_notional", l.MaxDailyNotional},
+		{"max_loss", l.MaxLoss},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			return Invalid(limit.field, "%s must not be negative", limit.field)
+		}
+	}
+	return nil
+}
+
+// RiskExposure is what a client has traded in a pair, as derived from history_orders.
+type RiskExposure struct {
+	// Position is the net base quantity bought.
+	Position float64
+	// Cost is the net quote amount paid for the position.
+	Cost float64
+	Fees float64
+	// DailyNotional is the quote amount traded since the start of the UTC day.
+	DailyNotional float64
+}
+
+// RiskCheckRequest is a proposed order. Without a price the order is valued at the current mid price.
+type RiskCheckRequest struct {
+	ClientName   string  `json:"client_name"`
+	ExchangeName string  `json:"exchange_name"`
+	Pair         string  `json:"pair"`
+	Side         Side    `json:"side"`
+	BaseQty      float64 `json:"base_qty"`
+	Price        float64 `json:"price"`
+}
+
+func (r *RiskCheckRequest) Validate() error {
+	if err := Required("client_name", r.ClientName, "exchange_name", r.ExchangeName, "pair", r.Pair, "side", string(r.Side)); err != nil {
+		return err
+	}
+	if r.BaseQty <= 0 {
+		return Invalid("base_qty", "base_qty must be positive")
+	}
+	if r.Price < 0 {
+		return Invalid("price", "price must not be negative")
+	}
+	return nil
+}
+
+type RiskCheck struct {
+	Allowed                bool          `json:"allowed"`
+	Limit                  *RiskLimit    `json:"limit"`
+	MarkPrice              float64       `json:"mark_price"`
+	OrderNotional          float64       `json:"order_notional"`
+	Position               float64       `json:"position"`
+	ProjectedPosition      float64       `json:"projected_position"`
+	DailyNotional          float64       `json:"daily_notional"`
+	ProjectedDailyNotional float64       `json:"projected_daily_notional"`
+	PnL                    float64       `json:"pnl"`
+	Breaches               []*RiskBreach `json:"breaches"`
+}
+
+// RiskBreach is a limit a proposed order would exceed, or one a saved fill exceeded.
+type RiskBreach struct {
+	ClientName   string    `json:"client_name"`
+	ExchangeName string    `json:"exchange_name"`
+	Pair         string    `json:"pair"`
+	Limit        string    `json:"limit" gorm:"column:limit_name"`
+	Threshold    float64   `json:"threshold"`
+	Value        float64   `json:"value"`
+	OrderID      string    `json:"order_id,omitempty"`
+	DetectedAt   time.Time `json:"detected_at"`
+}
@@ -0,0 +1,12 @@
+package entity
+
+type SaveResult struct {
+	Saved      int      `json:"saved"`
+	Duplicates []string `json:"duplicates"`
+	Replayed   bool     `json:"replayed,omitempty"`
+	// RiskBreaches lists the risk limits a saved fill exceeded.
+	RiskBreaches []*RiskBreach `json:"risk_breaches,omitempty"`
+}
+
+// ErrIdempotencyKeyReused is returned when an Idempotency-Key is sent again with a different request body.
+var ErrIdempotencyKeyReused error = &Error{Kind: ErrUnprocessable, Code: "idempotency_key_reused", Message: "idempotency key was already used with a different request body"}
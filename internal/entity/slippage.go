@@ -0,0 +1,71 @@
+package entity
+
+import "time"
+
+const DefaultSlippageBucketBps = 5
+
+type SlippageRequest struct {
+	HistoryFilter
+	BucketBps     float64 `json:"bucket_bps"`
+	IncludeOrders bool    `json:"include_orders"`
+}
+
+type OrderSlippage struct {
+	ExchangeName        string    `json:"exchange_name"`
+	Pair                string    `json:"pair"`
+	AlgorithmNamePlaced string    `json:"algorithm_name_placed"`
+	Side                Side      `json:"side"`
+	Price               float64   `json:"price"`
+	MidPrice            float64   `json:"mid_price"`
+	MidSlippageBps      float64   `json:"mid_slippage_bps"`
+	TouchSlippageBps    float64   `json:"touch_slippage_bps"`
+	OutsideSpread       bool      `json:"outside_spread"`
+	TimePlaced          time.Time `json:"time_placed"`
+}
+
+type HistogramBucket struct {
+	LowerBps float64 `json:"lower_bps"`
+	UpperBps float64 `json:"upper_bps"`
+	Count    int     `json:"count"`
+}
+
+type Distribution struct {
+	Mean      float64           `json:"mean"`
+	Min       float64           `json:"min"`
+	P50       float64           `json:"p50"`
+	P90       float64           `json:"p90"`
+	P95       float64           `json:"p95"`
+	P99       float64           `json:"p99"`
+	Max       float64           `json:"max"`
+	Histogram []HistogramBucket `json:"histogram"`
+}
+
+type SlippageAnalysis struct {
+	ExchangeName        string          `json:"exchange_name"`
+	Pair                string          `json:"pair"`
+	AlgorithmNamePlaced string          `json:"algorithm_name_placed"`
+	OrderCount          int             `json:"order_count"`
+	OutsideSpreadCount  int             `json:"outside_spread_count"`
+	MidSlippage         Distribution    `json:"mid_slippage"`
+	TouchSlippage       Distribution    `json:"touch_slippage"`
+	Orders              []OrderSlippage `json:"orders,omitempty"`
+}
+
+/*
+Validate checks the time range and histogram bucket width.
+A zero bucket width defaults to DefaultSlippageBucketBps.
+*/
+func (r *SlippageRequest) Validate() error {
+	if err := r.HistoryFilter.Validate(); err != nil {
+		return err
+	}
+
+	if r.BucketBps == 0 {
+		r.BucketBps = DefaultSlippageBucketBps
+	}
+	if r.BucketBps < 0 {
+		return Invalid("bucket_bps", "bucket_bps must be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package entity
+
+import "time"
+
+const (
+	TCAGroupByLabel     = "label"
+	TCAGroupByAlgorithm = "algorithm"
+)
+
+type TCARequest struct {
+	HistoryFilter
+	GroupBy string `json:"group_by"`
+}
+
+/*
+TCAReport is the transaction cost analysis of one parent order.
+Quantity is the net quantity on Side, whose fills AvgFillPrice is taken from.
+Only depth snapshots are stored, not the market's trades, so IntervalVwap and Participation are measured against
+the fills of every client stored for the exchange and pair while the parent order was working, the same trades
+trade bars are built from. IntervalTwap weights each stored book mid by how long it was in force over that interval.
+ShortfallBps, FeesBps and TotalCostBps are all in bps of the net quantity valued at the arrival price.
+*/
+type TCAReport struct {
+	ParentID        string    `json:"parent_id"`
+	ClientName      string    `json:"client_name"`
+	ExchangeName    string    `json:"exchange_name"`
+	Pair            string    `json:"pair"`
+	Side            Side      `json:"side"`
+	FillCount       int       `json:"fill_count"`
+	Quantity        float64   `json:"quantity"`
+	BuyQty          float64   `json:"buy_qty"`
+	SellQty         float64   `json:"sell_qty"`
+	AvgBuyPrice     float64   `json:"avg_buy_price"`
+	AvgSellPrice    float64   `json:"avg_sell_price"`
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+	ArrivalPrice    float64   `json:"arrival_price"`
+	AvgFillPrice    float64   `json:"avg_fill_price"`
+	IntervalVwap    float64   `json:"interval_vwap"`
+	IntervalTwap    float64   `json:"interval_twap"`
+	Participation   float64   `json:"participation"`
+	ShortfallBps    float64   `json:"shortfall_bps"`
+	VwapSlippageBps float64   `json:"vwap_slippage_bps"`
+	TwapSlippageBps float64   `json:"twap_slippage_bps"`
+	Fees            float64   `json:"fees"`
+	FeesBps         float64   `json:"fees_bps"`
+	TotalCostBps    float64   `json:"total_cost_bps"`
+}
+
+/*
+Validate checks the time range and grouping.
+An empty grouping defaults to parent orders identified by label.
+*/
+func (r *TCARequest) Validate() error {
+	if err := r.HistoryFilter.Validate(); err != nil {
+		return err
+	}
+
+	switch r.GroupBy {
+	case "":
+		r.GroupBy = TCAGroupByLabel
+	case TCAGroupByLabel, TCAGroupByAlgorithm:
+	default:
+		return Invalid("group_by", "unknown group_by %q", r.GroupBy)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package entity
+
+import "time"
+
+const DefaultTradeBarInterval = "1m"
+
+type TradeBarRequest struct {
+	ExchangeName    string    `json:"exchange_name"`
+	Pair            string    `json:"pair"`
+	ClientName      string    `json:"client_name"`
+	AlgorithmNames  []string  `json:"algorithm_names"`
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
+	Interval        string    `json:"interval"`
+	IntervalSeconds int64     `json:"-"`
+}
+
+type TradeBar struct {
+	BucketStart time.Time `json:"time"`
+	Open        float64   `json:"open"`
+	High        float64   `json:"high"`
+	Low         float64   `json:"low"`
+	Close       float64   `json:"close"`
+	Volume      float64   `json:"volume"`
+	BuyVolume   float64   `json:"buy_volume"`
+	SellVolume  float64   `json:"sell_volume"`
+	TradeCount  uint64    `json:"trade_count"`
+	Notional    float64   `json:"notional"`
+	Vwap        float64   `json:"vwap" gorm:"-"`
+}
+
+/*
+Validate checks the bar request and parses Interval into IntervalSeconds.
+The exchange and pair are required; an empty interval defaults to DefaultTradeBarInterval.
+*/
+func (r *TradeBarRequest) Validate() error {
+	if err := Required("exchange_name", r.ExchangeName, "pair", r.Pair); err != nil {
+		return err
+	}
+
+	if !r.From.IsZero() && !r.To.IsZero() && r.To.Before(r.From) {
+		return Invalid("to", "time range ends before it starts")
+	}
+
+	if r.Interval == "" {
+		r.Interval = DefaultTradeBarInterval
+	}
+	d, err := time.ParseDuration(r.Interval)
+	if err != nil {
+		return Invalid("interval", "invalid interval %q: %v", r.Interval, err)
+	}
+	if d < time.Second || d%time.Second != 0 {
+		return Invalid("interval", "interval %q must be a whole number of seconds", r.Interval)
+	}
+	r.IntervalSeconds = int64(d / time.Second)
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+)
+
+const (
+	KindHistory   = "history"
+	KindOrderBook = "orderbook"
+)
+
+// batch decodes records of one kind and saves them in a single insert.
+type batch interface {
+	Add(record map[string]any, key string) error
+	Len() int
+	Save() error
+	Reset()
+}
+
+func newBatch(kind string, repo repository.OrderRepository, clients service.ClientService) (batch, error) {
+	switch kind {
+	case KindHistory:
+		return &historyBatch{repo: repo, clients: clients}, nil
+	case KindOrderBook:
+		return &orderBookBatch{repo: repo}, nil
+	}
+	return nil, fmt.Errorf("unknown import kind %q", kind)
+}
+
+var historyFields = map[string]fieldKind{
+	"base_qty":             floatField,
+	"price":                floatField,
+	"lowest_sell_prc":      floatField,
+	"highest_buy_prc":      floatField,
+	"commission_quote_qty": floatField,
+	"commission_qty":       floatField,
+	"time_placed":          timeField,
+}
+
+type historyBatch struct {
+	repo    repository.OrderRepository
+	clients service.ClientService
+	orders  []*entity.HistoryOrder
+}
+
+/*
+Add decodes a history order and checks that it is complete enough to be stored
+and, unless there is no client service to ask, that its client account is registered for its pair.
+Orders without a natural key are given key, so importing the same record twice saves it once.
+*/
+func (b *historyBatch) Add(record map[string]any, key string) error {
+	var order entity.HistoryOrder
+	if err := decode(record, historyFields, &order); err != nil {
+		return err
+	}
+
+	if err := order.Validate(); err != nil {
+		return err
+	}
+	if order.ClientName == "" || order.ExchangeName == "" || order.Pair == "" {
+		return fmt.Errorf("client_name, exchange_name and pair are required")
+	}
+	if order.TimePlaced.IsZero() {
+		return fmt.Errorf("time_placed is required")
+	}
+	if b.clients != nil {
+		if err := b.clients.ValidateOrder(&order); err != nil {
+			return err
+		}
+	}
+
+	order.NormalizeCommission()
+
+	if order.NaturalKey() == "" {
+		order.DedupKey = key
+	} else {
+		order.DedupKey = order.NaturalKey()
+	}
+
+	b.orders = append(b.orders, &order)
+	return nil
+}
+
+func (b *historyBatch) Len() int {
+	return len(b.orders)
+}
+
+func (b *historyBatch) Save() error {
+	return b.repo.SaveOrderHistoryBatch(b.orders)
+}
+
+func (b *historyBatch) Reset() {
+	b.orders = nil
+}
+
+var orderBookFields = map[string]fieldKind{
+	"id":        intField,
+	"sequence":  intField,
+	"timestamp": timeField,
+	"asks":      depthField,
+	"bids":      depthField,
+}
+
+// orderBookRecord mirrors entity.OrderBookDTO with the depth decoded.
+type orderBookRecord struct {
+	ID        int64               `json:"id"`
+	Exchange  string              `json:"exchange"`
+	Pair      string              `json:"pair"`
+	Asks      []entity.DepthOrder `json:"asks"`
+	Bids      []entity.DepthOrder `json:"bids"`
+	Timestamp time.Time           `json:"timestamp"`
+	Sequence  int64               `json:"sequence"`
+	DedupKey  string              `json:"dedup_key"`
+}
+
+type orderBookBatch struct {
+	repo  repository.OrderRepository
+	books []*entity.OrderBook
+}
+
+/*
+Add decodes an order book snapshot. Historical snapshots must carry the time they were taken.
+Snapshots without a natural key are given key.
+*/
+func (b *orderBookBatch) Add(record map[string]any, key string) error {
+	var r orderBookRecord
+	if err := decode(record, orderBookFields, &r); err != nil {
+		return err
+	}
+
+	if r.Exchange == "" || r.Pair == "" {
+		return fmt.Errorf("exchange and pair are required")
+	}
+	if r.Timestamp.IsZero() {
+		return fmt.Errorf("timestamp is required")
+	}
+
+	book := &entity.OrderBook{
+		ID:        r.ID,
+		Exchange:  r.Exchange,
+		Pair:      r.Pair,
+		Asks:      r.Asks,
+		Bids:      r.Bids,
+		Timestamp: r.Timestamp,
+		Sequence:  r.Sequence,
+		DedupKey:  r.DedupKey,
+	}
+	if book.NaturalKey() == "" {
+		book.DedupKey = key
+	} else {
+		book.DedupKey = book.NaturalKey()
+	}
+
+	b.books = append(b.books, book)
+	return nil
+}
+
+func (b *orderBookBatch) Len() int {
+	return len(b.books)
+}
+
+func (b *orderBookBatch) Save() error {
+	return b.repo.SaveOrderBookBatch(b.books)
+}
+
+func (b *orderBookBatch) Reset() {
+	b.books = nil
+}
+
+func decode(record map[string]any, kinds map[string]fieldKind, v any) error {
+	if err := coerce(record, kinds); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
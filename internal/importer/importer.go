@@ -0,0 +1,283 @@
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+)
+
+// DefaultBatchSize is the number of records saved with one insert when no batch size is given.
+const DefaultBatchSize = 1000
+
+type Options struct {
+	// Kind is KindHistory or KindOrderBook.
+	Kind string
+	// Format is FormatCSV or FormatNDJSON. When empty it is taken from the file extension.
+	Format string
+	// Mapping renames source columns to the JSON field names of the imported entity.
+	Mapping map[string]string
+	// BatchSize is the number of records saved with one insert.
+	BatchSize int
+	// DryRun decodes and validates every record, reporting all invalid ones, without saving anything.
+	DryRun bool
+	// Resume keeps a checkpoint next to the file so an interrupted import continues after the last saved batch.
+	// A checkpoint written for other file content is refused rather than resumed.
+	Resume bool
+	// Progress receives a line after every batch and, in a dry run, every invalid record. May be nil.
+	Progress io.Writer
+}
+
+type Stats struct {
+	// Skipped counts the records saved by an earlier run and skipped on resume.
+	Skipped  int
+	Imported int
+	Invalid  int
+}
+
+type Importer struct {
+	repo    repository.OrderRepository
+	clients service.ClientService
+}
+
+/*
+New returns an importer saving to repo. Imported fills must belong to an account registered with clients,
+as fills saved through the API do; clients may be nil in a dry run, which leaves accounts unchecked.
+*/
+func New(repo repository.OrderRepository, clients service.ClientService) *Importer {
+	return &Importer{repo: repo, clients: clients}
+}
+
+type checkpoint struct {
+	File string `json:"file"`
+	// FileHash is the hash of the content the records were counted in.
+	FileHash  string    `json:"file_hash"`
+	Records   int       `json:"records"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+/*
+ImportFile imports the history orders or order book snapshots of a CSV or NDJSON file in batches.
+An invalid record stops the import, batches saved before it stay saved and, with opts.Resume,
+a later run continues after them. The checkpoint is removed once the whole file is imported,
+and a run refuses to resume from one written for different file content, whose record positions no longer match.
+Records are keyed by a hash of the file content and their position when they carry no natural key,
+so a record imported twice, even from a renamed copy of the file, is only kept once, and records of
+different files with the same name never share a key.
+Also returns an error if one occures.
+*/
+func (i *Importer) ImportFile(path string, opts Options) (*Stats, error) {
+	if opts.Format == "" {
+		opts.Format = formatOf(path)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.Progress == nil {
+		opts.Progress = io.Discard
+	}
+
+	b, err := newBatch(opts.Kind, i.repo, i.clients)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	fileHash, err := hashFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	counter := &countingReader{r: f}
+
+	records, err := newRecordReader(opts.Format, counter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	checkpointPath := path + ".checkpoint"
+	stats := &Stats{}
+	if opts.Resume && !opts.DryRun {
+		cp, err := loadCheckpoint(checkpointPath)
+		if err != nil {
+			return nil, err
+		}
+		if cp.Records > 0 && cp.FileHash != fileHash {
+			return nil, fmt.Errorf("%s: checkpoint %s was written for different file content, remove it to import the file from the start", path, checkpointPath)
+		}
+		stats.Skipped = cp.Records
+	}
+
+	p := &progress{w: opts.Progress, path: path, size: info.Size(), counter: counter, stats: stats, dryRun: opts.DryRun, start: time.Now()}
+	flush := func(read int) error {
+		if b.Len() == 0 {
+			return nil
+		}
+		if !opts.DryRun {
+			if err := b.Save(); err != nil {
+				return fmt.Errorf("%s: error saving batch: %w", path, err)
+			}
+		}
+		stats.Imported += b.Len()
+		b.Reset()
+
+		if opts.Resume && !opts.DryRun {
+			cp := checkpoint{File: path, FileHash: fileHash, Records: read, UpdatedAt: time.Now()}
+			if err := saveCheckpoint(checkpointPath, cp); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	read := 0
+	for ; ; read++ {
+		record, line, err := records.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, errMalformedRecord) {
+			return stats, fmt.Errorf("%s: %w", path, err)
+		}
+		if read < stats.Skipped {
+			continue
+		}
+
+		if err == nil {
+			for from, to := range opts.Mapping {
+				if v, ok := record[from]; ok {
+					delete(record, from)
+					record[to] = v
+				}
+			}
+			err = b.Add(record, fmt.Sprintf("import:%s:%d", fileHash, read))
+		}
+		if err != nil {
+			stats.Invalid++
+			if !opts.DryRun {
+				return stats, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+			fmt.Fprintf(opts.Progress, "%s:%d: %v\n", path, line, err)
+			continue
+		}
+
+		if b.Len() >= opts.BatchSize {
+			if err := flush(read + 1); err != nil {
+				return stats, err
+			}
+			p.report()
+		}
+	}
+
+	if err := flush(read); err != nil {
+		return stats, err
+	}
+	p.report()
+
+	if opts.Resume && !opts.DryRun {
+		if err := os.Remove(checkpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+// hashFile returns a short hash of the content of f and rewinds it to the start.
+func hashFile(f *os.File) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+func formatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl", ".json":
+		return FormatNDJSON
+	}
+	return FormatCSV
+}
+
+func loadCheckpoint(path string) (checkpoint, error) {
+	var cp checkpoint
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return cp, err
+	}
+	if err := json.Unmarshal(raw, &cp); err != nil {
+		return cp, fmt.Errorf("error reading checkpoint %s: %w", path, err)
+	}
+	return cp, nil
+}
+
+// saveCheckpoint replaces the checkpoint atomically so an interrupted write never loses the previous one.
+func saveCheckpoint(path string, cp checkpoint) error {
+	raw, _ := json.Marshal(cp)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return fmt.Errorf("error writing checkpoint %s: %w", path, err)
+	}
+	return os.Rename(tmp, path)
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type progress struct {
+	w       io.Writer
+	path    string
+	size    int64
+	counter *countingReader
+	stats   *Stats
+	dryRun  bool
+	start   time.Time
+}
+
+// report prints how far through the file the import is. Bytes read run slightly ahead of the records saved.
+func (p *progress) report() {
+	done := 100.0
+	if p.size > 0 {
+		done = float64(p.counter.n) / float64(p.size) * 100
+	}
+	verb := "imported"
+	if p.dryRun {
+		verb = "checked"
+	}
+	rate := float64(p.stats.Imported) / time.Since(p.start).Seconds()
+
+	fmt.Fprintf(p.w, "%s: %.1f%%, %d records %s, %d invalid, %d skipped, %.0f records/s\n",
+		p.path, done, p.stats.Imported, verb, p.stats.Invalid, p.stats.Skipped, rate)
+}
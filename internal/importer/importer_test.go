@@ -0,0 +1,205 @@
+package importer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const historyCSV = `client,exchange_name,label,pair,side,type,qty,price,time_placed
+client1,exchange1,label1,BTC/USDT,BUY,limit,1.5,100,2024-05-01 12:00:00
+client1,exchange1,label1,BTC/USDT,s,market,2,101,1714564800
+client1,exchange1,label1,BTC/USDT,sell,ioc,1,102,2024-05-01T12:00:02Z
+`
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportFile_History(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	mockClients := new(mocks.MockClientService)
+	imp := New(mockRepo, mockClients)
+
+	mockClients.On("ValidateOrder", mock.Anything).Return(nil)
+
+	var saved []*entity.HistoryOrder
+	mockRepo.On("SaveOrderHistoryBatch", mock.Anything).Run(func(args mock.Arguments) {
+		saved = append(saved, args.Get(0).([]*entity.HistoryOrder)...)
+	}).Return(nil)
+
+	path := writeFile(t, "fills.csv", historyCSV)
+	var out bytes.Buffer
+	stats, err := imp.ImportFile(path, Options{
+		Kind:      KindHistory,
+		Mapping:   map[string]string{"client": "client_name", "qty": "base_qty"},
+		BatchSize: 2,
+		Resume:    true,
+		Progress:  &out,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Stats{Imported: 3}, stats)
+	mockRepo.AssertNumberOfCalls(t, "SaveOrderHistoryBatch", 2)
+
+	assert.Len(t, saved, 3)
+	assert.Equal(t, "client1", saved[0].ClientName)
+	assert.Equal(t, entity.SideBuy, saved[0].Side)
+	assert.Equal(t, 1.5, saved[0].BaseQty)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), saved[0].TimePlaced)
+	assert.Equal(t, entity.SideSell, saved[1].Side)
+	assert.True(t, saved[1].TimePlaced.Equal(saved[0].TimePlaced))
+	assert.Equal(t, "import:"+contentHash(historyCSV)+":2", saved[2].DedupKey)
+
+	assert.Contains(t, out.String(), "100.0%, 3 records imported")
+	assert.NoFileExists(t, path+".checkpoint")
+	mockClients.AssertNumberOfCalls(t, "ValidateOrder", 3)
+}
+
+func TestImportFile_UnregisteredClient(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	mockClients := new(mocks.MockClientService)
+	imp := New(mockRepo, mockClients)
+
+	mockClients.On("ValidateOrder", mock.MatchedBy(func(o *entity.HistoryOrder) bool { return o.Side == entity.SideBuy })).Return(nil)
+	mockClients.On("ValidateOrder", mock.Anything).Return(entity.ErrUnregisteredClient)
+	mockRepo.On("SaveOrderHistoryBatch", mock.Anything).Return(nil)
+
+	path := writeFile(t, "fills.csv", historyCSV)
+	stats, err := imp.ImportFile(path, Options{
+		Kind:    KindHistory,
+		Mapping: map[string]string{"client": "client_name", "qty": "base_qty"},
+	})
+
+	assert.ErrorIs(t, err, entity.ErrUnregisteredClient)
+	assert.ErrorContains(t, err, path+":3:")
+	assert.Equal(t, &Stats{Invalid: 1}, stats)
+	mockRepo.AssertNotCalled(t, "SaveOrderHistoryBatch", mock.Anything)
+}
+
+func TestImportFile_DryRun(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	imp := New(mockRepo, nil)
+
+	path := writeFile(t, "fills.csv", historyCSV+
+		"client1,exchange1,label1,BTC/USDT,hold,limit,1,100,2024-05-01 12:00:03\n"+
+		"client1,exchange1,label1,BTC/USDT,buy,limit,one,100,2024-05-01 12:00:04\n")
+
+	var out bytes.Buffer
+	stats, err := imp.ImportFile(path, Options{
+		Kind:     KindHistory,
+		Mapping:  map[string]string{"client": "client_name", "qty": "base_qty"},
+		DryRun:   true,
+		Progress: &out,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Stats{Imported: 3, Invalid: 2}, stats)
+	assert.Contains(t, out.String(), path+":5: unknown side")
+	assert.Contains(t, out.String(), path+":6: base_qty")
+	mockRepo.AssertNotCalled(t, "SaveOrderHistoryBatch", mock.Anything)
+}
+
+func TestImportFile_Resume(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	imp := New(mockRepo, nil)
+
+	path := writeFile(t, "fills.csv", historyCSV)
+	opts := Options{
+		Kind:      KindHistory,
+		Mapping:   map[string]string{"client": "client_name", "qty": "base_qty"},
+		BatchSize: 2,
+		Resume:    true,
+	}
+
+	mockRepo.On("SaveOrderHistoryBatch", mock.Anything).Return(nil).Once()
+	mockRepo.On("SaveOrderHistoryBatch", mock.Anything).Return(errors.New("connection reset")).Once()
+
+	_, err := imp.ImportFile(path, opts)
+	assert.Error(t, err)
+	assert.FileExists(t, path+".checkpoint")
+
+	mockRepo.On("SaveOrderHistoryBatch", mock.MatchedBy(func(orders []*entity.HistoryOrder) bool {
+		return len(orders) == 1 && orders[0].Price == 102
+	})).Return(nil).Once()
+
+	stats, err := imp.ImportFile(path, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, &Stats{Skipped: 2, Imported: 1}, stats)
+	assert.NoFileExists(t, path+".checkpoint")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportFile_OrderBookNDJSON(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	imp := New(mockRepo, nil)
+
+	booksNDJSON := `{"exchange": "exchange1", "pair": "BTC/USDT", "ts": 1714564800000, "sequence": 42, "asks": [{"price": 101, "base_qty": 1}], "bids": [{"price": 99, "base_qty": 2}]}
+
+{"exchange": "exchange1", "pair": "BTC/USDT", "ts": "2024-05-01T12:00:01Z", "asks": [], "bids": []}
+`
+	path := writeFile(t, "books.ndjson", booksNDJSON)
+
+	mockRepo.On("SaveOrderBookBatch", mock.MatchedBy(func(books []*entity.OrderBook) bool {
+		return len(books) == 2 &&
+			books[0].Timestamp.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) &&
+			books[0].DedupKey == "exchange1:BTC/USDT:42" &&
+			books[0].Asks[0].Price == 101 &&
+			books[1].DedupKey == "import:"+contentHash(booksNDJSON)+":1"
+	})).Return(nil)
+
+	stats, err := imp.ImportFile(path, Options{Kind: KindOrderBook, Mapping: map[string]string{"ts": "timestamp"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stats.Imported)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportFile_ResumeChangedFile(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	imp := New(mockRepo, nil)
+
+	path := writeFile(t, "fills.csv", historyCSV)
+	opts := Options{
+		Kind:      KindHistory,
+		Mapping:   map[string]string{"client": "client_name", "qty": "base_qty"},
+		BatchSize: 2,
+		Resume:    true,
+	}
+
+	mockRepo.On("SaveOrderHistoryBatch", mock.Anything).Return(nil).Once()
+	mockRepo.On("SaveOrderHistoryBatch", mock.Anything).Return(errors.New("connection reset")).Once()
+
+	_, err := imp.ImportFile(path, opts)
+	assert.Error(t, err)
+
+	// Test case: the file was edited before the import was resumed
+	if err := os.WriteFile(path, []byte(historyCSV+"client1,exchange1,label1,BTC/USDT,buy,limit,1,103,2024-05-01 12:00:03\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := imp.ImportFile(path, opts)
+	assert.ErrorContains(t, err, "different file content")
+	assert.Nil(t, stats)
+	assert.FileExists(t, path+".checkpoint")
+	mockRepo.AssertNumberOfCalls(t, "SaveOrderHistoryBatch", 2)
+}
@@ -0,0 +1,193 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// errMalformedRecord marks a record that could not be parsed. Reading can continue with the next one.
+var errMalformedRecord = errors.New("malformed record")
+
+// recordReader yields the records of an export file keyed by column name, with the line each starts on.
+type recordReader interface {
+	Next() (map[string]any, int, error)
+}
+
+func newRecordReader(format string, r io.Reader) (recordReader, error) {
+	switch format {
+	case FormatCSV:
+		cr := csv.NewReader(r)
+		cr.ReuseRecord = true
+		header, err := cr.Read()
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV header: %w", err)
+		}
+		return &csvRecordReader{r: cr, header: append([]string{}, header...)}, nil
+	case FormatNDJSON:
+		return &ndjsonRecordReader{r: bufio.NewReader(r)}, nil
+	}
+	return nil, fmt.Errorf("unknown import format %q", format)
+}
+
+type csvRecordReader struct {
+	r      *csv.Reader
+	header []string
+}
+
+func (c *csvRecordReader) Next() (map[string]any, int, error) {
+	values, err := c.r.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, parseErr.StartLine, fmt.Errorf("%w: %v", errMalformedRecord, parseErr.Err)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	line, _ := c.r.FieldPos(0)
+
+	record := make(map[string]any, len(c.header))
+	for i, name := range c.header {
+		if i < len(values) {
+			record[strings.TrimSpace(name)] = values[i]
+		}
+	}
+	return record, line, nil
+}
+
+type ndjsonRecordReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+// Next skips blank lines. Numbers are kept as json.Number so integers do not lose precision.
+func (n *ndjsonRecordReader) Next() (map[string]any, int, error) {
+	for {
+		raw, err := n.r.ReadBytes('\n')
+		if len(raw) == 0 && err != nil {
+			return nil, 0, err
+		}
+		n.line++
+
+		raw = bytes.TrimSpace(raw)
+		if len(raw) == 0 {
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var record map[string]any
+		if err := dec.Decode(&record); err != nil {
+			return nil, n.line, fmt.Errorf("%w: %v", errMalformedRecord, err)
+		}
+		return record, n.line, nil
+	}
+}
+
+type fieldKind int
+
+const (
+	floatField fieldKind = iota
+	intField
+	timeField
+	depthField
+)
+
+/*
+coerce converts the values of the typed fields of a record so it can be decoded as JSON.
+CSV values arrive as strings; empty values are dropped and left at their zero value.
+*/
+func coerce(record map[string]any, kinds map[string]fieldKind) error {
+	for name, kind := range kinds {
+		v, ok := record[name]
+		if !ok {
+			continue
+		}
+
+		s, isString := v.(string)
+		if !isString {
+			if n, isNumber := v.(json.Number); isNumber && kind == timeField {
+				t, err := parseTime(n.String())
+				if err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+				record[name] = t
+			}
+			continue
+		}
+
+		s = strings.TrimSpace(s)
+		if s == "" {
+			delete(record, name)
+			continue
+		}
+
+		var err error
+		switch kind {
+		case floatField:
+			var f float64
+			f, err = strconv.ParseFloat(s, 64)
+			record[name] = f
+		case intField:
+			var i int64
+			i, err = strconv.ParseInt(s, 10, 64)
+			record[name] = i
+		case timeField:
+			var t time.Time
+			t, err = parseTime(s)
+			record[name] = t
+		case depthField:
+			if !json.Valid([]byte(s)) {
+				err = fmt.Errorf("invalid JSON")
+			}
+			record[name] = json.RawMessage(s)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// epochMillisThreshold separates Unix timestamps in seconds from ones in milliseconds.
+const epochMillisThreshold = 1e11
+
+/*
+parseTime accepts RFC 3339 and "2006-01-02 15:04:05" style timestamps, read as UTC when they carry no zone,
+and Unix timestamps in seconds or milliseconds.
+*/
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	epoch, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognised time %q", s)
+	}
+	if epoch >= epochMillisThreshold {
+		return time.UnixMilli(int64(epoch)).UTC(), nil
+	}
+	return time.Unix(0, int64(epoch*float64(time.Second))).UTC(), nil
+}
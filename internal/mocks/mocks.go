@@ -1,7 +1,13 @@
 package mocks
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/pubsub"
+	"github.com/egorque1/vortex-test/internal/ratelimit"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -14,6 +20,11 @@ func (m *MockOrderRepository) GetOrderBook(exchangeName, pair string) ([]*entity
 	return args.Get(0).([]*entity.OrderBook), args.Error(1)
 }
 
+func (m *MockOrderRepository) GetLatestOrderBook(exchangeName, pair string) (*entity.OrderBook, error) {
+	args := m.Called(exchangeName, pair)
+	return args.Get(0).(*entity.OrderBook), args.Error(1)
+}
+
 func (m *MockOrderRepository) SaveOrderBook(books []*entity.OrderBook) error {
 	args := m.Called(books)
 	return args.Error(0)
@@ -24,11 +35,36 @@ func (m *MockOrderRepository) GetOrderHistory(client *entity.Client) ([]*entity.
 	return args.Get(0).([]*entity.HistoryOrder), args.Error(1)
 }
 
+func (m *MockOrderRepository) FindOrderHistory(filter *entity.HistoryFilter) ([]*entity.HistoryOrder, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*entity.HistoryOrder), args.Error(1)
+}
+
 func (m *MockOrderRepository) SaveOrderHistory(order entity.HistoryOrder) error {
 	args := m.Called(order)
 	return args.Error(0)
 }
 
+func (m *MockOrderRepository) SaveOrderBookBatch(books []*entity.OrderBook) error {
+	args := m.Called(books)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) SaveOrderHistoryBatch(orders []*entity.HistoryOrder) error {
+	args := m.Called(orders)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) GetExistingOrderBookKeys(keys []string) ([]string, error) {
+	args := m.Called(keys)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetExistingHistoryOrderKeys(keys []string) ([]string, error) {
+	args := m.Called(keys)
+	return args.Get(0).([]string), args.Error(1)
+}
+
 type MockOrderService struct {
 	mock.Mock
 }
@@ -38,9 +74,14 @@ func (m *MockOrderService) GetOrderBook(exchange, pair string) ([]*entity.OrderB
 	return args.Get(0).([]*entity.OrderBook), args.Error(1)
 }
 
-func (m *MockOrderService) SaveOrderBook(books []*entity.OrderBook) error {
+func (m *MockOrderService) SaveOrderBook(books []*entity.OrderBook) (*entity.SaveResult, error) {
 	args := m.Called(books)
-	return args.Error(0)
+	return args.Get(0).(*entity.SaveResult), args.Error(1)
+}
+
+func (m *MockOrderService) SaveOrderBookBatch(books []*entity.OrderBook) (*entity.SaveResult, error) {
+	args := m.Called(books)
+	return args.Get(0).(*entity.SaveResult), args.Error(1)
 }
 
 func (m *MockOrderService) GetOrderHistory(client *entity.Client) ([]*entity.HistoryOrder, error) {
@@ -48,7 +89,496 @@ func (m *MockOrderService) GetOrderHistory(client *entity.Client) ([]*entity.His
 	return args.Get(0).([]*entity.HistoryOrder), args.Error(1)
 }
 
-func (m *MockOrderService) SaveOrderHistory(order entity.HistoryOrder) error {
+func (m *MockOrderService) FindOrderHistory(filter *entity.HistoryFilter) ([]*entity.HistoryOrder, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*entity.HistoryOrder), args.Error(1)
+}
+
+func (m *MockOrderService) SaveOrderHistory(order entity.HistoryOrder) (*entity.SaveResult, error) {
+	args := m.Called(order)
+	return args.Get(0).(*entity.SaveResult), args.Error(1)
+}
+
+type MockReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockReportRepository) GetCommissionReport(req *entity.CommissionReportRequest) ([]*entity.CommissionReport, error) {
+	args := m.Called(req)
+	return args.Get(0).([]*entity.CommissionReport), args.Error(1)
+}
+
+type MockReportService struct {
+	mock.Mock
+}
+
+func (m *MockReportService) GetCommissionReport(req *entity.CommissionReportRequest) ([]*entity.CommissionReport, error) {
+	args := m.Called(req)
+	return args.Get(0).([]*entity.CommissionReport), args.Error(1)
+}
+
+type MockAnalyticsRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsRepository) GetHistoryOrders(filter *entity.HistoryFilter) ([]*entity.HistoryOrder, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]*entity.HistoryOrder), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) GetTradeBars(req *entity.TradeBarRequest) ([]*entity.TradeBar, error) {
+	args := m.Called(req)
+	return args.Get(0).([]*entity.TradeBar), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) GetIntervalBar(exchangeName, pair string, from, to time.Time) (*entity.TradeBar, error) {
+	args := m.Called(exchangeName, pair, from, to)
+	return args.Get(0).(*entity.TradeBar), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) GetMidsBetween(exchangeName, pair string, from, to time.Time) ([]*entity.BookMid, error) {
+	args := m.Called(exchangeName, pair, from, to)
+	return args.Get(0).([]*entity.BookMid), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) GetMidsAsOf(exchangeName string, pairs []string, times []time.Time, maxAge time.Duration) ([]*entity.BookMid, error) {
+	args := m.Called(exchangeName, pairs, times, maxAge)
+	return args.Get(0).([]*entity.BookMid), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) GetLastOrderBookTime(exchangeName, pair string) (time.Time, error) {
+	args := m.Called(exchangeName, pair)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+type MockAnalyticsService struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsService) GetAlgorithmPerformance(req *entity.AlgorithmPerformanceRequest) ([]*entity.AlgorithmPerformance, error) {
+	args := m.Called(req)
+	return args.Get(0).([]*entity.AlgorithmPerformance), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetSlippageAnalysis(req *entity.SlippageRequest) ([]*entity.SlippageAnalysis, error) {
+	args := m.Called(req)
+	return args.Get(0).([]*entity.SlippageAnalysis), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetMarkouts(req *entity.MarkoutRequest) ([]*entity.MarkoutAnalysis, error) {
+	args := m.Called(req)
+	return args.Get(0).([]*entity.MarkoutAnalysis), args.Error(1)
+}
+
+func (m *MockAnalyticsService) GetTradeBars(req *entity.TradeBarRequest) ([]*entity.TradeBar, error) {
+	args := m.Called(req)
+	return args.Get(0).([]*entity.TradeBar), args.Error(1)
+}
+
+type MockOrderEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderEventRepository) GetOrderEvents(orderID string) ([]*entity.OrderEvent, error) {
+	args := m.Called(orderID)
+	return args.Get(0).([]*entity.OrderEvent), args.Error(1)
+}
+
+func (m *MockOrderEventRepository) SaveOrderEvent(event entity.OrderEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockOrderEventRepository) GetOrderFills(orderID string) ([]*entity.HistoryOrder, error) {
+	args := m.Called(orderID)
+	return args.Get(0).([]*entity.HistoryOrder), args.Error(1)
+}
+
+type MockOrderEventService struct {
+	mock.Mock
+}
+
+func (m *MockOrderEventService) TransitionOrder(event entity.OrderEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockOrderEventService) GetOrderTimeline(orderID string) (*entity.OrderTimeline, error) {
+	args := m.Called(orderID)
+	return args.Get(0).(*entity.OrderTimeline), args.Error(1)
+}
+
+type MockTCAService struct {
+	mock.Mock
+}
+
+func (m *MockTCAService) GetTCAReports(req *entity.TCARequest) ([]*entity.TCAReport, error) {
+	args := m.Called(req)
+	return args.Get(0).([]*entity.TCAReport), args.Error(1)
+}
+
+type MockClientRepository struct {
+	mock.Mock
+}
+
+func (m *MockClientRepository) GetClientAccounts(clientName string) ([]*entity.ClientAccount, error) {
+	args := m.Called(clientName)
+	return args.Get(0).([]*entity.ClientAccount), args.Error(1)
+}
+
+func (m *MockClientRepository) GetClientAccount(clientName, exchangeName, label string) (*entity.ClientAccount, error) {
+	args := m.Called(clientName, exchangeName, label)
+	return args.Get(0).(*entity.ClientAccount), args.Error(1)
+}
+
+func (m *MockClientRepository) SaveClientAccount(account entity.ClientAccount) error {
+	args := m.Called(account)
+	return args.Error(0)
+}
+
+func (m *MockClientRepository) DeleteClientAccount(clientName, exchangeName, label string) error {
+	args := m.Called(clientName, exchangeName, label)
+	return args.Error(0)
+}
+
+type MockClientService struct {
+	mock.Mock
+}
+
+func (m *MockClientService) CreateClient(account entity.ClientAccount) error {
+	args := m.Called(account)
+	return args.Error(0)
+}
+
+func (m *MockClientService) GetClients(clientName string) ([]*entity.ClientAccount, error) {
+	args := m.Called(clientName)
+	return args.Get(0).([]*entity.ClientAccount), args.Error(1)
+}
+
+func (m *MockClientService) GetClient(clientName, exchangeName, label string) (*entity.ClientAccount, error) {
+	args := m.Called(clientName, exchangeName, label)
+	return args.Get(0).(*entity.ClientAccount), args.Error(1)
+}
+
+func (m *MockClientService) UpdateClient(account entity.ClientAccount) error {
+	args := m.Called(account)
+	return args.Error(0)
+}
+
+func (m *MockClientService) DeleteClient(clientName, exchangeName, label string) error {
+	args := m.Called(clientName, exchangeName, label)
+	return args.Error(0)
+}
+
+func (m *MockClientService) ValidateOrder(order *entity.HistoryOrder) error {
+	args := m.Called(order)
+	return args.Error(0)
+}
+
+type MockExportRepository struct {
+	mock.Mock
+}
+
+func (m *MockExportRepository) StreamOrderBook(exchange_name, pair string, fn func(*entity.OrderBook) error) error {
+	args := m.Called(exchange_name, pair, fn)
+	return args.Error(0)
+}
+
+func (m *MockExportRepository) StreamOrderHistory(filter *entity.HistoryFilter, fn func(*entity.HistoryOrder) error) error {
+	args := m.Called(filter, fn)
+	return args.Error(0)
+}
+
+type MockExportService struct {
+	mock.Mock
+}
+
+func (m *MockExportService) ExportOrderBook(req *entity.OrderBookRequest, opts *entity.ExportOptions, w io.Writer) error {
+	args := m.Called(req, opts, w)
+	return args.Error(0)
+}
+
+func (m *MockExportService) ExportOrderHistory(filter *entity.HistoryFilter, opts *entity.ExportOptions, w io.Writer) error {
+	args := m.Called(filter, opts, w)
+	return args.Error(0)
+}
+
+type MockRiskRepository struct {
+	mock.Mock
+}
+
+func (m *MockRiskRepository) GetRiskLimits(clientName string) ([]*entity.RiskLimit, error) {
+	args := m.Called(clientName)
+	return args.Get(0).([]*entity.RiskLimit), args.Error(1)
+}
+
+func (m *MockRiskRepository) GetRiskLimit(clientName, exchangeName, pair string) (*entity.RiskLimit, error) {
+	args := m.Called(clientName, exchangeName, pair)
+	return args.Get(0).(*entity.RiskLimit), args.Error(1)
+}
+
+func (m *MockRiskRepository) SaveRiskLimit(limit entity.RiskLimit) error {
+	args := m.Called(limit)
+	return args.Error(0)
+}
+
+func (m *MockRiskRepository) GetExposure(clientName, exchangeName, pair string, dayStart time.Time) (*entity.RiskExposure, error) {
+	args := m.Called(clientName, exchangeName, pair, dayStart)
+	return args.Get(0).(*entity.RiskExposure), args.Error(1)
+}
+
+func (m *MockRiskRepository) GetLatestOrderBook(exchangeName, pair string) (*entity.OrderBook, error) {
+	args := m.Called(exchangeName, pair)
+	return args.Get(0).(*entity.OrderBook), args.Error(1)
+}
+
+func (m *MockRiskRepository) SaveRiskBreaches(breaches []*entity.RiskBreach) error {
+	args := m.Called(breaches)
+	return args.Error(0)
+}
+
+func (m *MockRiskRepository) GetRiskBreaches(clientName string) ([]*entity.RiskBreach, error) {
+	args := m.Called(clientName)
+	return args.Get(0).([]*entity.RiskBreach), args.Error(1)
+}
+
+type MockRiskService struct {
+	mock.Mock
+}
+
+func (m *MockRiskService) SetRiskLimit(limit entity.RiskLimit) error {
+	args := m.Called(limit)
+	return args.Error(0)
+}
+
+func (m *MockRiskService) GetRiskLimits(clientName string) ([]*entity.RiskLimit, error) {
+	args := m.Called(clientName)
+	return args.Get(0).([]*entity.RiskLimit), args.Error(1)
+}
+
+func (m *MockRiskService) CheckOrder(req *entity.RiskCheckRequest) (*entity.RiskCheck, error) {
+	args := m.Called(req)
+	return args.Get(0).(*entity.RiskCheck), args.Error(1)
+}
+
+func (m *MockRiskService) EvaluateFill(order *entity.HistoryOrder) ([]*entity.RiskBreach, error) {
 	args := m.Called(order)
+	return args.Get(0).([]*entity.RiskBreach), args.Error(1)
+}
+
+func (m *MockRiskService) GetRiskBreaches(clientName string) ([]*entity.RiskBreach, error) {
+	args := m.Called(clientName)
+	return args.Get(0).([]*entity.RiskBreach), args.Error(1)
+}
+
+type MockFeeRepository struct {
+	mock.Mock
+}
+
+func (m *MockFeeRepository) GetFeeSchedules(exchangeName string) ([]*entity.FeeSchedule, error) {
+	args := m.Called(exchangeName)
+	return args.Get(0).([]*entity.FeeSchedule), args.Error(1)
+}
+
+func (m *MockFeeRepository) SaveFeeSchedule(schedule entity.FeeSchedule) error {
+	args := m.Called(schedule)
+	return args.Error(0)
+}
+
+type MockFeeService struct {
+	mock.Mock
+}
+
+func (m *MockFeeService) SetFeeSchedule(schedule entity.FeeSchedule) error {
+	args := m.Called(schedule)
+	return args.Error(0)
+}
+
+func (m *MockFeeService) GetFeeSchedules(exchangeName string) ([]*entity.FeeSchedule, error) {
+	args := m.Called(exchangeName)
+	return args.Get(0).([]*entity.FeeSchedule), args.Error(1)
+}
+
+func (m *MockFeeService) ReconcileCommissions(req *entity.FeeReconciliationRequest) (*entity.FeeReconciliation, error) {
+	args := m.Called(req)
+	return args.Get(0).(*entity.FeeReconciliation), args.Error(1)
+}
+
+type MockConversionRepository struct {
+	mock.Mock
+}
+
+func (m *MockConversionRepository) GetMidsAsOf(exchange_name string, pairs []string, times []time.Time, maxAge time.Duration) ([]*entity.BookMid, error) {
+	args := m.Called(exchange_name, pairs, times, maxAge)
+	return args.Get(0).([]*entity.BookMid), args.Error(1)
+}
+
+type MockConversionService struct {
+	mock.Mock
+}
+
+func (m *MockConversionService) Convert(exchangeName, asset, currency string, qty float64, at time.Time) (float64, error) {
+	args := m.Called(exchangeName, asset, currency, qty, at)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockConversionService) ConvertAll(conversions []*entity.Conversion) error {
+	args := m.Called(conversions)
+	return args.Error(0)
+}
+
+func (m *MockConversionService) GetConvertedCommissions(req *entity.CommissionConversionRequest) ([]*entity.ConvertedCommission, error) {
+	args := m.Called(req)
+	return args.Get(0).([]*entity.ConvertedCommission), args.Error(1)
+}
+
+type MockStreamService struct {
+	mock.Mock
+}
+
+func (m *MockStreamService) PublishOrderBooks(books []*entity.OrderBook) {
+	m.Called(books)
+}
+
+func (m *MockStreamService) SubscribeOrderBooks(keys []entity.BookKey) (*pubsub.Subscription[*entity.OrderBook], []*entity.OrderBook, error) {
+	args := m.Called(keys)
+	return args.Get(0).(*pubsub.Subscription[*entity.OrderBook]), args.Get(1).([]*entity.OrderBook), args.Error(2)
+}
+
+func (m *MockStreamService) PublishFill(order *entity.HistoryOrder) {
+	m.Called(order)
+}
+
+func (m *MockStreamService) SubscribeFills(lastEventID uint64) (*pubsub.Subscription[*entity.FillEvent], []*entity.FillEvent) {
+	args := m.Called(lastEventID)
+	return args.Get(0).(*pubsub.Subscription[*entity.FillEvent]), args.Get(1).([]*entity.FillEvent)
+}
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeys() ([]*entity.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKey(id string) (*entity.APIKey, error) {
+	args := m.Called(id)
+	return args.Get(0).(*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) SaveAPIKey(key entity.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) RevokeAPIKey(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type MockAuthService struct {
+	mock.Mock
+}
+
+func (m *MockAuthService) CreateAPIKey(key entity.APIKey) (string, *entity.APIKey, error) {
+	args := m.Called(key)
+	return args.String(0), args.Get(1).(*entity.APIKey), args.Error(2)
+}
+
+func (m *MockAuthService) GetAPIKeys() ([]*entity.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]*entity.APIKey), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeAPIKey(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) Authenticate(token string) (*entity.APIKey, error) {
+	args := m.Called(token)
+	return args.Get(0).(*entity.APIKey), args.Error(1)
+}
+
+type MockRateQuotaRepository struct {
+	mock.Mock
+}
+
+func (m *MockRateQuotaRepository) GetRateQuotas() ([]*entity.RateQuota, error) {
+	args := m.Called()
+	return args.Get(0).([]*entity.RateQuota), args.Error(1)
+}
+
+func (m *MockRateQuotaRepository) SaveRateQuota(quota entity.RateQuota) error {
+	args := m.Called(quota)
 	return args.Error(0)
 }
+
+func (m *MockRateQuotaRepository) DeleteRateQuota(keyID, route string) error {
+	args := m.Called(keyID, route)
+	return args.Error(0)
+}
+
+type MockRateLimitService struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitService) Allow(principal, keyID, route, group string) (ratelimit.Result, bool) {
+	args := m.Called(principal, keyID, route, group)
+	return args.Get(0).(ratelimit.Result), args.Bool(1)
+}
+
+func (m *MockRateLimitService) Check(principal, keyID, route, group string) (ratelimit.Result, bool) {
+	args := m.Called(principal, keyID, route, group)
+	return args.Get(0).(ratelimit.Result), args.Bool(1)
+}
+
+func (m *MockRateLimitService) GetRateQuotas() ([]*entity.RateQuota, error) {
+	args := m.Called()
+	return args.Get(0).([]*entity.RateQuota), args.Error(1)
+}
+
+func (m *MockRateLimitService) SetRateQuota(quota entity.RateQuota) error {
+	args := m.Called(quota)
+	return args.Error(0)
+}
+
+func (m *MockRateLimitService) DeleteRateQuota(keyID, route string) error {
+	args := m.Called(keyID, route)
+	return args.Error(0)
+}
+
+type MockHealthRepository struct {
+	mock.Mock
+}
+
+func (m *MockHealthRepository) Ping(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockHealthRepository) GetTables(ctx context.Context) ([]entity.TableStatus, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.TableStatus), args.Error(1)
+}
+
+func (m *MockHealthRepository) GetColumns(ctx context.Context) ([]entity.Column, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.Column), args.Error(1)
+}
+
+type MockHealthService struct {
+	mock.Mock
+}
+
+func (m *MockHealthService) Ready(ctx context.Context) *entity.Readiness {
+	args := m.Called(ctx)
+	return args.Get(0).(*entity.Readiness)
+}
+
+func (m *MockHealthService) Status(ctx context.Context) *entity.ServiceStatus {
+	args := m.Called(ctx)
+	return args.Get(0).(*entity.ServiceStatus)
+}
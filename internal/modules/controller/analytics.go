@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+)
+
+type AnalyticsController interface {
+	GetAlgorithmPerformanceHandler(w http.ResponseWriter, r *http.Request)
+	GetSlippageAnalysisHandler(w http.ResponseWriter, r *http.Request)
+	GetMarkoutsHandler(w http.ResponseWriter, r *http.Request)
+	GetTradeBarsHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type analyticsControllerImpl struct {
+	svc service.AnalyticsService
+}
+
+func NewAnalyticsController(svc service.AnalyticsService) AnalyticsController {
+	return &analyticsControllerImpl{svc: svc}
+}
+
+// @Summary Get Algorithm Performance
+// @Description Rank algorithms by fill count, traded notional, realized PnL, slippage or fees over a time range and set of pairs.
+// @Description Filters left out are not applied; list filters may be repeated or comma-separated.
+// @Tags analytics
+// @Produce json
+// @Param client_name query string false "Client Name"
+// @Param exchange_name query string false "Exchange Name"
+// @Param pair query []string false "Trading Pairs" collectionFormat(multi)
+// @Param label query []string false "Labels" collectionFormat(multi)
+// @Param algorithm_name query []string false "Algorithm Names" collectionFormat(multi)
+// @Param from query string false "Earliest fill time, RFC 3339"
+// @Param to query string false "Latest fill time, RFC 3339"
+// @Param sort_by query string false "fill_count, traded_notional, realized_pnl, net_pnl (default), avg_slippage_bps or fees"
+// @Success 200 {array} entity.AlgorithmPerformance
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /analytics/algorithms [get]
+func (c *analyticsControllerImpl) GetAlgorithmPerformanceHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, err := historyFilterQuery(q)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	req := entity.AlgorithmPerformanceRequest{HistoryFilter: filter, SortBy: q.Get("sort_by")}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	perf, err := c.svc.GetAlgorithmPerformance(&req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(perf)
+	w.Write(bytes)
+}
+
+// @Summary Get Slippage Analysis
+// @Description Per-order slippage versus mid and touch with distributions grouped by exchange, pair and algorithm.
+// @Description Filters left out are not applied; list filters may be repeated or comma-separated.
+// @Tags analytics
+// @Produce json
+// @Param client_name query string false "Client Name"
+// @Param exchange_name query string false "Exchange Name"
+// @Param pair query []string false "Trading Pairs" collectionFormat(multi)
+// @Param label query []string false "Labels" collectionFormat(multi)
+// @Param algorithm_name query []string false "Algorithm Names" collectionFormat(multi)
+// @Param from query string false "Earliest fill time, RFC 3339"
+// @Param to query string false "Latest fill time, RFC 3339"
+// @Param bucket_bps query number false "Width of the distribution buckets in bps"
+// @Param include_orders query bool false "Include the slippage of every order"
+// @Success 200 {array} entity.SlippageAnalysis
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /analytics/slippage [get]
+func (c *analyticsControllerImpl) GetSlippageAnalysisHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, err := historyFilterQuery(q)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	req := entity.SlippageRequest{HistoryFilter: filter}
+	if req.BucketBps, err = queryFloat(q, "bucket_bps"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if req.IncludeOrders, err = queryBool(q, "include_orders"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	analysis, err := c.svc.GetSlippageAnalysis(&req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(analysis)
+	w.Write(bytes)
+}
+
+// @Summary Get Markouts
+// @Description Side-signed move of the stored order book mid at horizons after each order, aggregated per algorithm and pair.
+// @Description Filters left out are not applied; list filters may be repeated or comma-separated.
+// @Tags analytics
+// @Produce json
+// @Param client_name query string false "Client Name"
+// @Param exchange_name query string false "Exchange Name"
+// @Param pair query []string false "Trading Pairs" collectionFormat(multi)
+// @Param label query []string false "Labels" collectionFormat(multi)
+// @Param algorithm_name query []string false "Algorithm Names" collectionFormat(multi)
+// @Param from query string false "Earliest fill time, RFC 3339"
+// @Param to query string false "Latest fill time, RFC 3339"
+// @Param horizon query []string false "Horizons after each order, such as 1s or 5m" collectionFormat(multi)
+// @Success 200 {array} entity.MarkoutAnalysis
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /analytics/markouts [get]
+func (c *analyticsControllerImpl) GetMarkoutsHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, err := historyFilterQuery(q)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	req := entity.MarkoutRequest{HistoryFilter: filter, Horizons: queryList(q, "horizon")}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	markouts, err := c.svc.GetMarkouts(&req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(markouts)
+	w.Write(bytes)
+}
+
+// @Summary Get Trade Bars
+// @Description OHLCV bars of our own fills for an exchange and pair with buy/sell volume, trade count and VWAP.
+// @Description algorithm_name may be repeated or hold comma-separated values.
+// @Tags analytics
+// @Produce json
+// @Param exchange_name query string true "Exchange Name"
+// @Param pair query string true "Trading Pair"
+// @Param interval query string false "Bar width, such as 1m or 1h"
+// @Param client_name query string false "Client Name"
+// @Param algorithm_name query []string false "Algorithm Names" collectionFormat(multi)
+// @Param from query string false "Earliest fill time, RFC 3339"
+// @Param to query string false "Latest fill time, RFC 3339"
+// @Success 200 {array} entity.TradeBar
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /analytics/bars [get]
+func (c *analyticsControllerImpl) GetTradeBarsHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, err := historyFilterQuery(q)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	req := entity.TradeBarRequest{
+		ExchangeName:   filter.ExchangeName,
+		Pair:           q.Get("pair"),
+		ClientName:     filter.ClientName,
+		AlgorithmNames: filter.AlgorithmNames,
+		From:           filter.From,
+		To:             filter.To,
+		Interval:       q.Get("interval"),
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	bars, err := c.svc.GetTradeBars(&req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(bars)
+	w.Write(bytes)
+}
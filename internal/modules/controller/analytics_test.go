@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetAlgorithmPerformanceHandler(t *testing.T) {
+	mockService := &mocks.MockAnalyticsService{}
+	controller := NewAnalyticsController(mockService)
+
+	req := httptest.NewRequest("GET", "/analytics/algorithms?pair=BTC/USDT&sort_by=fees", nil)
+	rr := httptest.NewRecorder()
+
+	perf := []*entity.AlgorithmPerformance{{Rank: 1, AlgorithmNamePlaced: "algo1", FillCount: 3}}
+	mockService.On("GetAlgorithmPerformance", mock.MatchedBy(func(r *entity.AlgorithmPerformanceRequest) bool {
+		return r.SortBy == entity.SortByFees && len(r.Pairs) == 1
+	})).Return(perf, nil)
+
+	http.HandlerFunc(controller.GetAlgorithmPerformanceHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	expectedBody, _ := json.Marshal(perf)
+	assert.Equal(t, expectedBody, rr.Body.Bytes())
+}
+
+func TestGetAlgorithmPerformanceHandler_Errors(t *testing.T) {
+	mockService := &mocks.MockAnalyticsService{}
+	controller := NewAnalyticsController(mockService)
+
+	mockService.On("GetAlgorithmPerformance", mock.Anything).Return([]*entity.AlgorithmPerformance(nil), entity.ErrNotFound)
+
+	tests := map[string]int{
+		"from=yesterday": http.StatusBadRequest,
+		"sort_by=volume": http.StatusBadRequest,
+		"pair=ETH/USDT":  http.StatusNotFound,
+	}
+	for query, code := range tests {
+		req := httptest.NewRequest("GET", "/analytics/algorithms?"+query, nil)
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.GetAlgorithmPerformanceHandler).ServeHTTP(rr, req)
+
+		assert.Equal(t, code, rr.Code, query)
+	}
+}
+
+func TestGetSlippageAnalysisHandler(t *testing.T) {
+	mockService := &mocks.MockAnalyticsService{}
+	controller := NewAnalyticsController(mockService)
+
+	req := httptest.NewRequest("GET", "/analytics/slippage?exchange_name=Binance&include_orders=true", nil)
+	rr := httptest.NewRecorder()
+
+	analysis := []*entity.SlippageAnalysis{{ExchangeName: "Binance", Pair: "BTC/USDT", OrderCount: 1}}
+	mockService.On("GetSlippageAnalysis", mock.MatchedBy(func(r *entity.SlippageRequest) bool {
+		return r.ExchangeName == "Binance" && r.BucketBps == entity.DefaultSlippageBucketBps && r.IncludeOrders
+	})).Return(analysis, nil)
+
+	http.HandlerFunc(controller.GetSlippageAnalysisHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	expectedBody, _ := json.Marshal(analysis)
+	assert.Equal(t, expectedBody, rr.Body.Bytes())
+}
+
+func TestGetSlippageAnalysisHandler_BadRequest(t *testing.T) {
+	controller := NewAnalyticsController(nil)
+
+	for _, query := range []string{"bucket_bps=-1", "bucket_bps=wide", "include_orders=maybe"} {
+		req := httptest.NewRequest("GET", "/analytics/slippage?"+query, nil)
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.GetSlippageAnalysisHandler).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func TestGetMarkoutsHandler(t *testing.T) {
+	mockService := &mocks.MockAnalyticsService{}
+	controller := NewAnalyticsController(mockService)
+
+	req := httptest.NewRequest("GET", "/analytics/markouts?horizon=1s,1m", nil)
+	rr := httptest.NewRecorder()
+
+	markouts := []*entity.MarkoutAnalysis{{Pair: "BTC/USDT", AlgorithmNamePlaced: "algo1", OrderCount: 1}}
+	mockService.On("GetMarkouts", mock.MatchedBy(func(r *entity.MarkoutRequest) bool {
+		return len(r.Durations) == 2 && r.Durations[1] == time.Minute
+	})).Return(markouts, nil)
+
+	http.HandlerFunc(controller.GetMarkoutsHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	expectedBody, _ := json.Marshal(markouts)
+	assert.Equal(t, expectedBody, rr.Body.Bytes())
+}
+
+func TestGetMarkoutsHandler_BadRequest(t *testing.T) {
+	controller := NewAnalyticsController(nil)
+
+	for _, query := range []string{"from=yesterday", "horizon=soon", "horizon=-1s"} {
+		req := httptest.NewRequest("GET", "/analytics/markouts?"+query, nil)
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.GetMarkoutsHandler).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func TestGetTradeBarsHandler(t *testing.T) {
+	mockService := &mocks.MockAnalyticsService{}
+	controller := NewAnalyticsController(mockService)
+
+	req := httptest.NewRequest("GET", "/analytics/bars?exchange_name=Binance&pair=BTC/USDT&interval=5m&algorithm_name=algo1,algo2", nil)
+	rr := httptest.NewRecorder()
+
+	bars := []*entity.TradeBar{{Open: 1, High: 2, Low: 1, Close: 2, Volume: 3, TradeCount: 2}}
+	mockService.On("GetTradeBars", mock.MatchedBy(func(r *entity.TradeBarRequest) bool {
+		return r.Pair == "BTC/USDT" && r.IntervalSeconds == 300 && len(r.AlgorithmNames) == 2
+	})).Return(bars, nil)
+
+	http.HandlerFunc(controller.GetTradeBarsHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	expectedBody, _ := json.Marshal(bars)
+	assert.Equal(t, expectedBody, rr.Body.Bytes())
+}
+
+func TestGetTradeBarsHandler_BadRequest(t *testing.T) {
+	controller := NewAnalyticsController(nil)
+
+	for _, query := range []string{
+		"exchange_name=Binance&pair=BTC/USDT&from=yesterday",
+		"pair=BTC/USDT",
+		"exchange_name=Binance&pair=BTC/USDT&interval=500ms",
+		"exchange_name=Binance&pair=BTC/USDT&interval=hourly",
+	} {
+		req := httptest.NewRequest("GET", "/analytics/bars?"+query, nil)
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.GetTradeBarsHandler).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
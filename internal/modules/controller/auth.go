@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/httprate"
+)
+
+type APIKeyController interface {
+	CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request)
+	GetAPIKeysHandler(w http.ResponseWriter, r *http.Request)
+	RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type apiKeyControllerImpl struct {
+	svc service.AuthService
+}
+
+func NewAPIKeyController(svc service.AuthService) APIKeyController {
+	return &apiKeyControllerImpl{svc: svc}
+}
+
+/*
+Authenticate rejects requests that do not present a valid API key or JWT, as a bearer token
+in the Authorization header or in the X-API-Key header, and stores the key in the request context.
+Unless limits is nil, the requests failing authentication are also limited by client IP to the quota of the ip group,
+and a client IP over it is answered with 429 before its credential is checked, so it cannot keep guessing keys.
+Requests with a valid credential are not counted, so callers behind one IP are limited only by their own keys.
+*/
+func Authenticate(svc service.AuthService, limits service.RateLimitService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip, _ := httprate.KeyByIP(r)
+			if limits != nil {
+				if result, limited := limits.Check("ip:"+ip, "", "", "ip"); !writeRateLimit(w, result, limited) {
+					RateLimitedHandler(w, r)
+					return
+				}
+			}
+
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				token = r.Header.Get("X-API-Key")
+			}
+			var key *entity.APIKey
+			err := entity.ErrInvalidAPIKey
+			if token != "" {
+				key, err = svc.Authenticate(token)
+			}
+			if err != nil {
+				if limits != nil && errors.Is(err, entity.ErrUnauthorized) {
+					result, limited := limits.Allow("ip:"+ip, "", "", "ip")
+					writeRateLimit(w, result, limited)
+				}
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(service.WithAPIKey(r.Context(), key)))
+		})
+	}
+}
+
+// RequireScope rejects requests whose key was not granted scope. Handlers check the clients the key may access.
+func RequireScope(scope entity.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, err := apiKey(r)
+			if err == nil {
+				err = key.Require(scope)
+			}
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAllClients rejects requests whose key was not granted scope for every client, for routes spanning clients.
+func RequireAllClients(scope entity.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := authorize(r, scope, ""); err != nil {
+				writeError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+/*
+authorize checks that the key of the request has scope for clientName, or for every client if clientName is empty.
+Returns entity.ErrInvalidAPIKey if the request was not authenticated.
+*/
+func authorize(r *http.Request, scope entity.Scope, clientName string) error {
+	key, err := apiKey(r)
+	if err != nil {
+		return err
+	}
+	return key.Allow(scope, clientName)
+}
+
+// apiKey returns the key the request was authenticated with, or entity.ErrInvalidAPIKey if it was not.
+func apiKey(r *http.Request) (*entity.APIKey, error) {
+	key := service.APIKeyFromContext(r.Context())
+	if key == nil {
+		return nil, entity.ErrInvalidAPIKey
+	}
+	return key, nil
+}
+
+// @Summary Create API Key
+// @Description Create an API key with scopes, optionally restricted to the fills of some clients and expiring.
+// @Description The key is only returned by this request; store it, only its hash is kept.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param apiKey body entity.APIKey true "API Key"
+// @Success 201 {object} entity.CreatedAPIKey
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /admin/api-keys [post]
+func (c *apiKeyControllerImpl) CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	var req entity.APIKey
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, malformedRequest(err))
+		return
+	}
+
+	token, key, err := c.svc.CreateAPIKey(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	bytes, _ := json.Marshal(entity.CreatedAPIKey{APIKey: *key, Key: token})
+	w.Write(bytes)
+}
+
+// @Summary List API Keys
+// @Description List the API keys that are not revoked, without their secrets.
+// @Tags auth
+// @Produce json
+// @Success 200 {array} entity.APIKey
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /admin/api-keys [get]
+func (c *apiKeyControllerImpl) GetAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	keys, err := c.svc.GetAPIKeys()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(keys)
+	w.Write(bytes)
+}
+
+// @Summary Revoke API Key
+// @Description Revoke an API key. Other instances of the service may accept it for up to a minute.
+// @Tags auth
+// @Param id path string true "API Key ID"
+// @Success 204 {string} string "No Content"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /admin/api-keys/{id} [delete]
+func (c *apiKeyControllerImpl) RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	if err := c.svc.RevokeAPIKey(chi.URLParam(r, "id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+	"github.com/egorque1/vortex-test/internal/ratelimit"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var adminKey = &entity.APIKey{ID: "admin", Name: "admin", Scopes: []entity.Scope{entity.ScopeAdmin}}
+
+// withAPIKey serves requests with h as if they were authenticated with key.
+func withAPIKey(key *entity.APIKey, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h(w, r.WithContext(service.WithAPIKey(r.Context(), key)))
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	key := &entity.APIKey{ID: "key1", Scopes: []entity.Scope{entity.ScopeReadBooks}}
+	mockAuth := &mocks.MockAuthService{}
+	mockAuth.On("Authenticate", "vx_key1.secret").Return(key, nil)
+	mockAuth.On("Authenticate", mock.Anything).Return((*entity.APIKey)(nil), entity.ErrInvalidAPIKey)
+
+	handler := Authenticate(mockAuth, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, key, service.APIKeyFromContext(r.Context()))
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		header, value string
+		status        int
+	}{
+		{"Authorization", "Bearer vx_key1.secret", http.StatusOK},
+		{"X-API-Key", "vx_key1.secret", http.StatusOK},
+		{"Authorization", "", http.StatusUnauthorized},
+		{"Authorization", "Bearer vx_key1.wrong", http.StatusUnauthorized},
+		{"Authorization", "vx_key1.secret", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/v1/orderbooks/binance/BTC", nil)
+		req.Header.Set(tt.header, tt.value)
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, tt.status, rr.Code, tt.value)
+		if tt.status == http.StatusUnauthorized {
+			assert.Equal(t, "Bearer", rr.Header().Get("WWW-Authenticate"))
+			assert.Equal(t, "invalid_api_key", decodeError(t, rr).Code)
+		}
+	}
+}
+
+func TestAuthenticate_RateLimit(t *testing.T) {
+	key := &entity.APIKey{ID: "key1", Scopes: []entity.Scope{entity.ScopeReadBooks}}
+	mockAuth := &mocks.MockAuthService{}
+	mockAuth.On("Authenticate", "vx_key1.secret").Return(key, nil)
+	mockAuth.On("Authenticate", mock.Anything).Return((*entity.APIKey)(nil), entity.ErrInvalidAPIKey)
+	mockLimits := &mocks.MockRateLimitService{}
+
+	handler := Authenticate(mockAuth, mockLimits)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/v1/orderbooks/binance/BTC", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set("X-API-Key", token)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	allowed := ratelimit.Result{Allowed: true, Limit: 20, Remaining: 19}
+	denied := ratelimit.Result{Limit: 20, RetryAfter: 100 * time.Millisecond}
+
+	// Test case: A valid key is checked against the IP bucket without taking from it.
+	mockLimits.On("Check", "ip:192.0.2.1", "", "", "ip").Return(allowed, true).Once()
+	rr := serve("vx_key1.secret")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockLimits.AssertNotCalled(t, "Allow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// Test case: A wrong key takes from the IP bucket.
+	mockLimits.On("Check", "ip:192.0.2.1", "", "", "ip").Return(allowed, true).Once()
+	mockLimits.On("Allow", "ip:192.0.2.1", "", "", "ip").Return(ratelimit.Result{Allowed: true, Limit: 20, Remaining: 18}, true).Once()
+	rr = serve("vx_key1.wrong")
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, "18", rr.Header().Get("RateLimit-Remaining"))
+
+	// Test case: Once the IP bucket is empty, even a valid key is rejected before it is checked.
+	mockLimits.On("Check", "ip:192.0.2.1", "", "", "ip").Return(denied, true).Once()
+	rr = serve("vx_key1.secret")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+	mockAuth.AssertNumberOfCalls(t, "Authenticate", 2)
+	mockLimits.AssertExpectations(t)
+}
+
+func TestRequireScope(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	restricted := &entity.APIKey{ID: "key1", Scopes: []entity.Scope{entity.ScopeReadHistory}, ClientNames: []string{"client1"}}
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		key     *entity.APIKey
+		status  int
+	}{
+		{"scope granted", RequireScope(entity.ScopeReadHistory)(ok), restricted, http.StatusOK},
+		{"admin has every scope", RequireScope(entity.ScopeWriteBooks)(ok), adminKey, http.StatusOK},
+		{"scope missing", RequireScope(entity.ScopeWriteHistory)(ok), restricted, http.StatusForbidden},
+		{"restricted to some clients", RequireAllClients(entity.ScopeReadHistory)(ok), restricted, http.StatusForbidden},
+		{"not authenticated", RequireScope(entity.ScopeReadBooks)(ok), nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		if tt.key != nil {
+			req = req.WithContext(service.WithAPIKey(req.Context(), tt.key))
+		}
+		rr := httptest.NewRecorder()
+
+		tt.handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, tt.status, rr.Code, tt.name)
+	}
+}
+
+func TestGetOrderHistoryV1Handler_OtherClient(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	controller := NewController(&mocks.MockOrderRepository{}, mockService)
+	key := &entity.APIKey{ID: "key1", Scopes: []entity.Scope{entity.ScopeReadHistory}, ClientNames: []string{"client1"}}
+
+	router := chi.NewRouter()
+	router.Get("/v1/history/{clientName}", withAPIKey(key, controller.GetOrderHistoryV1Handler))
+
+	mockService.On("FindOrderHistory", mock.Anything).Return([]*entity.HistoryOrder{}, nil)
+
+	for client, status := range map[string]int{"client1": http.StatusOK, "client2": http.StatusForbidden} {
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/v1/history/"+client, nil))
+
+		assert.Equal(t, status, rr.Code, client)
+		if status == http.StatusForbidden {
+			assert.Equal(t, "forbidden", decodeError(t, rr).Code)
+		}
+	}
+	mockService.AssertNumberOfCalls(t, "FindOrderHistory", 1)
+}
+
+func TestCreateAPIKeyHandler(t *testing.T) {
+	mockAuth := &mocks.MockAuthService{}
+	controller := NewAPIKeyController(mockAuth)
+
+	req := entity.APIKey{Name: "collector", Scopes: []entity.Scope{entity.ScopeWriteBooks}}
+	created := req
+	created.ID = "key1"
+	mockAuth.On("CreateAPIKey", req).Return("vx_key1.secret", &created, nil)
+
+	body, _ := json.Marshal(req)
+	rr := httptest.NewRecorder()
+
+	controller.CreateAPIKeyHandler(rr, httptest.NewRequest("POST", "/admin/api-keys", bytes.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	var resp entity.CreatedAPIKey
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, "vx_key1.secret", resp.Key)
+	assert.Equal(t, "key1", resp.ID)
+	assert.NotContains(t, rr.Body.String(), "secret_hash")
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+	"github.com/go-chi/chi"
+)
+
+type ClientController interface {
+	CreateClientHandler(w http.ResponseWriter, r *http.Request)
+	GetClientsHandler(w http.ResponseWriter, r *http.Request)
+	GetClientHandler(w http.ResponseWriter, r *http.Request)
+	UpdateClientHandler(w http.ResponseWriter, r *http.Request)
+	DeleteClientHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type clientControllerImpl struct {
+	svc service.ClientService
+}
+
+func NewClientController(svc service.ClientService) ClientController {
+	return &clientControllerImpl{svc: svc}
+}
+
+// @Summary Create Client Account
+// @Description Register a client account on an exchange under a label, optionally restricted to a set of pairs.
+// @Tags client
+// @Accept json
+// @Produce json
+// @Param clientAccount body entity.ClientAccount true "Client Account"
+// @Success 201 {string} string "Created"
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 409 {object} entity.ErrorResponse "Conflict"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /clients [post]
+func (c *clientControllerImpl) CreateClientHandler(w http.ResponseWriter, r *http.Request) {
+	var req entity.ClientAccount
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, malformedRequest(err))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err := c.svc.CreateClient(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// @Summary List Client Accounts
+// @Description List registered client accounts, optionally of a single client.
+// @Tags client
+// @Produce json
+// @Param client_name query string false "Client Name"
+// @Success 200 {array} entity.ClientAccount
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /clients [get]
+func (c *clientControllerImpl) GetClientsHandler(w http.ResponseWriter, r *http.Request) {
+	accounts, err := c.svc.GetClients(r.URL.Query().Get("client_name"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(accounts)
+	w.Write(bytes)
+}
+
+// @Summary Get Client Account
+// @Description Retrieve a registered client account.
+// @Tags client
+// @Produce json
+// @Param clientName path string true "Client Name"
+// @Param exchangeName path string true "Exchange Name"
+// @Param label path string true "Label"
+// @Success 200 {object} entity.ClientAccount
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /clients/{clientName}/{exchangeName}/{label} [get]
+func (c *clientControllerImpl) GetClientHandler(w http.ResponseWriter, r *http.Request) {
+	account, err := c.svc.GetClient(chi.URLParam(r, "clientName"), chi.URLParam(r, "exchangeName"), chi.URLParam(r, "label"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(account)
+	w.Write(bytes)
+}
+
+// @Summary Update Client Account
+// @Description Replace the pairs of a registered client account.
+// @Tags client
+// @Accept json
+// @Param clientName path string true "Client Name"
+// @Param exchangeName path string true "Exchange Name"
+// @Param label path string true "Label"
+// @Param clientAccount body entity.ClientAccount true "Client Account"
+// @Success 200 {string} string "OK"
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /clients/{clientName}/{exchangeName}/{label} [put]
+func (c *clientControllerImpl) UpdateClientHandler(w http.ResponseWriter, r *http.Request) {
+	var req entity.ClientAccount
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, malformedRequest(err))
+		return
+	}
+	req.ClientName = chi.URLParam(r, "clientName")
+	req.ExchangeName = chi.URLParam(r, "exchangeName")
+	req.Label = chi.URLParam(r, "label")
+
+	err := c.svc.UpdateClient(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Delete Client Account
+// @Description Remove a registered client account. Its history is kept.
+// @Tags client
+// @Param clientName path string true "Client Name"
+// @Param exchangeName path string true "Exchange Name"
+// @Param label path string true "Label"
+// @Success 204 {string} string "No Content"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /clients/{clientName}/{exchangeName}/{label} [delete]
+func (c *clientControllerImpl) DeleteClientHandler(w http.ResponseWriter, r *http.Request) {
+	err := c.svc.DeleteClient(chi.URLParam(r, "clientName"), chi.URLParam(r, "exchangeName"), chi.URLParam(r, "label"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
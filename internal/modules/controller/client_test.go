@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func withAccountParams(req *http.Request, clientName, exchangeName, label string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("clientName", clientName)
+	rctx.URLParams.Add("exchangeName", exchangeName)
+	rctx.URLParams.Add("label", label)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestCreateClientHandler(t *testing.T) {
+	mockService := &mocks.MockClientService{}
+	controller := NewClientController(mockService)
+
+	account := entity.ClientAccount{ClientName: "client1", ExchangeName: "exchange1", Label: "label1", Pairs: []string{"BTC/USDT"}}
+	mockService.On("CreateClient", account).Return(nil).Once()
+	mockService.On("CreateClient", account).Return(entity.ErrClientExists)
+
+	reqBody, _ := json.Marshal(account)
+	for _, code := range []int{http.StatusCreated, http.StatusConflict} {
+		req := httptest.NewRequest("POST", "/clients", bytes.NewBuffer(reqBody))
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.CreateClientHandler).ServeHTTP(rr, req)
+
+		assert.Equal(t, code, rr.Code)
+	}
+
+	req := httptest.NewRequest("POST", "/clients", bytes.NewBufferString(`{"client_name": "client1"}`))
+	rr := httptest.NewRecorder()
+
+	http.HandlerFunc(controller.CreateClientHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetClientHandler(t *testing.T) {
+	mockService := &mocks.MockClientService{}
+	controller := NewClientController(mockService)
+
+	account := &entity.ClientAccount{ClientName: "client1", ExchangeName: "exchange1", Label: "label1"}
+	mockService.On("GetClient", "client1", "exchange1", "label1").Return(account, nil)
+	mockService.On("GetClient", "client2", "exchange1", "label1").Return((*entity.ClientAccount)(nil), entity.ErrNotFound)
+
+	req := withAccountParams(httptest.NewRequest("GET", "/clients/client1/exchange1/label1", nil), "client1", "exchange1", "label1")
+	rr := httptest.NewRecorder()
+
+	http.HandlerFunc(controller.GetClientHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expectedBody, _ := json.Marshal(account)
+	assert.Equal(t, expectedBody, rr.Body.Bytes())
+
+	req = withAccountParams(httptest.NewRequest("GET", "/clients/client2/exchange1/label1", nil), "client2", "exchange1", "label1")
+	rr = httptest.NewRecorder()
+
+	http.HandlerFunc(controller.GetClientHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestUpdateAndDeleteClientHandler(t *testing.T) {
+	mockService := &mocks.MockClientService{}
+	controller := NewClientController(mockService)
+
+	mockService.On("UpdateClient", entity.ClientAccount{ClientName: "client1", ExchangeName: "exchange1", Label: "label1", Pairs: []string{"ETH/USDT"}}).Return(nil)
+	mockService.On("DeleteClient", "client1", "exchange1", "label1").Return(entity.ErrNotFound)
+
+	req := withAccountParams(httptest.NewRequest("PUT", "/clients/client1/exchange1/label1", bytes.NewBufferString(`{"pairs": ["ETH/USDT"]}`)), "client1", "exchange1", "label1")
+	rr := httptest.NewRecorder()
+
+	http.HandlerFunc(controller.UpdateClientHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req = withAccountParams(httptest.NewRequest("DELETE", "/clients/client1/exchange1/label1", nil), "client1", "exchange1", "label1")
+	rr = httptest.NewRecorder()
+
+	http.HandlerFunc(controller.DeleteClientHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	orderv1 "github.com/egorque1/vortex-test/api/order/v1"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/protoconv"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Media types the order book and history endpoints read and write. Error bodies are always JSON.
+const (
+	mediaJSON     = "application/json"
+	mediaProtobuf = "application/x-protobuf"
+	mediaMsgpack  = "application/msgpack"
+)
+
+var (
+	errUnsupportedMediaType = errors.New("unsupported media type")
+	errNotAcceptable        = errors.New("not acceptable")
+)
+
+// codec reads request bodies and writes response bodies in one media type.
+type codec interface {
+	decode(r io.Reader, v any) error
+	encode(v any) ([]byte, error)
+}
+
+var codecs = map[string]codec{
+	mediaJSON:     jsonCodec{},
+	mediaProtobuf: protobufCodec{},
+	mediaMsgpack:  msgpackCodec{},
+}
+
+// mediaAliases are other names clients use for the supported media types.
+var mediaAliases = map[string]string{
+	"application/protobuf":     mediaProtobuf,
+	"application/vnd.msgpack":  mediaMsgpack,
+	"application/x-msgpack":    mediaMsgpack,
+	"application/*":            mediaJSON,
+	"*/*":                      mediaJSON,
+	"application/octet-stream": "",
+}
+
+func canonicalMedia(mediaType string) string {
+	if alias, ok := mediaAliases[mediaType]; ok {
+		return alias
+	}
+	return mediaType
+}
+
+/*
+decodeBody decodes the request body into v in the media type of its Content-Type, JSON if none is given.
+Returns errUnsupportedMediaType for other media types, or a validation error for a malformed body.
+*/
+func decodeBody(r *http.Request, v any) error {
+	mediaType := mediaJSON
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errUnsupportedMediaType, err)
+		}
+		mediaType = canonicalMedia(parsed)
+	}
+
+	c, ok := codecs[mediaType]
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnsupportedMediaType, mediaType)
+	}
+	if err := c.decode(r.Body, v); err != nil {
+		if errors.Is(err, errUnsupportedMediaType) {
+			return err
+		}
+		return malformedRequest(err)
+	}
+	return nil
+}
+
+/*
+negotiate returns the supported media type the Accept header of the request prefers, JSON if it has none.
+Returns errNotAcceptable if the header accepts none of them.
+*/
+func negotiate(r *http.Request) (string, error) {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return mediaJSON, nil
+	}
+
+	best, bestQ, bestWildcard := "", 0.0, false
+	for _, part := range strings.Split(accept, ",") {
+		parsed, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		mediaType := canonicalMedia(parsed)
+		if _, ok := codecs[mediaType]; !ok {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		// Of equally preferred types the first listed wins, but a named type beats a wildcard.
+		wildcard := strings.HasSuffix(parsed, "*")
+		if q > bestQ || q == bestQ && bestWildcard && !wildcard {
+			best, bestQ, bestWildcard = mediaType, q, wildcard
+		}
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("%w: %s", errNotAcceptable, accept)
+	}
+	return best, nil
+}
+
+// writeBody writes v with status in the media type negotiated with the Accept header of the request.
+func writeBody(w http.ResponseWriter, r *http.Request, status int, v any) {
+	w.Header().Add("Vary", "Accept")
+
+	mediaType, err := negotiate(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	body, err := codecs[mediaType].encode(v)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) decode(r io.Reader, v any) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+func (jsonCodec) encode(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// msgpackCodec encodes values with their JSON field names, so both encodings have the same shape.
+type msgpackCodec struct{}
+
+func (msgpackCodec) decode(r io.Reader, v any) error {
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+func (msgpackCodec) encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// protobufCodec encodes values as the messages of the gRPC API, see api/order/v1/order.proto.
+type protobufCodec struct{}
+
+func (protobufCodec) decode(r io.Reader, v any) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case *entity.OrderBookRequest:
+		var msg orderv1.GetOrderBookRequest
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			return err
+		}
+		*v = entity.OrderBookRequest{Exchange_name: msg.GetExchange(), Pair: msg.GetPair()}
+	case *[]*entity.OrderBook:
+		var msg orderv1.SaveOrderBookRequest
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			return err
+		}
+		*v = protoconv.FromOrderBooks(msg.GetBooks())
+	case *entity.Client:
+		var msg orderv1.Client
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			return err
+		}
+		*v = *protoconv.FromClient(&msg)
+	case *entity.HistoryOrder:
+		var msg orderv1.HistoryOrder
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			return err
+		}
+		*v = protoconv.FromHistoryOrder(&msg)
+	case *entity.IngestMessage:
+		var msg orderv1.IngestMessage
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			return err
+		}
+		*v = protoconv.FromIngestMessage(&msg)
+	default:
+		return fmt.Errorf("%w: no protobuf message for %T", errUnsupportedMediaType, v)
+	}
+	return nil
+}
+
+func (protobufCodec) encode(v any) ([]byte, error) {
+	var msg proto.Message
+	switch v := v.(type) {
+	case []*entity.OrderBook:
+		msg = &orderv1.GetOrderBookResponse{Books: protoconv.ToOrderBooks(v)}
+	case []*entity.HistoryOrder:
+		msg = &orderv1.GetOrderHistoryResponse{Orders: protoconv.ToHistoryOrders(v)}
+	case *entity.SaveResult:
+		msg = protoconv.ToSaveResult(v)
+	case *entity.IngestReply:
+		msg = protoconv.ToIngestReply(v)
+	default:
+		return nil, fmt.Errorf("%w: no protobuf message for %T", errNotAcceptable, v)
+	}
+	return proto.Marshal(msg)
+}
@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	orderv1 "github.com/egorque1/vortex-test/api/order/v1"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/egorque1/vortex-test/internal/protoconv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", mediaJSON},
+		{"*/*", mediaJSON},
+		{"application/x-protobuf", mediaProtobuf},
+		{"application/vnd.msgpack", mediaMsgpack},
+		{"text/html, application/msgpack;q=0.9, application/json;q=0.5", mediaMsgpack},
+		{"*/*;q=0.8, application/x-protobuf;q=0.8", mediaProtobuf},
+		{"application/json, application/msgpack", mediaJSON},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", tt.accept)
+
+		got, err := negotiate(r)
+
+		assert.NoError(t, err, tt.accept)
+		assert.Equal(t, tt.want, got, tt.accept)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/csv")
+	_, err := negotiate(r)
+	assert.ErrorIs(t, err, errNotAcceptable)
+}
+
+func TestSaveOrderBookHandler_Protobuf(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	controller := NewController(&mocks.MockOrderRepository{}, mockService)
+
+	book := &entity.OrderBook{
+		Exchange:  "Binance",
+		Pair:      "BTC/USDT",
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Asks:      []entity.DepthOrder{{Price: 30000, BaseQty: 0.5}},
+		Bids:      []entity.DepthOrder{{Price: 29900, BaseQty: 1}},
+	}
+	body, _ := proto.Marshal(&orderv1.SaveOrderBookRequest{Books: protoconv.ToOrderBooks([]*entity.OrderBook{book})})
+
+	mockService.On("SaveOrderBook", mock.MatchedBy(func(books []*entity.OrderBook) bool {
+		return len(books) == 1 && assert.ObjectsAreEqual(book, books[0])
+	})).Return(&entity.SaveResult{Saved: 1}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/orderbook", bytes.NewReader(body))
+	req.Header.Set("Content-Type", mediaProtobuf)
+	req.Header.Set("Accept", mediaProtobuf)
+	rr := httptest.NewRecorder()
+
+	controller.SaveOrderBookHandler(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, mediaProtobuf, rr.Header().Get("Content-Type"))
+
+	var result orderv1.SaveResult
+	assert.NoError(t, proto.Unmarshal(rr.Body.Bytes(), &result))
+	assert.Equal(t, int32(1), result.GetSaved())
+	mockService.AssertExpectations(t)
+}
+
+func TestGetOrderHistoryHandler_Msgpack(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	controller := NewController(&mocks.MockOrderRepository{}, mockService)
+
+	client := entity.Client{ClientName: "client1", ExchangeName: "Binance", Label: "label1", Pair: "BTC/USDT"}
+	var body bytes.Buffer
+	enc := msgpack.NewEncoder(&body)
+	enc.SetCustomStructTag("json")
+	assert.NoError(t, enc.Encode(client))
+
+	orders := []*entity.HistoryOrder{{ClientName: "client1", Side: entity.SideBuy, Type: entity.OrderTypeLimit, BaseQty: 1, Price: 30000}}
+	mockService.On("GetOrderHistory", &client).Return(orders, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/history", &body)
+	req.Header.Set("Content-Type", mediaMsgpack)
+	req.Header.Set("Accept", mediaMsgpack)
+	rr := httptest.NewRecorder()
+
+	withAPIKey(adminKey, controller.GetOrderHistoryHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, mediaMsgpack, rr.Header().Get("Content-Type"))
+
+	var got []*entity.HistoryOrder
+	dec := msgpack.NewDecoder(rr.Body)
+	dec.SetCustomStructTag("json")
+	assert.NoError(t, dec.Decode(&got))
+	assert.Len(t, got, 1)
+	assert.Equal(t, entity.SideBuy, got[0].Side)
+	assert.Equal(t, 30000.0, got[0].Price)
+}
+
+func TestOrderHandlers_UnsupportedMediaType(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	controller := NewController(&mocks.MockOrderRepository{}, mockService)
+
+	req := httptest.NewRequest(http.MethodPost, "/orderbook", bytes.NewBufferString("exchange,pair"))
+	req.Header.Set("Content-Type", "text/csv")
+	rr := httptest.NewRecorder()
+
+	controller.SaveOrderBookHandler(rr, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+	assert.Equal(t, "unsupported_media_type", decodeError(t, rr).Code)
+	mockService.AssertNotCalled(t, "SaveOrderBook", mock.Anything)
+}
+
+func TestOrderHandlers_NotAcceptable(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	controller := NewController(&mocks.MockOrderRepository{}, mockService)
+
+	mockService.On("GetOrderBook", "Binance", "BTC/USDT").Return([]*entity.OrderBook{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/orderbook", bytes.NewBufferString(`{"exchange":"Binance","pair":"BTC/USDT"}`))
+	req.Header.Set("Accept", "text/csv")
+	rr := httptest.NewRecorder()
+
+	controller.GetOrderBookHandler(rr, req)
+
+	assert.Equal(t, http.StatusNotAcceptable, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "not_acceptable", decodeError(t, rr).Code)
+}
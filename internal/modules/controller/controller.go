@@ -1,13 +1,16 @@
 package controller
 
 import (
-	"encoding/json"
+	"bytes"
+	"crypto/sha256"
+	"io"
 	"net/http"
+	"time"
 
+	"github.com/egorque1/vortex-test/internal/cache"
 	"github.com/egorque1/vortex-test/internal/entity"
 	"github.com/egorque1/vortex-test/internal/modules/repository"
 	"github.com/egorque1/vortex-test/internal/modules/service"
-	"gorm.io/gorm"
 )
 
 type OrderController interface {
@@ -15,133 +18,334 @@ type OrderController interface {
 	SaveOrderBookHandler(w http.ResponseWriter, r *http.Request)
 	GetOrderHistoryHandler(w http.ResponseWriter, r *http.Request)
 	SaveOrderHistoryHandler(w http.ResponseWriter, r *http.Request)
+	GetOrderBookV1Handler(w http.ResponseWriter, r *http.Request)
+	GetOrderHistoryV1Handler(w http.ResponseWriter, r *http.Request)
 }
 
+// idempotencyTTL is how long the result of a request carrying an Idempotency-Key is replayed.
+const idempotencyTTL = 10 * time.Minute
+
 type orderControllerImpl struct {
-	repo repository.OrderRepository
-	svc  service.OrderService
+	repo        repository.OrderRepository
+	svc         service.OrderService
+	idempotency *cache.Cache
 }
 
 func NewController(repo repository.OrderRepository, svc service.OrderService) OrderController {
-	return &orderControllerImpl{repo: repo, svc: svc}
+	return &orderControllerImpl{repo: repo, svc: svc, idempotency: cache.New(idempotencyTTL)}
 }
 
 // @Summary Get Order Book
 // @Description Retrieve the order book for a specific exchange and trading pair.
+// @Description Kept for existing callers, use GET /v1/orderbooks/{exchange}/{pair} instead.
 // @Tags order
-// @Accept json
-// @Produce json
+// @Deprecated
+// @Accept json,application/x-protobuf,application/msgpack
+// @Produce json,application/x-protobuf,application/msgpack
 // @Param orderBookRequest body entity.OrderBookRequest true "Order Book Request"
 // @Success 200 {array} entity.OrderBook
-// @Failure 400 {string} string "Bad Request"
-// @Failure 404 {string} string "Not Found"
-// @Failure 500 {string} string "Internal Server Error"
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 406 {object} entity.ErrorResponse "Not Acceptable"
+// @Failure 415 {object} entity.ErrorResponse "Unsupported Media Type"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
 // @Router /orderbook [get]
 func (c *orderControllerImpl) GetOrderBookHandler(w http.ResponseWriter, r *http.Request) {
 	var req entity.OrderBookRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	ob, err := c.svc.GetOrderBook(req.Exchange_name, req.Pair)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	bytes, _ := json.Marshal(ob)
-	w.Write(bytes)
+	writeBody(w, r, http.StatusOK, ob)
 }
 
 // @Summary Save Order Book
-// @Description Save a new order book entry.
+// @Description Save new order book entries. Snapshots already saved under the same dedup key are reported as duplicates.
 // @Tags order
-// @Accept json
-// @Produce json
+// @Accept json,application/x-protobuf,application/msgpack
+// @Produce json,application/x-protobuf,application/msgpack
+// @Param Idempotency-Key header string false "Replays the result of an earlier request with the same key and body"
 // @Param orderBooks body []entity.OrderBook true "Order Books"
-// @Success 200 {string} string "OK"
-// @Failure 400 {string} string "Bad Request"
-// @Failure 500 {string} string "Internal Server Error"
+// @Success 200 {object} entity.SaveResult
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 422 {object} entity.ErrorResponse "Idempotency-Key reused with another body"
+// @Failure 406 {object} entity.ErrorResponse "Not Acceptable"
+// @Failure 415 {object} entity.ErrorResponse "Unsupported Media Type"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
 // @Router /orderbook [post]
 func (c *orderControllerImpl) SaveOrderBookHandler(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	bodyHash, replayed := c.replayIdempotent(w, r, "orderbook:", idempotencyKey)
+	if replayed {
+		return
+	}
+
 	var req []*entity.OrderBook
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	err := c.svc.SaveOrderBook(req)
+	result, err := c.svc.SaveOrderBook(req)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	c.storeIdempotent(r, "orderbook:", idempotencyKey, bodyHash, result)
+
+	writeBody(w, r, http.StatusOK, result)
 }
 
 // @Summary Get Order History
 // @Description Retrieve the order history for a specific client.
+// @Description Kept for existing callers, use GET /v1/history/{clientName} instead.
 // @Tags order
-// @Accept json
-// @Produce json
+// @Deprecated
+// @Accept json,application/x-protobuf,application/msgpack
+// @Produce json,application/x-protobuf,application/msgpack
 // @Param client body entity.Client true "Client"
 // @Success 200 {array} entity.HistoryOrder
-// @Failure 400 {string} string "Bad Request"
-// @Failure 404 {string} string "Not Found"
-// @Failure 500 {string} string "Internal Server Error"
-// @Router /orderhistory [get]
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 406 {object} entity.ErrorResponse "Not Acceptable"
+// @Failure 415 {object} entity.ErrorResponse "Unsupported Media Type"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /history [get]
 func (c *orderControllerImpl) GetOrderHistoryHandler(w http.ResponseWriter, r *http.Request) {
-	var req *entity.Client
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	var req entity.Client
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	ho, err := c.svc.GetOrderHistory(req)
+	if err := authorize(r, entity.ScopeReadHistory, req.ClientName); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	ho, err := c.svc.GetOrderHistory(&req)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	bytes, _ := json.Marshal(ho)
-	w.Write(bytes)
+	writeBody(w, r, http.StatusOK, ho)
 }
 
 // @Summary Save Order History
-// @Description Save a new history order entry.
+// @Description Save a new history order entry. An order already saved under the same dedup key is reported as a duplicate.
 // @Tags order
-// @Accept json
-// @Produce json
+// @Accept json,application/x-protobuf,application/msgpack
+// @Produce json,application/x-protobuf,application/msgpack
+// @Param Idempotency-Key header string false "Replays the result of an earlier request with the same key and body"
 // @Param historyOrder body entity.HistoryOrder true "History Order"
-// @Success 200 {string} string "OK"
-// @Failure 400 {string} string "Bad Request"
-// @Failure 500 {string} string "Internal Server Error"
-// @Router /orderhistory [post]
+// @Success 200 {object} entity.SaveResult
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 422 {object} entity.ErrorResponse "Unregistered client account or Idempotency-Key reused with another body"
+// @Failure 406 {object} entity.ErrorResponse "Not Acceptable"
+// @Failure 415 {object} entity.ErrorResponse "Unsupported Media Type"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /history [post]
 func (c *orderControllerImpl) SaveOrderHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	bodyHash, replayed := c.replayIdempotent(w, r, "history:", idempotencyKey)
+	if replayed {
+		return
+	}
+
 	var req entity.HistoryOrder
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	if err := decodeBody(r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := authorize(r, entity.ScopeWriteHistory, req.ClientName); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result, err := c.svc.SaveOrderHistory(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	c.storeIdempotent(r, "history:", idempotencyKey, bodyHash, result)
+
+	writeBody(w, r, http.StatusOK, result)
+}
+
+// @Summary Get Order Book
+// @Description Retrieve the order book snapshots of an exchange and trading pair. A pair containing a slash is URL-encoded, as in BTC%2FUSDT.
+// @Tags order
+// @Produce json,application/x-protobuf,application/msgpack
+// @Param exchange path string true "Exchange Name"
+// @Param pair path string true "Trading Pair"
+// @Success 200 {array} entity.OrderBook
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 406 {object} entity.ErrorResponse "Not Acceptable"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /v1/orderbooks/{exchange}/{pair} [get]
+func (c *orderControllerImpl) GetOrderBookV1Handler(w http.ResponseWriter, r *http.Request) {
+	exchange, err := pathParam(r, "exchange")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	pair, err := pathParam(r, "pair")
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	err := c.svc.SaveOrderHistory(req)
+	ob, err := c.svc.GetOrderBook(exchange, pair)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	writeBody(w, r, http.StatusOK, ob)
+}
+
+// @Summary Get Order History
+// @Description Retrieve the fills of a client, oldest first. Filters left out are not applied; list filters may be repeated or comma-separated.
+// @Tags order
+// @Produce json,application/x-protobuf,application/msgpack
+// @Param clientName path string true "Client Name"
+// @Param exchange_name query string false "Exchange Name"
+// @Param pair query []string false "Trading Pairs" collectionFormat(multi)
+// @Param label query []string false "Labels" collectionFormat(multi)
+// @Param algorithm_name query []string false "Algorithm Names" collectionFormat(multi)
+// @Param from query string false "Earliest fill time, RFC 3339"
+// @Param to query string false "Latest fill time, RFC 3339"
+// @Success 200 {array} entity.HistoryOrder
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 406 {object} entity.ErrorResponse "Not Acceptable"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /v1/history/{clientName} [get]
+func (c *orderControllerImpl) GetOrderHistoryV1Handler(w http.ResponseWriter, r *http.Request) {
+	filter, err := historyFilterQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if filter.ClientName, err = pathParam(r, "clientName"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := authorize(r, entity.ScopeReadHistory, filter.ClientName); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	ho, err := c.svc.FindOrderHistory(&filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeBody(w, r, http.StatusOK, ho)
+}
+
+// idempotentResult is the result of a request carrying an Idempotency-Key and the hash of the body it was made with.
+type idempotentResult struct {
+	bodyHash [sha256.Size]byte
+	result   *entity.SaveResult
+}
+
+/*
+replayIdempotent writes the stored result of an earlier request made with the same Idempotency-Key and body,
+or an error if the key was used with a different body, and reports that the request was answered.
+Otherwise it writes nothing and returns the hash of the body to store the result under;
+the body is left to be read again.
+*/
+func (c *orderControllerImpl) replayIdempotent(w http.ResponseWriter, r *http.Request, prefix, key string) ([sha256.Size]byte, bool) {
+	if key == "" {
+		return [sha256.Size]byte{}, false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, r, malformedRequest(err))
+		return [sha256.Size]byte{}, true
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	bodyHash := sha256.Sum256(body)
+
+	stored, ok := c.idempotency.Get(idempotencyCacheKey(r, prefix, key))
+	if !ok {
+		return bodyHash, false
+	}
+
+	earlier := stored.(*idempotentResult)
+	if earlier.bodyHash != bodyHash {
+		writeError(w, r, entity.ErrIdempotencyKeyReused)
+		return bodyHash, true
+	}
+
+	result := *earlier.result
+	result.Replayed = true
+
+	writeBody(w, r, http.StatusOK, &result)
+	return bodyHash, true
+}
+
+// storeIdempotent keeps the result of a request carrying an Idempotency-Key for replayIdempotent.
+func (c *orderControllerImpl) storeIdempotent(r *http.Request, prefix, key string, bodyHash [sha256.Size]byte, result *entity.SaveResult) {
+	if key != "" {
+		c.idempotency.Set(idempotencyCacheKey(r, prefix, key), &idempotentResult{bodyHash: bodyHash, result: result})
+	}
+}
+
+// idempotencyCacheKey scopes an Idempotency-Key to the API key of the request, so callers cannot replay each other's results.
+func idempotencyCacheKey(r *http.Request, prefix, key string) string {
+	if apiKey := service.APIKeyFromContext(r.Context()); apiKey != nil {
+		return prefix + apiKey.ID + ":" + key
+	}
+	return prefix + key
 }
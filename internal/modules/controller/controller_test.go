@@ -6,9 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/egorque1/vortex-test/internal/entity"
 	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/go-chi/chi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -101,7 +103,7 @@ func TestGetOrderHistoryHandler(t *testing.T) {
 
 	mockService.On("GetOrderHistory", mock.Anything).Return([]*entity.HistoryOrder{}, nil)
 
-	http.HandlerFunc(controller.GetOrderHistoryHandler).ServeHTTP(rr, req)
+	withAPIKey(adminKey, controller.GetOrderHistoryHandler).ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Errorf("expected status OK; got %d", rr.Code)
@@ -124,7 +126,7 @@ func TestGetOrderHistoryHandler_BadRequest(t *testing.T) {
 
 	controller := NewController(nil, nil)
 
-	http.HandlerFunc(controller.GetOrderHistoryHandler).ServeHTTP(rr, req)
+	withAPIKey(adminKey, controller.GetOrderHistoryHandler).ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusBadRequest {
 		t.Errorf("expected status BadRequest; got %d", rr.Code)
@@ -138,15 +140,17 @@ func TestSaveOrderHistoryHandler(t *testing.T) {
 
 	order := entity.HistoryOrder{
 		ClientName: "client1",
+		Side:       entity.SideBuy,
+		Type:       entity.OrderTypeLimit,
 	}
 	reqBody, _ := json.Marshal(order)
 	req := httptest.NewRequest("POST", "/saveOrderHistory", bytes.NewBuffer(reqBody))
 	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
 
-	mockService.On("SaveOrderHistory", order).Return(nil)
+	mockService.On("SaveOrderHistory", order).Return(&entity.SaveResult{Saved: 1, Duplicates: []string{}}, nil)
 
-	http.HandlerFunc(controller.SaveOrderHistoryHandler).ServeHTTP(rr, req)
+	withAPIKey(adminKey, controller.SaveOrderHistoryHandler).ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Errorf("expected status OK; got %d", rr.Code)
@@ -164,9 +168,163 @@ func TestSaveOrderHistoryHandler_BadRequest(t *testing.T) {
 
 	controller := NewController(nil, nil)
 
-	http.HandlerFunc(controller.SaveOrderHistoryHandler).ServeHTTP(rr, req)
+	withAPIKey(adminKey, controller.SaveOrderHistoryHandler).ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusBadRequest {
 		t.Errorf("expected status BadRequest; got %d", rr.Code)
 	}
 }
+
+func TestSaveOrderBookHandler_Idempotent(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	mockRepo := &mocks.MockOrderRepository{}
+	controller := NewController(mockRepo, mockService)
+
+	mockService.On("SaveOrderBook", mock.Anything).Return(&entity.SaveResult{Saved: 1, Duplicates: []string{}}, nil).Once()
+
+	for i, expected := range []string{`{"saved":1,"duplicates":[]}`, `{"saved":1,"duplicates":[],"replayed":true}`} {
+		req := httptest.NewRequest("POST", "/orderbook", bytes.NewBufferString(`[{"Exchange": "Binance", "Pair": "BTC/USDT"}]`))
+		req.Header.Set("Idempotency-Key", "batch-1")
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.SaveOrderBookHandler).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code, i)
+		assert.Equal(t, expected, rr.Body.String(), i)
+	}
+
+	// Test case: the same key with another body
+	req := httptest.NewRequest("POST", "/orderbook", bytes.NewBufferString(`[{"Exchange": "Binance", "Pair": "ETH/USDT"}]`))
+	req.Header.Set("Idempotency-Key", "batch-1")
+	rr := httptest.NewRecorder()
+
+	http.HandlerFunc(controller.SaveOrderBookHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), "idempotency_key_reused")
+
+	mockService.AssertNumberOfCalls(t, "SaveOrderBook", 1)
+}
+
+func TestSaveOrderHistoryHandler_NormalizesSideAndType(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	mockRepo := &mocks.MockOrderRepository{}
+	controller := NewController(mockRepo, mockService)
+
+	reqBody := []byte(`{"client_name": "client1", "side": "BID", "type": "Post_Only"}`)
+	req := httptest.NewRequest("POST", "/saveOrderHistory", bytes.NewBuffer(reqBody))
+	rr := httptest.NewRecorder()
+
+	mockService.On("SaveOrderHistory", mock.MatchedBy(func(o entity.HistoryOrder) bool {
+		return o.Side == entity.SideBuy && o.Type == entity.OrderTypePostOnly
+	})).Return(&entity.SaveResult{Saved: 1, Duplicates: []string{}}, nil)
+
+	withAPIKey(adminKey, controller.SaveOrderHistoryHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSaveOrderHistoryHandler_RejectsUnknownSideAndType(t *testing.T) {
+	controller := NewController(nil, nil)
+
+	for _, body := range []string{
+		`{"side": "long", "type": "limit"}`,
+		`{"side": "buy", "type": "iceberg"}`,
+		`{"side": "buy"}`,
+		`{"type": "market"}`,
+	} {
+		req := httptest.NewRequest("POST", "/saveOrderHistory", bytes.NewBufferString(body))
+		rr := httptest.NewRecorder()
+
+		withAPIKey(adminKey, controller.SaveOrderHistoryHandler).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, body)
+	}
+}
+
+func TestSaveOrderHistoryHandler_UnregisteredClient(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	mockRepo := &mocks.MockOrderRepository{}
+	controller := NewController(mockRepo, mockService)
+
+	mockService.On("SaveOrderHistory", mock.Anything).Return((*entity.SaveResult)(nil), entity.ErrUnregisteredClient)
+
+	req := httptest.NewRequest("POST", "/saveOrderHistory", bytes.NewBufferString(`{"client_name": "clinet1", "side": "buy", "type": "limit"}`))
+	rr := httptest.NewRecorder()
+
+	withAPIKey(adminKey, controller.SaveOrderHistoryHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func newV1Router(controller OrderController) http.Handler {
+	r := chi.NewRouter()
+	r.With(Deprecated("/v1/orderbooks/{exchange}/{pair}")).Get("/orderbook", controller.GetOrderBookHandler)
+	r.Get("/v1/orderbooks/{exchange}/{pair}", controller.GetOrderBookV1Handler)
+	r.Get("/v1/history/{clientName}", withAPIKey(adminKey, controller.GetOrderHistoryV1Handler))
+	return r
+}
+
+func TestGetOrderBookV1Handler(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	router := newV1Router(NewController(&mocks.MockOrderRepository{}, mockService))
+
+	mockOrderBook := []*entity.OrderBook{{ID: 1, Exchange: "Binance", Pair: "BTC/USDT"}}
+	mockService.On("GetOrderBook", "Binance", "BTC/USDT").Return(mockOrderBook, nil)
+	mockService.On("GetOrderBook", "Binance", "ETH/USDT").Return([]*entity.OrderBook(nil), entity.ErrNotFound)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/v1/orderbooks/Binance/BTC%2FUSDT", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expectedBody, _ := json.Marshal(mockOrderBook)
+	assert.Equal(t, expectedBody, rr.Body.Bytes())
+	assert.Empty(t, rr.Header().Get("Deprecation"))
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/v1/orderbooks/Binance/ETH%2FUSDT", nil))
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetOrderHistoryV1Handler(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	router := newV1Router(NewController(&mocks.MockOrderRepository{}, mockService))
+
+	filter := &entity.HistoryFilter{
+		ClientName:   "client1",
+		ExchangeName: "exchange1",
+		Pairs:        []string{"BTC/USDT", "ETH/USDT", "SOL/USDT"},
+		From:         time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+	orders := []*entity.HistoryOrder{{OrderID: "order1", ClientName: "client1"}}
+	mockService.On("FindOrderHistory", filter).Return(orders, nil)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/v1/history/client1?exchange_name=exchange1&pair=BTC/USDT,ETH/USDT&pair=SOL/USDT&from=2024-05-01T00:00:00Z", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expectedBody, _ := json.Marshal(orders)
+	assert.Equal(t, expectedBody, rr.Body.Bytes())
+
+	// Test case: malformed time and a range ending before it starts
+	for _, query := range []string{"from=yesterday", "from=2024-05-02T00:00:00Z&to=2024-05-01T00:00:00Z"} {
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/v1/history/client1?"+query, nil))
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	}
+	mockService.AssertNumberOfCalls(t, "FindOrderHistory", 1)
+}
+
+func TestDeprecated(t *testing.T) {
+	router := newV1Router(NewController(&mocks.MockOrderRepository{}, &mocks.MockOrderService{}))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/orderbook", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "true", rr.Header().Get("Deprecation"))
+	assert.Equal(t, `</v1/orderbooks/{exchange}/{pair}>; rel="successor-version"`, rr.Header().Get("Link"))
+}
@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+)
+
+type ConversionController interface {
+	GetConvertedCommissionsHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type conversionControllerImpl struct {
+	svc service.ConversionService
+}
+
+func NewConversionController(svc service.ConversionService) ConversionController {
+	return &conversionControllerImpl{svc: svc}
+}
+
+// @Summary Get Converted Commissions
+// @Description Total the commissions and notionals of filtered fills per exchange, valued in a single currency at the order book mid prices when each fill was placed.
+// @Description Fills charged in an asset that cannot be valued are counted as unpriced and left out of the totals.
+// @Description Filters left out are not applied; list filters may be repeated or comma-separated.
+// @Tags report
+// @Produce json
+// @Param client_name query string false "Client Name"
+// @Param exchange_name query string false "Exchange Name"
+// @Param pair query []string false "Trading Pairs" collectionFormat(multi)
+// @Param label query []string false "Labels" collectionFormat(multi)
+// @Param algorithm_name query []string false "Algorithm Names" collectionFormat(multi)
+// @Param from query string false "Earliest fill time, RFC 3339"
+// @Param to query string false "Latest fill time, RFC 3339"
+// @Param currency query string true "Currency to value commissions in, such as USDT"
+// @Success 200 {array} entity.ConvertedCommission
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /reports/commission/converted [get]
+func (c *conversionControllerImpl) GetConvertedCommissionsHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, err := historyFilterQuery(q)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	req := entity.CommissionConversionRequest{HistoryFilter: filter, Currency: q.Get("currency")}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	commissions, err := c.svc.GetConvertedCommissions(&req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(commissions)
+	w.Write(bytes)
+}
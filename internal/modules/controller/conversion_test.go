@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConvertedCommissionsHandler(t *testing.T) {
+	mockService := &mocks.MockConversionService{}
+	controller := NewConversionController(mockService)
+
+	req := &entity.CommissionConversionRequest{Currency: "USDT"}
+	commissions := []*entity.ConvertedCommission{{ExchangeName: "exchange1", Currency: "USDT", Fills: 1, Commission: 0.5, Notional: 1000, FeeRateBps: 5, UnpricedAssets: []string{}}}
+	mockService.On("GetConvertedCommissions", req).Return(commissions, nil)
+
+	r := httptest.NewRequest("GET", "/reports/commission/converted?currency=usdt", nil)
+	rr := httptest.NewRecorder()
+
+	http.HandlerFunc(controller.GetConvertedCommissionsHandler).ServeHTTP(rr, r)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expectedBody, _ := json.Marshal(commissions)
+	assert.Equal(t, expectedBody, rr.Body.Bytes())
+
+	// Test case: missing currency
+	r = httptest.NewRequest("GET", "/reports/commission/converted", nil)
+	rr = httptest.NewRecorder()
+
+	http.HandlerFunc(controller.GetConvertedCommissionsHandler).ServeHTTP(rr, r)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNumberOfCalls(t, "GetConvertedCommissions", 1)
+}
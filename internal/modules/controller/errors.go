@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/google/uuid"
+)
+
+type requestIDKey struct{}
+
+// errorKinds maps the kinds of domain errors to their status and default code, most specific first.
+var errorKinds = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{entity.ErrValidation, http.StatusBadRequest, "validation_failed"},
+	{entity.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{entity.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{entity.ErrNotFound, http.StatusNotFound, "not_found"},
+	{entity.ErrConflict, http.StatusConflict, "conflict"},
+	{entity.ErrUnprocessable, http.StatusUnprocessableEntity, "unprocessable"},
+	{entity.ErrUnavailable, http.StatusServiceUnavailable, "storage_unavailable"},
+	{entity.ErrTimeout, http.StatusGatewayTimeout, "timeout"},
+	{errUnsupportedMediaType, http.StatusUnsupportedMediaType, "unsupported_media_type"},
+	{errNotAcceptable, http.StatusNotAcceptable, "not_acceptable"},
+}
+
+/*
+RequestID gives every request an ID, taken from the X-Request-Id header when the caller sends one,
+and echoes it in the response so error bodies and logs can be matched with the request.
+*/
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-Id")
+		if id == "" {
+			id = uuid.NewString()
+		}
+		w.Header().Set("X-Request-Id", id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+func requestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDKey{}).(string)
+	return id
+}
+
+/*
+malformedRequest turns an error decoding a request body into a validation error,
+naming the field when a value has the wrong type.
+*/
+func malformedRequest(err error) error {
+	if errors.Is(err, entity.ErrValidation) {
+		return err
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return entity.Invalid(typeErr.Field, "%s must be a %s", typeErr.Field, typeErr.Type)
+	}
+
+	return &entity.Error{Kind: entity.ErrValidation, Code: "malformed_request", Message: "malformed request body", Err: err}
+}
+
+/*
+writeError writes err as an entity.ErrorResponse with the status of its kind.
+Errors of no known kind are internal errors; their detail is logged and not sent.
+*/
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, body := errorBody(r, err)
+	writeErrorBody(w, status, body)
+}
+
+// errorBody returns the status and the body of the response to a request that failed with err.
+func errorBody(r *http.Request, err error) (int, entity.ErrorBody) {
+	body := entity.ErrorBody{Code: "internal", Message: "internal server error", RequestID: requestID(r)}
+	status := http.StatusInternalServerError
+
+	for _, k := range errorKinds {
+		if errors.Is(err, k.kind) {
+			status, body.Code, body.Message = k.status, k.code, k.kind.Error()
+			break
+		}
+	}
+
+	var domain *entity.Error
+	if errors.As(err, &domain) && status != http.StatusInternalServerError {
+		body.Code, body.Message = domain.Code, domain.Message
+	}
+	if status < http.StatusInternalServerError {
+		body.Message = err.Error()
+	}
+
+	var invalid *entity.ValidationError
+	if errors.As(err, &invalid) {
+		body.Details = invalid.Fields
+	}
+
+	if status >= http.StatusInternalServerError {
+		log.Printf("request %s: %s %s: %v", body.RequestID, r.Method, r.URL.Path, err)
+	}
+
+	return status, body
+}
+
+func writeErrorBody(w http.ResponseWriter, status int, body entity.ErrorBody) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	bytes, _ := json.Marshal(entity.ErrorResponse{Error: body})
+	w.Write(bytes)
+}
+
+// NotFoundHandler answers requests for routes that do not exist.
+func NotFoundHandler(w http.ResponseWriter, r *http.Request) {
+	writeErrorBody(w, http.StatusNotFound, entity.ErrorBody{Code: "route_not_found", Message: "no route for " + r.URL.Path, RequestID: requestID(r)})
+}
+
+// MethodNotAllowedHandler answers requests using a method the route does not serve.
+func MethodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
+	writeErrorBody(w, http.StatusMethodNotAllowed, entity.ErrorBody{Code: "method_not_allowed", Message: r.Method + " is not allowed on " + r.URL.Path, RequestID: requestID(r)})
+}
+
+// RateLimitedHandler answers requests over the rate limit.
+func RateLimitedHandler(w http.ResponseWriter, r *http.Request) {
+	writeErrorBody(w, http.StatusTooManyRequests, entity.ErrorBody{Code: "rate_limited", Message: "too many requests", RequestID: requestID(r)})
+}
+
+// badQuery returns a validation error for a query parameter that could not be parsed.
+func badQuery(name string, err error) error {
+	return entity.Invalid(name, "invalid %s: %v", name, err)
+}
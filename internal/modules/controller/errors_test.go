@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeError(t *testing.T, rr *httptest.ResponseRecorder) entity.ErrorBody {
+	var resp entity.ErrorResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	return resp.Error
+}
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		err     error
+		status  int
+		code    string
+		details []entity.FieldError
+	}{
+		{entity.Required("client_name", "", "exchange_name", "x"), http.StatusBadRequest, "validation_failed", []entity.FieldError{{Field: "client_name", Message: "client_name is required"}}},
+		{entity.ErrNotFound, http.StatusNotFound, "not_found", nil},
+		{entity.ErrClientExists, http.StatusConflict, "client_exists", nil},
+		{entity.ErrNoMarkPrice, http.StatusUnprocessableEntity, "no_mark_price", nil},
+		{&entity.Error{Kind: entity.ErrUnavailable, Code: "storage_unavailable", Message: "storage unavailable", Err: errors.New("dial tcp: refused")}, http.StatusServiceUnavailable, "storage_unavailable", nil},
+		{errors.New("boom"), http.StatusInternalServerError, "internal", nil},
+	}
+
+	for _, tt := range tests {
+		handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { writeError(w, r, tt.err) }))
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Request-Id", "req-1")
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, tt.status, rr.Code)
+		assert.Equal(t, "req-1", rr.Header().Get("X-Request-Id"))
+		body := decodeError(t, rr)
+		assert.Equal(t, tt.code, body.Code)
+		assert.Equal(t, tt.details, body.Details)
+		assert.Equal(t, "req-1", body.RequestID)
+		assert.NotContains(t, body.Message, "refused")
+		assert.NotContains(t, body.Message, "boom")
+	}
+}
+
+func TestMalformedRequest(t *testing.T) {
+	mockService := &mocks.MockClientService{}
+	controller := NewClientController(mockService)
+
+	req := httptest.NewRequest("POST", "/clients", bytes.NewBufferString(`{"client_name": 1}`))
+	rr := httptest.NewRecorder()
+
+	RequestID(http.HandlerFunc(controller.CreateClientHandler)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	body := decodeError(t, rr)
+	assert.Equal(t, "validation_failed", body.Code)
+	assert.Equal(t, "client_name", body.Details[0].Field)
+	assert.NotEmpty(t, body.RequestID)
+
+	req = httptest.NewRequest("POST", "/clients", bytes.NewBufferString(`{`))
+	rr = httptest.NewRecorder()
+
+	http.HandlerFunc(controller.CreateClientHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "malformed_request", decodeError(t, rr).Code)
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+)
+
+type ExportController interface {
+	ExportOrderBookHandler(w http.ResponseWriter, r *http.Request)
+	ExportOrderHistoryHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type exportControllerImpl struct {
+	svc service.ExportService
+}
+
+func NewExportController(svc service.ExportService) ExportController {
+	return &exportControllerImpl{svc: svc}
+}
+
+var exportContentTypes = map[string]string{
+	entity.ExportFormatCSV:     "text/csv",
+	entity.ExportFormatParquet: "application/vnd.apache.parquet",
+}
+
+// @Summary Export Order Book
+// @Description Stream the order book snapshots of an exchange and trading pair as a CSV or Parquet download. A pair containing a slash is URL-encoded, as in BTC%2FUSDT.
+// @Description The levels layout flattens the first depth levels of each side into columns, the rows layout writes one row per price level.
+// @Tags export
+// @Produce text/csv,application/vnd.apache.parquet
+// @Param exchange path string true "Exchange Name"
+// @Param pair path string true "Trading Pair"
+// @Param format query string false "csv (default) or parquet"
+// @Param layout query string false "levels (default) or rows"
+// @Param depth query int false "Levels per side in the levels layout, 10 by default"
+// @Success 200 {file} file
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /v1/orderbooks/{exchange}/{pair}/export [get]
+func (c *exportControllerImpl) ExportOrderBookHandler(w http.ResponseWriter, r *http.Request) {
+	opts, err := exportOptions(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req entity.OrderBookRequest
+	if req.Exchange_name, err = pathParam(r, "exchange"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if req.Pair, err = pathParam(r, "pair"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	out := &exportResponseWriter{w: w, r: r, format: opts.Format, filename: "orderbook"}
+	err = c.svc.ExportOrderBook(&req, opts, out)
+	out.finish(err)
+}
+
+// @Summary Export Order History
+// @Description Stream the fills of a client as a CSV or Parquet download, oldest first. Filters left out are not applied; list filters may be repeated or comma-separated.
+// @Tags export
+// @Produce text/csv,application/vnd.apache.parquet
+// @Param clientName path string true "Client Name"
+// @Param format query string false "csv (default) or parquet"
+// @Param exchange_name query string false "Exchange Name"
+// @Param pair query []string false "Trading Pairs" collectionFormat(multi)
+// @Param label query []string false "Labels" collectionFormat(multi)
+// @Param algorithm_name query []string false "Algorithm Names" collectionFormat(multi)
+// @Param from query string false "Earliest fill time, RFC 3339"
+// @Param to query string false "Latest fill time, RFC 3339"
+// @Success 200 {file} file
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /v1/history/{clientName}/export [get]
+func (c *exportControllerImpl) ExportOrderHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	opts, err := exportOptions(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter, err := historyFilterQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if filter.ClientName, err = pathParam(r, "clientName"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := authorize(r, entity.ScopeReadHistory, filter.ClientName); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	out := &exportResponseWriter{w: w, r: r, format: opts.Format, filename: "history"}
+	err = c.svc.ExportOrderHistory(&filter, opts, out)
+	out.finish(err)
+}
+
+func exportOptions(r *http.Request) (*entity.ExportOptions, error) {
+	query := r.URL.Query()
+	opts := &entity.ExportOptions{
+		Format: query.Get("format"),
+		Layout: query.Get("layout"),
+	}
+
+	if depth := query.Get("depth"); depth != "" {
+		n, err := strconv.Atoi(depth)
+		if err != nil {
+			return nil, badQuery("depth", err)
+		}
+		opts.Depth = n
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+/*
+exportResponseWriter sends the download headers with the first write,
+so a request that fails before any data is produced can still answer with an error status.
+*/
+type exportResponseWriter struct {
+	w        http.ResponseWriter
+	r        *http.Request
+	format   string
+	filename string
+	started  bool
+}
+
+func (e *exportResponseWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.w.Header().Set("Content-Type", exportContentTypes[e.format])
+		e.w.Header().Set("Content-Disposition", `attachment; filename="`+e.filename+"."+e.format+`"`)
+		e.w.WriteHeader(http.StatusOK)
+	}
+	return e.w.Write(p)
+}
+
+// finish writes the error response for err. Once the download has started the response is only cut short.
+func (e *exportResponseWriter) finish(err error) {
+	if err == nil || e.started {
+		return
+	}
+	writeError(e.w, e.r, err)
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newExportRouter(controller ExportController) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/v1/orderbooks/{exchange}/{pair}/export", controller.ExportOrderBookHandler)
+	r.Get("/v1/history/{clientName}/export", withAPIKey(adminKey, controller.ExportOrderHistoryHandler))
+	return r
+}
+
+func TestExportOrderHistoryHandler(t *testing.T) {
+	mockService := &mocks.MockExportService{}
+	router := newExportRouter(NewExportController(mockService))
+
+	filter := &entity.HistoryFilter{ClientName: "client1", ExchangeName: "exchange1", Labels: []string{"label1"}, Pairs: []string{"pair1"}}
+	opts := &entity.ExportOptions{Format: entity.ExportFormatCSV, Layout: entity.BookLayoutLevels, Depth: entity.DefaultExportDepth}
+	mockService.On("ExportOrderHistory", filter, opts, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(2).(io.Writer).Write([]byte("order_id\no1\n"))
+	}).Return(nil)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/v1/history/client1/export?exchange_name=exchange1&label=label1&pair=pair1", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="history.csv"`, rr.Header().Get("Content-Disposition"))
+	assert.Equal(t, "order_id\no1\n", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/v1/history/client1/export?from=yesterday", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestExportOrderBookHandler(t *testing.T) {
+	mockService := &mocks.MockExportService{}
+	router := newExportRouter(NewExportController(mockService))
+
+	req := &entity.OrderBookRequest{Exchange_name: "exchange1", Pair: "pair1"}
+	opts := &entity.ExportOptions{Format: entity.ExportFormatParquet, Layout: entity.BookLayoutRows, Depth: 5}
+	mockService.On("ExportOrderBook", req, opts, mock.Anything).Return(entity.ErrNotFound)
+
+	tests := []struct {
+		name string
+		url  string
+		code int
+	}{
+		{"not found", "/v1/orderbooks/exchange1/pair1/export?format=parquet&layout=rows&depth=5", http.StatusNotFound},
+		{"unknown format", "/v1/orderbooks/exchange1/pair1/export?format=xlsx", http.StatusBadRequest},
+		{"bad depth", "/v1/orderbooks/exchange1/pair1/export?depth=ten", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, httptest.NewRequest("GET", tt.url, nil))
+
+			assert.Equal(t, tt.code, rr.Code)
+			assert.Empty(t, rr.Header().Get("Content-Disposition"))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+)
+
+type FeeController interface {
+	SetFeeScheduleHandler(w http.ResponseWriter, r *http.Request)
+	GetFeeSchedulesHandler(w http.ResponseWriter, r *http.Request)
+	ReconcileCommissionsHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type feeControllerImpl struct {
+	svc service.FeeService
+}
+
+func NewFeeController(svc service.FeeService) FeeController {
+	return &feeControllerImpl{svc: svc}
+}
+
+// @Summary Set Fee Schedule
+// @Description Set the fee tiers of an exchange from a point in time, or a client override of them. Negative rates are rebates.
+// @Tags fees
+// @Accept json
+// @Param feeSchedule body entity.FeeSchedule true "Fee Schedule"
+// @Success 200 {string} string "OK"
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /fees/schedules [put]
+func (c *feeControllerImpl) SetFeeScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	var req entity.FeeSchedule
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, malformedRequest(err))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := c.svc.SetFeeSchedule(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary List Fee Schedules
+// @Description List fee schedules, optionally of a single exchange.
+// @Tags fees
+// @Produce json
+// @Param exchange_name query string false "Exchange Name"
+// @Success 200 {array} entity.FeeSchedule
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /fees/schedules [get]
+func (c *feeControllerImpl) GetFeeSchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	schedules, err := c.svc.GetFeeSchedules(r.URL.Query().Get("exchange_name"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(schedules)
+	w.Write(bytes)
+}
+
+// @Summary Reconcile Commissions
+// @Description Recompute the commission of filtered fills from the fee schedules and list the fills charged differently.
+// @Description Filters left out are not applied; list filters may be repeated or comma-separated.
+// @Tags report
+// @Produce json
+// @Param client_name query string false "Client Name"
+// @Param exchange_name query string false "Exchange Name"
+// @Param pair query []string false "Trading Pairs" collectionFormat(multi)
+// @Param label query []string false "Labels" collectionFormat(multi)
+// @Param algorithm_name query []string false "Algorithm Names" collectionFormat(multi)
+// @Param from query string false "Earliest fill time, RFC 3339"
+// @Param to query string false "Latest fill time, RFC 3339"
+// @Param tolerance_bps query number false "Difference from the expected commission still counted as a match"
+// @Success 200 {object} entity.FeeReconciliation
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /reports/fees [get]
+func (c *feeControllerImpl) ReconcileCommissionsHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, err := historyFilterQuery(q)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	req := entity.FeeReconciliationRequest{HistoryFilter: filter}
+	if req.ToleranceBps, err = queryFloat(q, "tolerance_bps"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	reconciliation, err := c.svc.ReconcileCommissions(&req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(reconciliation)
+	w.Write(bytes)
+}
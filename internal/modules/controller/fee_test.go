@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileCommissionsHandler(t *testing.T) {
+	mockService := &mocks.MockFeeService{}
+	controller := NewFeeController(mockService)
+
+	req := &entity.FeeReconciliationRequest{HistoryFilter: entity.HistoryFilter{ExchangeName: "exchange1"}, ToleranceBps: entity.DefaultFeeToleranceBps}
+	reconciliation := &entity.FeeReconciliation{
+		Fills:         2,
+		Overcharged:   0.1,
+		Discrepancies: []*entity.FeeDiscrepancy{{OrderID: "order1", Difference: 0.1}},
+	}
+	mockService.On("ReconcileCommissions", req).Return(reconciliation, nil).Once()
+	mockService.On("ReconcileCommissions", req).Return((*entity.FeeReconciliation)(nil), entity.ErrNotFound)
+
+	for _, code := range []int{http.StatusOK, http.StatusNotFound} {
+		r := httptest.NewRequest("GET", "/reports/fees?exchange_name=exchange1", nil)
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.ReconcileCommissionsHandler).ServeHTTP(rr, r)
+
+		assert.Equal(t, code, rr.Code)
+		if code == http.StatusOK {
+			expectedBody, _ := json.Marshal(reconciliation)
+			assert.Equal(t, expectedBody, rr.Body.Bytes())
+		}
+	}
+}
+
+func TestReconcileCommissionsHandler_BadRequest(t *testing.T) {
+	controller := NewFeeController(nil)
+
+	for _, query := range []string{"from=yesterday", "tolerance_bps=-1", "tolerance_bps=some"} {
+		r := httptest.NewRequest("GET", "/reports/fees?"+query, nil)
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.ReconcileCommissionsHandler).ServeHTTP(rr, r)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func TestSetFeeScheduleHandler(t *testing.T) {
+	mockService := &mocks.MockFeeService{}
+	controller := NewFeeController(mockService)
+
+	schedule := entity.FeeSchedule{
+		ExchangeName:     "exchange1",
+		EffectiveFrom:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		VolumeWindowDays: entity.DefaultFeeVolumeWindowDays,
+		Tiers:            []entity.FeeTier{{MinVolume: 0, MakerBps: 2, TakerBps: 5}},
+	}
+	mockService.On("SetFeeSchedule", schedule).Return(nil)
+
+	r := httptest.NewRequest("PUT", "/fees/schedules", bytes.NewBufferString(`{"exchange_name": "exchange1", "effective_from": "2024-01-01T00:00:00Z", "tiers": [{"min_volume": 0, "maker_bps": 2, "taker_bps": 5}]}`))
+	rr := httptest.NewRecorder()
+
+	http.HandlerFunc(controller.SetFeeScheduleHandler).ServeHTTP(rr, r)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	r = httptest.NewRequest("PUT", "/fees/schedules", bytes.NewBufferString(`{"exchange_name": "exchange1", "tiers": [{"min_volume": 100, "taker_bps": 5}]}`))
+	rr = httptest.NewRecorder()
+
+	http.HandlerFunc(controller.SetFeeScheduleHandler).ServeHTTP(rr, r)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNumberOfCalls(t, "SetFeeSchedule", 1)
+}
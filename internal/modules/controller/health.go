@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/egorque1/vortex-test/internal/modules/service"
+)
+
+type HealthController interface {
+	HealthzHandler(w http.ResponseWriter, r *http.Request)
+	ReadyzHandler(w http.ResponseWriter, r *http.Request)
+	StatusHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type healthControllerImpl struct {
+	svc service.HealthService
+}
+
+func NewHealthController(svc service.HealthService) HealthController {
+	return &healthControllerImpl{svc: svc}
+}
+
+// @Summary Liveness Probe
+// @Description Answer 200 while the process is running, whatever the state of its dependencies.
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func (c *healthControllerImpl) HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(map[string]string{"status": "ok"})
+	w.Write(bytes)
+}
+
+// @Summary Readiness Probe
+// @Description Check that ClickHouse can be reached, that the migrations created every table and column and that the ingestion queue is not saturated.
+// @Description Answer 200 if every check passed and 503 otherwise, with the outcome of each check.
+// @Tags health
+// @Produce json
+// @Success 200 {object} entity.Readiness
+// @Failure 503 {object} entity.Readiness "Not Ready"
+// @Router /readyz [get]
+func (c *healthControllerImpl) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	readiness := c.svc.Ready(r.Context())
+
+	status := http.StatusOK
+	if !readiness.Ready {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	bytes, _ := json.Marshal(readiness)
+	w.Write(bytes)
+}
+
+// @Summary Service Status
+// @Description Get the version and uptime of the service, its readiness checks, the latency of ClickHouse and the error reaching it, the row counts of its tables and the ingestion queue.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} entity.ServiceStatus
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Security ApiKeyAuth
+// @Router /status [get]
+func (c *healthControllerImpl) StatusHandler(w http.ResponseWriter, r *http.Request) {
+	status := c.svc.Status(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(status)
+	w.Write(bytes)
+}
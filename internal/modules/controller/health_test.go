@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHealthzHandler(t *testing.T) {
+	controller := NewHealthController(&mocks.MockHealthService{})
+
+	rr := httptest.NewRecorder()
+	controller.HealthzHandler(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+}
+
+func TestReadyzHandler(t *testing.T) {
+	mockService := &mocks.MockHealthService{}
+	controller := NewHealthController(mockService)
+
+	mockService.On("Ready", mock.Anything).Return(&entity.Readiness{Ready: true, Checks: []entity.Check{{Name: "clickhouse", OK: true}}}).Once()
+	mockService.On("Ready", mock.Anything).Return(&entity.Readiness{Checks: []entity.Check{{Name: "clickhouse", Message: "database unreachable"}}})
+
+	// Test case: ready
+	rr := httptest.NewRecorder()
+	controller.ReadyzHandler(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Test case: not ready
+	rr = httptest.NewRecorder()
+	controller.ReadyzHandler(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	var readiness entity.Readiness
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &readiness))
+	assert.Equal(t, "database unreachable", readiness.Checks[0].Message)
+}
+
+func TestStatusHandler(t *testing.T) {
+	mockService := &mocks.MockHealthService{}
+	controller := NewHealthController(mockService)
+
+	mockService.On("Status", mock.Anything).Return(&entity.ServiceStatus{
+		Version:  "v1.2.3",
+		Database: entity.DatabaseStatus{Reachable: true, LatencyMs: 1.5, Tables: []entity.TableStatus{{Name: "order_book_dtos", Rows: 5}}},
+	})
+
+	rr := httptest.NewRecorder()
+	controller.StatusHandler(rr, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var status entity.ServiceStatus
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &status))
+	assert.Equal(t, "v1.2.3", status.Version)
+	assert.Equal(t, uint64(5), status.Database.Tables[0].Rows)
+}
@@ -0,0 +1,260 @@
+package controller
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// ingestWindow is the number of messages a collector may send before waiting for their acks.
+	ingestWindow = 64
+	// ingestBatchSize is the number of books that are saved with one insert as soon as they are received.
+	ingestBatchSize = 500
+	// ingestFlushInterval is how long received books wait for a batch to fill up before they are saved.
+	ingestFlushInterval = 100 * time.Millisecond
+	// ingestMaxMessageSize is the size of the largest message a collector may send.
+	ingestMaxMessageSize = 4 << 20
+)
+
+/*
+ingestSubprotocols are the WebSocket subprotocols a collector may request to pick the encoding of the messages,
+and their media types. Without one the messages are JSON.
+*/
+var ingestSubprotocols = map[string]string{
+	"json":     mediaJSON,
+	"protobuf": mediaProtobuf,
+	"msgpack":  mediaMsgpack,
+}
+
+type IngestController interface {
+	IngestOrderBooksHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type ingestControllerImpl struct {
+	svc      service.OrderService
+	queue    *service.IngestQueue
+	upgrader websocket.Upgrader
+	// flushInterval and pingPeriod are fields so tests can change them.
+	flushInterval time.Duration
+	pingPeriod    time.Duration
+	pongWait      time.Duration
+}
+
+// NewIngestController returns a controller saving the books collectors send with svc, counting those not saved yet in queue.
+func NewIngestController(svc service.OrderService, queue *service.IngestQueue) IngestController {
+	return &ingestControllerImpl{
+		svc:           svc,
+		queue:         queue,
+		upgrader:      websocket.Upgrader{Subprotocols: []string{"json", "protobuf", "msgpack"}},
+		flushInterval: ingestFlushInterval,
+		pingPeriod:    streamPingPeriod,
+		pongWait:      streamPongWait,
+	}
+}
+
+// ingestConn is a collector connection and the codec of its messages.
+type ingestConn struct {
+	*websocket.Conn
+	codec codec
+	// messageType is websocket.TextMessage for JSON and websocket.BinaryMessage for the binary encodings.
+	messageType int
+}
+
+// ingestInput is a message read from a collector, or the error reading it.
+type ingestInput struct {
+	msg entity.IngestMessage
+	err error
+}
+
+// ingestBatch holds the books received since the last save, and the messages they came in.
+type ingestBatch struct {
+	books    []*entity.OrderBook
+	messages []entity.IngestReply
+	// latest is the latest book of each pair in the batch, the base of the deltas that follow.
+	latest map[string]*entity.OrderBook
+}
+
+// @Summary Ingest Order Books
+// @Description Open a WebSocket on which a collector pushes order book snapshots and deltas as entity.IngestMessage messages.
+// @Description The Sec-WebSocket-Protocol header picks their encoding and that of the replies: json, the default, protobuf, with the IngestMessage and IngestReply messages of api/order/v1/order.proto, or msgpack.
+// @Description The collector authenticates once with an Authorization: Bearer header holding an API key with the books:write scope.
+// @Description The server first sends a welcome entity.IngestReply with the window, the number of messages that may be sent before waiting for acks,
+// @Description then an ack or nack for every message once its books are saved, in batches, or rejected. Deltas apply to the latest book of the pair sent on the same connection.
+// @Tags order
+// @Produce json
+// @Success 101 {object} entity.IngestReply "Switching Protocols"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Security ApiKeyAuth
+// @Router /v1/ingest/orderbooks [get]
+func (c *ingestControllerImpl) IngestOrderBooksHandler(w http.ResponseWriter, r *http.Request) {
+	ws, err := c.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	conn := &ingestConn{Conn: ws, codec: jsonCodec{}, messageType: websocket.TextMessage}
+	if mediaType := ingestSubprotocols[ws.Subprotocol()]; mediaType != "" && mediaType != mediaJSON {
+		conn.codec, conn.messageType = codecs[mediaType], websocket.BinaryMessage
+	}
+
+	if err := c.reply(conn, entity.IngestReply{Type: entity.IngestWelcome, Window: ingestWindow}); err != nil {
+		return
+	}
+
+	// The reader blocks once a window of messages is waiting, which pushes back on the collector.
+	in := make(chan ingestInput, ingestWindow)
+	done := make(chan struct{})
+	defer close(done)
+	go c.read(conn, in, done)
+
+	flush := time.NewTicker(c.flushInterval)
+	defer flush.Stop()
+	ping := time.NewTicker(c.pingPeriod)
+	defer ping.Stop()
+
+	base := make(map[string]*entity.OrderBook)
+	batch := &ingestBatch{latest: make(map[string]*entity.OrderBook)}
+	defer func() { c.queue.Done(len(batch.books)) }()
+	for {
+		select {
+		case input, ok := <-in:
+			if !ok {
+				c.flush(conn, r, base, batch)
+				return
+			}
+			if err := c.receive(conn, r, base, batch, input); err != nil {
+				return
+			}
+			if len(batch.books) >= ingestBatchSize {
+				if err := c.flush(conn, r, base, batch); err != nil {
+					return
+				}
+			}
+		case <-flush.C:
+			if err := c.flush(conn, r, base, batch); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// read decodes messages from the collector into in until the connection fails or closes, or done is closed.
+func (c *ingestControllerImpl) read(conn *ingestConn, in chan<- ingestInput, done <-chan struct{}) {
+	defer close(in)
+
+	conn.SetReadLimit(ingestMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(c.pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(c.pongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(c.pongWait))
+
+		var input ingestInput
+		if err := conn.codec.decode(bytes.NewReader(data), &input.msg); err != nil {
+			input.err = malformedRequest(err)
+		} else {
+			input.err = input.msg.Validate()
+		}
+		select {
+		case in <- input:
+		case <-done:
+			return
+		}
+	}
+}
+
+// receive adds the books of a message to the batch, or nacks it.
+func (c *ingestControllerImpl) receive(conn *ingestConn, r *http.Request, base map[string]*entity.OrderBook, batch *ingestBatch, input ingestInput) error {
+	msg := input.msg
+	if input.err != nil {
+		return c.reply(conn, c.nack(r, msg.ID, input.err))
+	}
+
+	books := msg.Books
+	if msg.Type == entity.IngestDelta {
+		topic := entity.BookKey{Exchange: msg.Delta.Exchange, Pair: msg.Delta.Pair}.Topic()
+		latest, ok := batch.latest[topic]
+		if !ok {
+			latest, ok = base[topic]
+		}
+		if !ok {
+			return c.reply(conn, c.nack(r, msg.ID, &entity.Error{Kind: entity.ErrConflict, Code: "no_snapshot", Message: "a delta must follow a snapshot of its book"}))
+		}
+
+		book, err := latest.Apply(msg.Delta)
+		if err != nil {
+			return c.reply(conn, c.nack(r, msg.ID, err))
+		}
+		books = []*entity.OrderBook{book}
+	}
+
+	for _, book := range books {
+		batch.latest[entity.BookKey{Exchange: book.Exchange, Pair: book.Pair}.Topic()] = book
+	}
+	batch.books = append(batch.books, books...)
+	c.queue.Add(len(books))
+	batch.messages = append(batch.messages, entity.IngestReply{Type: entity.IngestAck, ID: msg.ID, Books: len(books)})
+	return nil
+}
+
+/*
+flush saves the batch with one insert and acks its messages, or nacks them all if the save fails.
+Books of a failed batch are not the base of later deltas, so the collector can send the messages again.
+*/
+func (c *ingestControllerImpl) flush(conn *ingestConn, r *http.Request, base map[string]*entity.OrderBook, batch *ingestBatch) error {
+	if len(batch.messages) == 0 {
+		return nil
+	}
+
+	replies := batch.messages
+	if _, err := c.svc.SaveOrderBookBatch(batch.books); err != nil {
+		for i, reply := range replies {
+			replies[i] = c.nack(r, reply.ID, err)
+		}
+	} else {
+		for topic, book := range batch.latest {
+			base[topic] = book
+		}
+	}
+	c.queue.Done(len(batch.books))
+	*batch = ingestBatch{latest: make(map[string]*entity.OrderBook)}
+
+	for _, reply := range replies {
+		if err := c.reply(conn, reply); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nack returns the reply rejecting a message, with the error body an HTTP request would get.
+func (c *ingestControllerImpl) nack(r *http.Request, id uint64, err error) entity.IngestReply {
+	_, body := errorBody(r, err)
+	return entity.IngestReply{Type: entity.IngestNack, ID: id, Error: &body}
+}
+
+func (c *ingestControllerImpl) reply(conn *ingestConn, reply entity.IngestReply) error {
+	data, err := conn.codec.encode(&reply)
+	if err != nil {
+		return err
+	}
+	conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+	return conn.WriteMessage(conn.messageType, data)
+}
@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	orderv1 "github.com/egorque1/vortex-test/api/order/v1"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+	"github.com/egorque1/vortex-test/internal/protoconv"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/protobuf/proto"
+)
+
+func dialIngest(t *testing.T, svc *mocks.MockOrderService, queue *service.IngestQueue) *websocket.Conn {
+	controller := NewIngestController(svc, queue).(*ingestControllerImpl)
+	controller.flushInterval = 10 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(controller.IngestOrderBooksHandler))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("error dialing ingest: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	var welcome entity.IngestReply
+	assert.NoError(t, conn.ReadJSON(&welcome))
+	assert.Equal(t, entity.IngestReply{Type: entity.IngestWelcome, Window: ingestWindow}, welcome)
+	return conn
+}
+
+func TestIngestOrderBooksHandler(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	var saved []*entity.OrderBook
+	mockService.On("SaveOrderBookBatch", mock.Anything).Run(func(args mock.Arguments) {
+		saved = append(saved, args.Get(0).([]*entity.OrderBook)...)
+	}).Return(&entity.SaveResult{}, nil)
+
+	queue := service.NewIngestQueue(10)
+	conn := dialIngest(t, mockService, queue)
+
+	snapshot := &entity.OrderBook{
+		Exchange: "exchange1",
+		Pair:     "BTC/USDT",
+		Asks:     []entity.DepthOrder{{Price: 101, BaseQty: 1}, {Price: 102, BaseQty: 2}},
+		Bids:     []entity.DepthOrder{{Price: 99, BaseQty: 1}},
+		Sequence: 1,
+	}
+	delta := &entity.OrderBookDelta{
+		Exchange: "exchange1",
+		Pair:     "BTC/USDT",
+		Asks:     []entity.DepthOrder{{Price: 101, BaseQty: 0}},
+		Bids:     []entity.DepthOrder{{Price: 100, BaseQty: 3}},
+		Sequence: 2,
+	}
+	assert.NoError(t, conn.WriteJSON(entity.IngestMessage{ID: 1, Type: entity.IngestSnapshot, Books: []*entity.OrderBook{snapshot}}))
+	assert.NoError(t, conn.WriteJSON(entity.IngestMessage{ID: 2, Type: entity.IngestDelta, Delta: delta}))
+	assert.NoError(t, conn.WriteJSON(entity.IngestMessage{ID: 3, Type: entity.IngestDelta, Delta: &entity.OrderBookDelta{Exchange: "exchange1", Pair: "ETH/USDT"}}))
+	assert.NoError(t, conn.WriteJSON(entity.IngestMessage{ID: 4, Type: "trade"}))
+
+	replies := map[uint64]entity.IngestReply{}
+	for i := 0; i < 4; i++ {
+		var reply entity.IngestReply
+		assert.NoError(t, conn.ReadJSON(&reply))
+		replies[reply.ID] = reply
+	}
+
+	assert.Equal(t, entity.IngestReply{Type: entity.IngestAck, ID: 1, Books: 1}, replies[1])
+	assert.Equal(t, entity.IngestReply{Type: entity.IngestAck, ID: 2, Books: 1}, replies[2])
+	assert.Equal(t, "no_snapshot", replies[3].Error.Code)
+	assert.Equal(t, "type", replies[4].Error.Details[0].Field)
+
+	assert.Len(t, saved, 2)
+	assert.Equal(t, []entity.DepthOrder{{Price: 102, BaseQty: 2}}, saved[1].Asks)
+	assert.Equal(t, []entity.DepthOrder{{Price: 100, BaseQty: 3}, {Price: 99, BaseQty: 1}}, saved[1].Bids)
+	assert.Equal(t, int64(2), saved[1].Sequence)
+	assert.Equal(t, 0, queue.Status().Pending)
+}
+
+func TestIngestOrderBooksHandler_SaveFailure(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	unavailable := &entity.Error{Kind: entity.ErrUnavailable, Code: "storage_unavailable", Message: "storage unavailable", Err: errors.New("connection refused")}
+	mockService.On("SaveOrderBookBatch", mock.Anything).Return((*entity.SaveResult)(nil), unavailable).Once()
+	mockService.On("SaveOrderBookBatch", mock.Anything).Return(&entity.SaveResult{}, nil)
+
+	conn := dialIngest(t, mockService, service.NewIngestQueue(10))
+
+	snapshot := &entity.OrderBook{Exchange: "exchange1", Pair: "BTC/USDT", Sequence: 1}
+	assert.NoError(t, conn.WriteJSON(entity.IngestMessage{ID: 1, Type: entity.IngestSnapshot, Books: []*entity.OrderBook{snapshot}}))
+
+	var reply entity.IngestReply
+	assert.NoError(t, conn.ReadJSON(&reply))
+	assert.Equal(t, entity.IngestNack, reply.Type)
+	assert.Equal(t, "storage_unavailable", reply.Error.Code)
+
+	// The failed snapshot is not the base of deltas until it is sent again.
+	assert.NoError(t, conn.WriteJSON(entity.IngestMessage{ID: 2, Type: entity.IngestDelta, Delta: &entity.OrderBookDelta{Exchange: "exchange1", Pair: "BTC/USDT", Sequence: 2}}))
+	var nack entity.IngestReply
+	assert.NoError(t, conn.ReadJSON(&nack))
+	assert.Equal(t, "no_snapshot", nack.Error.Code)
+
+	assert.NoError(t, conn.WriteJSON(entity.IngestMessage{ID: 3, Type: entity.IngestSnapshot, Books: []*entity.OrderBook{snapshot}}))
+	var ack entity.IngestReply
+	assert.NoError(t, conn.ReadJSON(&ack))
+	assert.Equal(t, entity.IngestReply{Type: entity.IngestAck, ID: 3, Books: 1}, ack)
+}
+
+func TestIngestOrderBooksHandler_Subprotocols(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	mockService.On("SaveOrderBookBatch", mock.Anything).Return(&entity.SaveResult{}, nil)
+
+	controller := NewIngestController(mockService, service.NewIngestQueue(10)).(*ingestControllerImpl)
+	controller.flushInterval = 10 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(controller.IngestOrderBooksHandler))
+	defer server.Close()
+
+	snapshot := &entity.OrderBook{Exchange: "exchange1", Pair: "BTC/USDT", Asks: []entity.DepthOrder{{Price: 101, BaseQty: 1}}, Sequence: 1}
+	for _, subprotocol := range []string{"protobuf", "msgpack"} {
+		dialer := websocket.Dialer{Subprotocols: []string{subprotocol}}
+		conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		if err != nil {
+			t.Fatalf("error dialing ingest: %v", err)
+		}
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		assert.Equal(t, subprotocol, conn.Subprotocol())
+
+		c := codecs[ingestSubprotocols[subprotocol]]
+		read := func() entity.IngestReply {
+			messageType, data, err := conn.ReadMessage()
+			assert.NoError(t, err)
+			assert.Equal(t, websocket.BinaryMessage, messageType, subprotocol)
+			var reply entity.IngestReply
+			if subprotocol == "protobuf" {
+				var msg orderv1.IngestReply
+				assert.NoError(t, proto.Unmarshal(data, &msg))
+				reply = entity.IngestReply{ID: msg.GetId(), Books: int(msg.GetBooks()), Window: int(msg.GetWindow())}
+				if msg.GetError() != nil {
+					reply.Error = &entity.ErrorBody{Code: msg.GetError().GetCode()}
+				}
+			} else {
+				assert.NoError(t, c.decode(bytes.NewReader(data), &reply))
+			}
+			return reply
+		}
+		write := func(msg entity.IngestMessage) {
+			var data []byte
+			if subprotocol == "protobuf" {
+				m := &orderv1.IngestMessage{Id: msg.ID, Type: orderv1.IngestMessage_TYPE_SNAPSHOT, Books: protoconv.ToOrderBooks(msg.Books)}
+				if msg.Delta != nil {
+					m = &orderv1.IngestMessage{Id: msg.ID, Type: orderv1.IngestMessage_TYPE_DELTA, Delta: &orderv1.OrderBookDelta{Exchange: msg.Delta.Exchange, Pair: msg.Delta.Pair}}
+				}
+				data, err = proto.Marshal(m)
+			} else {
+				data, err = msgpackCodec{}.encode(msg)
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, conn.WriteMessage(websocket.BinaryMessage, data))
+		}
+
+		assert.Equal(t, ingestWindow, read().Window, subprotocol)
+
+		// Test case: a snapshot is acked and a delta without one nacked in the negotiated encoding
+		write(entity.IngestMessage{ID: 1, Type: entity.IngestSnapshot, Books: []*entity.OrderBook{snapshot}})
+		write(entity.IngestMessage{ID: 2, Type: entity.IngestDelta, Delta: &entity.OrderBookDelta{Exchange: "exchange1", Pair: "ETH/USDT"}})
+		replies := map[uint64]entity.IngestReply{}
+		for i := 0; i < 2; i++ {
+			reply := read()
+			replies[reply.ID] = reply
+		}
+		assert.Equal(t, 1, replies[1].Books, subprotocol)
+		assert.Equal(t, "no_snapshot", replies[2].Error.Code, subprotocol)
+	}
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+	"github.com/go-chi/chi"
+)
+
+type OrderEventController interface {
+	SaveOrderEventHandler(w http.ResponseWriter, r *http.Request)
+	GetOrderTimelineHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type orderEventControllerImpl struct {
+	svc service.OrderEventService
+}
+
+func NewOrderEventController(svc service.OrderEventService) OrderEventController {
+	return &orderEventControllerImpl{svc: svc}
+}
+
+// @Summary Save Order Event
+// @Description Move an order to a new lifecycle status. Illegal transitions are rejected.
+// @Tags order
+// @Accept json
+// @Produce json
+// @Param orderEvent body entity.OrderEvent true "Order Event"
+// @Success 200 {string} string "OK"
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 409 {object} entity.ErrorResponse "Conflict"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /orders/events [post]
+func (c *orderEventControllerImpl) SaveOrderEventHandler(w http.ResponseWriter, r *http.Request) {
+	var req entity.OrderEvent
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, malformedRequest(err))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err := c.svc.TransitionOrder(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Get Order Timeline
+// @Description Retrieve the lifecycle events and fills of an order.
+// @Tags order
+// @Produce json
+// @Param orderID path string true "Order ID"
+// @Success 200 {object} entity.OrderTimeline
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /orders/{orderID}/timeline [get]
+func (c *orderEventControllerImpl) GetOrderTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	orderID := chi.URLParam(r, "orderID")
+
+	timeline, err := c.svc.GetOrderTimeline(orderID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(timeline)
+	w.Write(bytes)
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSaveOrderEventHandler(t *testing.T) {
+	mockService := &mocks.MockOrderEventService{}
+	controller := NewOrderEventController(mockService)
+
+	reqBody := []byte(`{"order_id": "order1", "status": "new"}`)
+	req := httptest.NewRequest("POST", "/orders/events", bytes.NewBuffer(reqBody))
+	rr := httptest.NewRecorder()
+
+	mockService.On("TransitionOrder", mock.MatchedBy(func(e entity.OrderEvent) bool {
+		return e.OrderID == "order1" && e.Status == entity.OrderStatusNew && !e.EventTime.IsZero()
+	})).Return(nil)
+
+	http.HandlerFunc(controller.SaveOrderEventHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSaveOrderEventHandler_Errors(t *testing.T) {
+	mockService := &mocks.MockOrderEventService{}
+	controller := NewOrderEventController(mockService)
+
+	mockService.On("TransitionOrder", mock.Anything).Return(fmt.Errorf("%w: filled to new", entity.ErrIllegalTransition))
+
+	tests := map[string]int{
+		`invalid-json`:      http.StatusBadRequest,
+		`{"status": "new"}`: http.StatusBadRequest,
+		`{"order_id": "order1", "status": "open"}`: http.StatusBadRequest,
+		`{"order_id": "order1", "status": "new"}`:  http.StatusConflict,
+	}
+	for body, code := range tests {
+		req := httptest.NewRequest("POST", "/orders/events", bytes.NewBufferString(body))
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.SaveOrderEventHandler).ServeHTTP(rr, req)
+
+		assert.Equal(t, code, rr.Code, body)
+	}
+}
+
+func TestGetOrderTimelineHandler(t *testing.T) {
+	mockService := &mocks.MockOrderEventService{}
+	controller := NewOrderEventController(mockService)
+
+	timeline := &entity.OrderTimeline{
+		OrderID: "order1",
+		Status:  entity.OrderStatusNew,
+		Events:  []*entity.OrderEvent{{OrderID: "order1", Status: entity.OrderStatusNew}},
+	}
+	mockService.On("GetOrderTimeline", "order1").Return(timeline, nil)
+	mockService.On("GetOrderTimeline", "order2").Return((*entity.OrderTimeline)(nil), entity.ErrNotFound)
+
+	for orderID, code := range map[string]int{"order1": http.StatusOK, "order2": http.StatusNotFound} {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("orderID", orderID)
+		req := httptest.NewRequest("GET", "/orders/"+orderID+"/timeline", nil)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.GetOrderTimelineHandler).ServeHTTP(rr, req)
+
+		assert.Equal(t, code, rr.Code, orderID)
+		if code == http.StatusOK {
+			expectedBody, _ := json.Marshal(timeline)
+			assert.Equal(t, expectedBody, rr.Body.Bytes())
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/go-chi/chi"
+)
+
+// pathParam returns a URL parameter with its escapes decoded, so a pair such as BTC%2FUSDT fits in one path segment.
+func pathParam(r *http.Request, name string) (string, error) {
+	v, err := url.PathUnescape(chi.URLParam(r, name))
+	if err != nil {
+		return "", badQuery(name, err)
+	}
+	return v, nil
+}
+
+/*
+historyFilterQuery reads a history filter from the query string. The pair, label and algorithm_name
+parameters may be repeated or hold comma-separated values, from and to are RFC 3339 times.
+*/
+func historyFilterQuery(q url.Values) (entity.HistoryFilter, error) {
+	filter := entity.HistoryFilter{
+		ClientName:     q.Get("client_name"),
+		ExchangeName:   q.Get("exchange_name"),
+		Pairs:          queryList(q, "pair"),
+		Labels:         queryList(q, "label"),
+		AlgorithmNames: queryList(q, "algorithm_name"),
+	}
+
+	var err error
+	if filter.From, err = queryTime(q, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = queryTime(q, "to"); err != nil {
+		return filter, err
+	}
+
+	return filter, filter.Validate()
+}
+
+func queryList(q url.Values, key string) []string {
+	var values []string
+	for _, v := range q[key] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+func queryTime(q url.Values, key string) (time.Time, error) {
+	v := q.Get(key)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, badQuery(key, err)
+	}
+	return t, nil
+}
+
+func queryFloat(q url.Values, key string) (float64, error) {
+	v := q.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, badQuery(key, err)
+	}
+	return f, nil
+}
+
+func queryBool(q url.Values, key string) (bool, error) {
+	v := q.Get(key)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, badQuery(key, err)
+	}
+	return b, nil
+}
+
+/*
+Deprecated marks the responses of a route kept working for existing callers
+with a Deprecation header and a link to the route replacing it.
+*/
+func Deprecated(successor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", "<"+successor+">; rel=\"successor-version\"")
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+	"github.com/egorque1/vortex-test/internal/ratelimit"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/httprate"
+)
+
+type RateLimitController interface {
+	GetRateQuotasHandler(w http.ResponseWriter, r *http.Request)
+	SetRateQuotaHandler(w http.ResponseWriter, r *http.Request)
+	DeleteRateQuotaHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type rateLimitControllerImpl struct {
+	svc service.RateLimitService
+}
+
+func NewRateLimitController(svc service.RateLimitService) RateLimitController {
+	return &rateLimitControllerImpl{svc: svc}
+}
+
+/*
+RateLimit limits the requests to the routes of group of each API key, or of each client IP if the request is not authenticated,
+to the quotas of svc. Requests limited by IP share the bucket of the group whatever their route. Limited responses carry
+the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers, and requests over the limit are answered with 429 and a Retry-After header.
+*/
+func RateLimit(svc service.RateLimitService, group string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, keyID, route := "", "", ""
+			if key := service.APIKeyFromContext(r.Context()); key != nil {
+				principal, keyID = "key:"+key.ID, key.ID
+				route = r.Method + " " + chi.RouteContext(r.Context()).RoutePattern()
+			} else {
+				ip, _ := httprate.KeyByIP(r)
+				principal = "ip:" + ip
+			}
+
+			if result, limited := svc.Allow(principal, keyID, route, group); !writeRateLimit(w, result, limited) {
+				RateLimitedHandler(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeRateLimit sets the rate limit headers of result if the request is limited, and returns whether it is allowed.
+func writeRateLimit(w http.ResponseWriter, result ratelimit.Result, limited bool) bool {
+	if limited {
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	}
+	if !result.Allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(max(1, ceilSeconds(result.RetryAfter))))
+	}
+	return result.Allowed
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// @Summary List Rate Quotas
+// @Description List the rate quotas in force: the defaults of the route groups and the quotas set for routes, groups and API keys.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} entity.RateQuota
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /admin/rate-limits [get]
+func (c *rateLimitControllerImpl) GetRateQuotasHandler(w http.ResponseWriter, r *http.Request) {
+	quotas, err := c.svc.GetRateQuotas()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(quotas)
+	w.Write(bytes)
+}
+
+// @Summary Set Rate Quota
+// @Description Set the requests per second and burst of a route group, such as read or write, or of a single route, such as GET /v1/history/{clientName},
+// @Description for one API key or, without key_id, for every caller. The quota applies at once on this instance and within a minute on the others.
+// @Tags admin
+// @Accept json
+// @Param rateQuota body entity.RateQuota true "Rate Quota"
+// @Success 200 {string} string "OK"
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /admin/rate-limits [put]
+func (c *rateLimitControllerImpl) SetRateQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	var req entity.RateQuota
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, malformedRequest(err))
+		return
+	}
+
+	if err := c.svc.SetRateQuota(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary Delete Rate Quota
+// @Description Delete a quota set for a route or group, so the default of the route group applies again.
+// @Tags admin
+// @Param route query string true "Route group or method and pattern"
+// @Param key_id query string false "API Key ID"
+// @Success 204 {string} string "No Content"
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /admin/rate-limits [delete]
+func (c *rateLimitControllerImpl) DeleteRateQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if err := entity.Required("route", query.Get("route")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := c.svc.DeleteRateQuota(query.Get("key_id"), query.Get("route")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
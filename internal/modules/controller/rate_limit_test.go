@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+	"github.com/egorque1/vortex-test/internal/ratelimit"
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRateLimit(t *testing.T) {
+	mockService := &mocks.MockRateLimitService{}
+	key := &entity.APIKey{ID: "key1", Scopes: []entity.Scope{entity.ScopeReadHistory}}
+
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-API-Key") != "" {
+				r = r.WithContext(service.WithAPIKey(r.Context(), key))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	router.Group(func(r chi.Router) {
+		r.Use(RateLimit(mockService, "read"))
+		r.Get("/v1/history/{clientName}", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	})
+
+	allowed := ratelimit.Result{Allowed: true, Limit: 10, Remaining: 9, Reset: 100 * time.Millisecond}
+	denied := ratelimit.Result{Limit: 10, Reset: 1500 * time.Millisecond, RetryAfter: 200 * time.Millisecond}
+	mockService.On("Allow", "key:key1", "key1", "GET /v1/history/{clientName}", "read").Return(allowed, true).Once()
+	mockService.On("Allow", "key:key1", "key1", "GET /v1/history/{clientName}", "read").Return(denied, true).Once()
+	mockService.On("Allow", "ip:192.0.2.1", "", "", "read").Return(ratelimit.Result{Allowed: true}, false)
+
+	req := httptest.NewRequest("GET", "/v1/history/client1", nil)
+	req.Header.Set("X-API-Key", "key")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "10", rr.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "9", rr.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Reset"))
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", rr.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+	assert.Equal(t, "rate_limited", decodeError(t, rr).Code)
+
+	// Anonymous requests are limited by client IP for the whole group; requests without a quota get no headers.
+	req = httptest.NewRequest("GET", "/v1/history/client1", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("RateLimit-Limit"))
+	mockService.AssertExpectations(t)
+}
+
+func TestSetRateQuotaHandler(t *testing.T) {
+	mockService := &mocks.MockRateLimitService{}
+	controller := NewRateLimitController(mockService)
+
+	quota := entity.RateQuota{KeyID: "key1", Route: "GET /v1/history/{clientName}", Rate: 5, Burst: 10}
+	mockService.On("SetRateQuota", quota).Return(nil)
+	mockService.On("SetRateQuota", mock.Anything).Return(entity.Invalid("route", "route group exports does not exist"))
+
+	rr := httptest.NewRecorder()
+	controller.SetRateQuotaHandler(rr, httptest.NewRequest("PUT", "/admin/rate-limits", bytes.NewBufferString(`{"key_id":"key1","route":"GET /v1/history/{clientName}","rate":5,"burst":10}`)))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	controller.SetRateQuotaHandler(rr, httptest.NewRequest("PUT", "/admin/rate-limits", bytes.NewBufferString(`{"route":"exports","rate":5,"burst":10}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestDeleteRateQuotaHandler(t *testing.T) {
+	mockService := &mocks.MockRateLimitService{}
+	controller := NewRateLimitController(mockService)
+
+	mockService.On("DeleteRateQuota", "key1", "GET /v1/history/{clientName}").Return(nil)
+
+	rr := httptest.NewRecorder()
+	controller.DeleteRateQuotaHandler(rr, httptest.NewRequest("DELETE", "/admin/rate-limits?key_id=key1&route=GET+%2Fv1%2Fhistory%2F%7BclientName%7D", nil))
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = httptest.NewRecorder()
+	controller.DeleteRateQuotaHandler(rr, httptest.NewRequest("DELETE", "/admin/rate-limits", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNumberOfCalls(t, "DeleteRateQuota", 1)
+}
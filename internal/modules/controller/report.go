@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+)
+
+type ReportController interface {
+	GetCommissionReportHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type reportControllerImpl struct {
+	svc service.ReportService
+}
+
+func NewReportController(svc service.ReportService) ReportController {
+	return &reportControllerImpl{svc: svc}
+}
+
+// @Summary Get Commission Report
+// @Description Aggregate commissions by client, exchange, pair, algorithm and liquidity per day or month.
+// @Description group_by may be repeated or hold comma-separated dimensions.
+// @Tags report
+// @Produce json
+// @Param client_name query string false "Client Name"
+// @Param exchange_name query string false "Exchange Name"
+// @Param pair query string false "Trading Pair"
+// @Param algorithm_name_placed query string false "Algorithm Name"
+// @Param from query string false "Earliest fill time, RFC 3339"
+// @Param to query string false "Latest fill time, RFC 3339"
+// @Param period query string false "day (default) or month"
+// @Param group_by query []string false "Dimensions to group by" collectionFormat(multi)
+// @Success 200 {array} entity.CommissionReport
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /reports/commission [get]
+func (c *reportControllerImpl) GetCommissionReportHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := entity.CommissionReportRequest{
+		ClientName:          q.Get("client_name"),
+		ExchangeName:        q.Get("exchange_name"),
+		Pair:                q.Get("pair"),
+		AlgorithmNamePlaced: q.Get("algorithm_name_placed"),
+		Period:              q.Get("period"),
+		GroupBy:             queryList(q, "group_by"),
+	}
+
+	var err error
+	if req.From, err = queryTime(q, "from"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if req.To, err = queryTime(q, "to"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	report, err := c.svc.GetCommissionReport(&req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(report)
+	w.Write(bytes)
+}
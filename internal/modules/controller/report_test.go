@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetCommissionReportHandler(t *testing.T) {
+	mockService := &mocks.MockReportService{}
+	controller := NewReportController(mockService)
+
+	req := httptest.NewRequest("GET", "/reports/commission?client_name=client1&group_by=pair,liquidity", nil)
+	rr := httptest.NewRecorder()
+
+	report := []*entity.CommissionReport{
+		{Pair: "BTC/USDT", Liquidity: "taker", OrderCount: 2, Notional: 1000, Commission: 1, FeeRateBps: 10},
+	}
+	mockService.On("GetCommissionReport", mock.MatchedBy(func(r *entity.CommissionReportRequest) bool {
+		return r.ClientName == "client1" && r.Period == entity.ReportPeriodDay &&
+			slices.Equal(r.GroupBy, []string{"pair", "liquidity"})
+	})).Return(report, nil)
+
+	http.HandlerFunc(controller.GetCommissionReportHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	expectedBody, _ := json.Marshal(report)
+	assert.Equal(t, expectedBody, rr.Body.Bytes())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCommissionReportHandler_BadRequest(t *testing.T) {
+	controller := NewReportController(nil)
+
+	for _, query := range []string{"from=yesterday", "period=week", "group_by=side"} {
+		req := httptest.NewRequest("GET", "/reports/commission?"+query, nil)
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.GetCommissionReportHandler).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+)
+
+type RiskController interface {
+	SetRiskLimitHandler(w http.ResponseWriter, r *http.Request)
+	GetRiskLimitsHandler(w http.ResponseWriter, r *http.Request)
+	CheckOrderHandler(w http.ResponseWriter, r *http.Request)
+	GetRiskBreachesHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type riskControllerImpl struct {
+	svc service.RiskService
+}
+
+func NewRiskController(svc service.RiskService) RiskController {
+	return &riskControllerImpl{svc: svc}
+}
+
+// @Summary Set Risk Limit
+// @Description Set the risk limits of a client on an exchange for a pair, or for every pair with an empty pair. A zero limit is not enforced.
+// @Tags risk
+// @Accept json
+// @Param riskLimit body entity.RiskLimit true "Risk Limit"
+// @Success 200 {string} string "OK"
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /risk/limits [put]
+func (c *riskControllerImpl) SetRiskLimitHandler(w http.ResponseWriter, r *http.Request) {
+	var req entity.RiskLimit
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, malformedRequest(err))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := c.svc.SetRiskLimit(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// @Summary List Risk Limits
+// @Description List risk limits, optionally of a single client.
+// @Tags risk
+// @Produce json
+// @Param client_name query string false "Client Name"
+// @Success 200 {array} entity.RiskLimit
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /risk/limits [get]
+func (c *riskControllerImpl) GetRiskLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	clientName := r.URL.Query().Get("client_name")
+	if err := authorize(r, entity.ScopeReadHistory, clientName); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	limits, err := c.svc.GetRiskLimits(clientName)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(limits)
+	w.Write(bytes)
+}
+
+// @Summary Check Order
+// @Description Check a proposed order against the risk limits of the client, its current position and the latest order book.
+// @Description A rejected order is still answered with 200, allowed is false and the breaches list the limits it would exceed.
+// @Tags risk
+// @Accept json
+// @Produce json
+// @Param riskCheckRequest body entity.RiskCheckRequest true "Proposed Order"
+// @Success 200 {object} entity.RiskCheck
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 422 {object} entity.ErrorResponse "No price to value the order at"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /risk/check [post]
+func (c *riskControllerImpl) CheckOrderHandler(w http.ResponseWriter, r *http.Request) {
+	var req entity.RiskCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, malformedRequest(err))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := authorize(r, entity.ScopeReadHistory, req.ClientName); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	check, err := c.svc.CheckOrder(&req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(check)
+	w.Write(bytes)
+}
+
+// @Summary List Risk Breaches
+// @Description List the latest limits exceeded by saved fills, optionally of a single client.
+// @Tags risk
+// @Produce json
+// @Param client_name query string false "Client Name"
+// @Success 200 {array} entity.RiskBreach
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /risk/breaches [get]
+func (c *riskControllerImpl) GetRiskBreachesHandler(w http.ResponseWriter, r *http.Request) {
+	clientName := r.URL.Query().Get("client_name")
+	if err := authorize(r, entity.ScopeReadHistory, clientName); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	breaches, err := c.svc.GetRiskBreaches(clientName)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(breaches)
+	w.Write(bytes)
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckOrderHandler(t *testing.T) {
+	mockService := &mocks.MockRiskService{}
+	controller := NewRiskController(mockService)
+
+	req := &entity.RiskCheckRequest{ClientName: "client1", ExchangeName: "exchange1", Pair: "pair1", Side: entity.SideBuy, BaseQty: 2}
+	check := &entity.RiskCheck{
+		Allowed:  false,
+		Breaches: []*entity.RiskBreach{{Limit: entity.RiskLimitMaxPosition, Threshold: 1, Value: 2}},
+	}
+	mockService.On("CheckOrder", req).Return(check, nil).Once()
+	mockService.On("CheckOrder", req).Return((*entity.RiskCheck)(nil), entity.ErrNoMarkPrice)
+
+	reqBody, _ := json.Marshal(req)
+	r := httptest.NewRequest("POST", "/risk/check", bytes.NewBuffer(reqBody))
+	rr := httptest.NewRecorder()
+
+	withAPIKey(adminKey, controller.CheckOrderHandler).ServeHTTP(rr, r)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expectedBody, _ := json.Marshal(check)
+	assert.Equal(t, expectedBody, rr.Body.Bytes())
+
+	r = httptest.NewRequest("POST", "/risk/check", bytes.NewBuffer(reqBody))
+	rr = httptest.NewRecorder()
+
+	withAPIKey(adminKey, controller.CheckOrderHandler).ServeHTTP(rr, r)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+
+	r = httptest.NewRequest("POST", "/risk/check", bytes.NewBufferString(`{"client_name": "client1", "exchange_name": "exchange1", "pair": "pair1", "side": "buy"}`))
+	rr = httptest.NewRecorder()
+
+	withAPIKey(adminKey, controller.CheckOrderHandler).ServeHTTP(rr, r)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestSetRiskLimitHandler(t *testing.T) {
+	mockService := &mocks.MockRiskService{}
+	controller := NewRiskController(mockService)
+
+	limit := entity.RiskLimit{ClientName: "client1", ExchangeName: "exchange1", MaxLoss: 1000}
+	mockService.On("SetRiskLimit", limit).Return(nil)
+
+	reqBody, _ := json.Marshal(limit)
+	r := httptest.NewRequest("PUT", "/risk/limits", bytes.NewBuffer(reqBody))
+	rr := httptest.NewRecorder()
+
+	http.HandlerFunc(controller.SetRiskLimitHandler).ServeHTTP(rr, r)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	r = httptest.NewRequest("PUT", "/risk/limits", bytes.NewBufferString(`{"client_name": "client1", "exchange_name": "exchange1", "max_loss": -1}`))
+	rr = httptest.NewRecorder()
+
+	http.HandlerFunc(controller.SetRiskLimitHandler).ServeHTTP(rr, r)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	mockService.AssertNumberOfCalls(t, "SetRiskLimit", 1)
+}
+
+func TestGetRiskLimitsHandler_ClientKey(t *testing.T) {
+	mockService := &mocks.MockRiskService{}
+	controller := NewRiskController(mockService)
+
+	key := &entity.APIKey{ID: "key1", Scopes: []entity.Scope{entity.ScopeReadHistory}, ClientNames: []string{"client1"}}
+	limits := []*entity.RiskLimit{{ClientName: "client1", ExchangeName: "exchange1", MaxLoss: 1000}}
+	mockService.On("GetRiskLimits", "client1").Return(limits, nil)
+
+	for url, status := range map[string]int{
+		"/risk/limits?client_name=client1": http.StatusOK,
+		"/risk/limits?client_name=client2": http.StatusForbidden,
+		"/risk/limits":                     http.StatusForbidden,
+	} {
+		r := httptest.NewRequest("GET", url, nil)
+		rr := httptest.NewRecorder()
+
+		withAPIKey(key, controller.GetRiskLimitsHandler).ServeHTTP(rr, r)
+
+		assert.Equal(t, status, rr.Code, url)
+	}
+	mockService.AssertNumberOfCalls(t, "GetRiskLimits", 1)
+}
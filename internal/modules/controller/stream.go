@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// streamWriteWait is how long a write to a stream may block before the subscriber is disconnected.
+	streamWriteWait = 10 * time.Second
+	// streamPongWait is how long a subscriber may go without answering a ping.
+	streamPongWait = 60 * time.Second
+	// streamPingPeriod must be shorter than streamPongWait.
+	streamPingPeriod = 30 * time.Second
+	// fillHeartbeatPeriod is how often an idle fills stream sends a comment to keep proxies from closing it.
+	fillHeartbeatPeriod = 15 * time.Second
+	// fillRetry is how long EventSource clients wait before reconnecting, in milliseconds.
+	fillRetry = 3000
+)
+
+type StreamController interface {
+	OrderBookStreamHandler(w http.ResponseWriter, r *http.Request)
+	FillStreamHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type streamControllerImpl struct {
+	svc      service.StreamService
+	upgrader websocket.Upgrader
+	// pingPeriod, pongWait and heartbeatPeriod are fields so tests can shorten them.
+	pingPeriod      time.Duration
+	pongWait        time.Duration
+	heartbeatPeriod time.Duration
+}
+
+func NewStreamController(svc service.StreamService) StreamController {
+	return &streamControllerImpl{
+		svc: svc,
+		upgrader: websocket.Upgrader{
+			// Dashboards are served from other origins; the stream is read-only.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		pingPeriod:      streamPingPeriod,
+		pongWait:        streamPongWait,
+		heartbeatPeriod: fillHeartbeatPeriod,
+	}
+}
+
+// @Summary Stream Order Books
+// @Description Open a WebSocket streaming order books as entity.OrderBookMessage JSON messages: the latest stored snapshot of each book, then every snapshot saved after it.
+// @Description The server pings every 30 seconds and disconnects subscribers that do not answer within 60 seconds.
+// @Description Subscribers that fall too far behind are disconnected with close code 1008 and reason "slow consumer".
+// @Tags order
+// @Produce json
+// @Param book query []string true "Books as exchange:pair, repeated or comma-separated, e.g. binance:BTC/USDT" collectionFormat(multi)
+// @Success 101 {object} entity.OrderBookMessage "Switching Protocols"
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /v1/stream/orderbooks [get]
+func (c *streamControllerImpl) OrderBookStreamHandler(w http.ResponseWriter, r *http.Request) {
+	keys, err := entity.ParseBookKeys(queryList(r.URL.Query(), "book"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	sub, snapshots, err := c.svc.SubscribeOrderBooks(keys)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	defer sub.Close()
+
+	conn, err := c.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already answered the request.
+		return
+	}
+	defer conn.Close()
+
+	closed := c.readUntilClosed(conn)
+
+	filter := entity.NewSnapshotFilter(snapshots)
+	for _, book := range snapshots {
+		if err := c.write(conn, entity.OrderBookMessage{Type: entity.OrderBookSnapshot, Book: book}); err != nil {
+			return
+		}
+	}
+
+	ping := time.NewTicker(c.pingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case book, ok := <-sub.C:
+			if !ok {
+				log.Printf("request %s: disconnecting slow order book subscriber", requestID(r))
+				conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "slow consumer"), time.Now().Add(streamWriteWait))
+				return
+			}
+
+			if !filter.Pass(book) {
+				continue
+			}
+
+			if err := c.write(conn, entity.OrderBookMessage{Type: entity.OrderBookUpdate, Book: book}); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// @Summary Stream Fills
+// @Description Stream fills as Server-Sent Events as they are saved, each a "fill" event whose data is an entity.HistoryOrder and whose id increases with every fill.
+// @Description A client reconnecting with the Last-Event-ID header, or the last_event_id parameter, first receives the fills it missed that are still kept; the latest 1000 fills are kept.
+// @Description Subscribers that fall too far behind are disconnected and can resume the same way.
+// @Tags order
+// @Produce text/event-stream
+// @Param Last-Event-ID header int false "ID of the last fill received"
+// @Param last_event_id query int false "ID of the last fill received, for clients that cannot set headers"
+// @Param client_name query string false "Client Name"
+// @Param exchange_name query string false "Exchange Name"
+// @Param pair query []string false "Pairs, repeated or comma-separated" collectionFormat(multi)
+// @Param label query []string false "Labels, repeated or comma-separated" collectionFormat(multi)
+// @Param algorithm_name query []string false "Algorithm Names, repeated or comma-separated" collectionFormat(multi)
+// @Success 200 {object} entity.HistoryOrder
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Security ApiKeyAuth
+// @Router /v1/stream/fills [get]
+func (c *streamControllerImpl) FillStreamHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, err := historyFilterQuery(q)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if err := authorize(r, entity.ScopeReadHistory, filter.ClientName); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = q.Get("last_event_id")
+	}
+	var lastID uint64
+	if lastEventID != "" {
+		if lastID, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			writeError(w, r, badQuery("last_event_id", err))
+			return
+		}
+	}
+
+	sub, missed := c.svc.SubscribeFills(lastID)
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", fillRetry)
+
+	send := func(event *entity.FillEvent) error {
+		if !filter.Matches(event.Order) {
+			return nil
+		}
+		data, _ := json.Marshal(event.Order)
+		rc.SetWriteDeadline(time.Now().Add(streamWriteWait))
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: fill\ndata: %s\n\n", event.ID, data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	for _, event := range missed {
+		if err := send(event); err != nil {
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(c.heartbeatPeriod)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-sub.C:
+			if !ok {
+				log.Printf("request %s: disconnecting slow fill subscriber", requestID(r))
+				return
+			}
+			if err := send(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			rc.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (c *streamControllerImpl) write(conn *websocket.Conn, msg entity.OrderBookMessage) error {
+	conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+	return conn.WriteJSON(msg)
+}
+
+/*
+readUntilClosed reads the connection in the background, as it must be read for pongs and the close handshake
+to be processed, and returns a channel closed when the subscriber disconnects or stops answering pings.
+Messages sent by the subscriber are discarded.
+*/
+func (c *streamControllerImpl) readUntilClosed(conn *websocket.Conn) <-chan struct{} {
+	closed := make(chan struct{})
+
+	conn.SetReadLimit(512)
+	conn.SetReadDeadline(time.Now().Add(c.pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(c.pongWait))
+	})
+
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	return closed
+}
@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/egorque1/vortex-test/internal/pubsub"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func dialStream(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/v1/stream/orderbooks?"+query, nil)
+	if err != nil {
+		t.Fatalf("error dialing stream: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	return conn
+}
+
+func TestOrderBookStreamHandler(t *testing.T) {
+	mockService := &mocks.MockStreamService{}
+	controller := NewStreamController(mockService)
+	server := httptest.NewServer(http.HandlerFunc(controller.OrderBookStreamHandler))
+	defer server.Close()
+
+	broker := pubsub.New[*entity.OrderBook](8)
+	keys := []entity.BookKey{{Exchange: "exchange1", Pair: "BTC/USDT"}, {Exchange: "exchange2", Pair: "ETH/USDT"}}
+	snapshot := &entity.OrderBook{Exchange: "exchange1", Pair: "BTC/USDT", Sequence: 5, Timestamp: time.Unix(100, 0).UTC()}
+	mockService.On("SubscribeOrderBooks", keys).Return(broker.Subscribe("exchange1:BTC/USDT", "exchange2:ETH/USDT"), []*entity.OrderBook{snapshot}, nil)
+
+	conn := dialStream(t, server, "book=exchange1:BTC/USDT,exchange2:ETH/USDT")
+	defer conn.Close()
+
+	var msg entity.OrderBookMessage
+	assert.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, entity.OrderBookMessage{Type: entity.OrderBookSnapshot, Book: snapshot}, msg)
+
+	stale := &entity.OrderBook{Exchange: "exchange1", Pair: "BTC/USDT", Sequence: 5, Timestamp: snapshot.Timestamp}
+	update := &entity.OrderBook{Exchange: "exchange1", Pair: "BTC/USDT", Sequence: 6, Timestamp: snapshot.Timestamp}
+	broker.Publish("exchange1:BTC/USDT", stale)
+	broker.Publish("exchange1:BTC/USDT", update)
+
+	assert.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, entity.OrderBookMessage{Type: entity.OrderBookUpdate, Book: update}, msg)
+}
+
+func TestOrderBookStreamHandler_SlowConsumer(t *testing.T) {
+	mockService := &mocks.MockStreamService{}
+	controller := NewStreamController(mockService)
+	server := httptest.NewServer(http.HandlerFunc(controller.OrderBookStreamHandler))
+	defer server.Close()
+
+	broker := pubsub.New[*entity.OrderBook](1)
+	sub := broker.Subscribe("exchange1:BTC/USDT")
+	mockService.On("SubscribeOrderBooks", []entity.BookKey{{Exchange: "exchange1", Pair: "BTC/USDT"}}).Return(sub, []*entity.OrderBook{}, nil)
+
+	// Fill the buffer and overflow it before the stream is opened.
+	broker.Publish("exchange1:BTC/USDT", &entity.OrderBook{Exchange: "exchange1", Pair: "BTC/USDT", Sequence: 1})
+	broker.Publish("exchange1:BTC/USDT", &entity.OrderBook{Exchange: "exchange1", Pair: "BTC/USDT", Sequence: 2})
+
+	conn := dialStream(t, server, "book=exchange1:BTC/USDT")
+	defer conn.Close()
+
+	var msg entity.OrderBookMessage
+	assert.NoError(t, conn.ReadJSON(&msg))
+	assert.Equal(t, int64(1), msg.Book.Sequence)
+
+	err := conn.ReadJSON(&msg)
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation))
+}
+
+func TestOrderBookStreamHandler_Heartbeat(t *testing.T) {
+	mockService := &mocks.MockStreamService{}
+	controller := NewStreamController(mockService).(*streamControllerImpl)
+	controller.pingPeriod = 10 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(controller.OrderBookStreamHandler))
+	defer server.Close()
+
+	broker := pubsub.New[*entity.OrderBook](1)
+	mockService.On("SubscribeOrderBooks", []entity.BookKey{{Exchange: "exchange1", Pair: "BTC/USDT"}}).Return(broker.Subscribe("exchange1:BTC/USDT"), []*entity.OrderBook{}, nil)
+
+	conn := dialStream(t, server, "book=exchange1:BTC/USDT")
+	defer conn.Close()
+
+	pinged := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	go conn.ReadMessage()
+
+	select {
+	case <-pinged:
+	case <-time.After(time.Second):
+		t.Fatal("no ping received")
+	}
+}
+
+func TestOrderBookStreamHandler_BadRequest(t *testing.T) {
+	controller := NewStreamController(&mocks.MockStreamService{})
+
+	for _, query := range []string{"", "book=BTC/USDT"} {
+		req := httptest.NewRequest("GET", "/v1/stream/orderbooks?"+query, nil)
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.OrderBookStreamHandler).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Equal(t, "book", decodeError(t, rr).Details[0].Field)
+	}
+}
+
+// readEvent reads the fields of the next Server-Sent Event, skipping comments and retry hints.
+func readEvent(t *testing.T, body *bufio.Reader) map[string]string {
+	event := map[string]string{}
+	for {
+		line, err := body.ReadString('\n')
+		if err != nil {
+			t.Fatalf("error reading event: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if len(event) > 0 && event["retry"] == "" {
+				return event
+			}
+			event = map[string]string{}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			event[":"] = line
+			return event
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		event[field] = value
+	}
+}
+
+func TestFillStreamHandler(t *testing.T) {
+	mockService := &mocks.MockStreamService{}
+	controller := NewStreamController(mockService)
+	server := httptest.NewServer(withAPIKey(adminKey, controller.FillStreamHandler))
+	defer server.Close()
+
+	broker := pubsub.New[*entity.FillEvent](8)
+	sub := broker.Subscribe("")
+	missed := []*entity.FillEvent{
+		{ID: 4, Order: &entity.HistoryOrder{FillID: "fill4", ClientName: "client2"}},
+		{ID: 5, Order: &entity.HistoryOrder{FillID: "fill5", ClientName: "client1"}},
+	}
+	mockService.On("SubscribeFills", uint64(3)).Return(sub, missed)
+
+	req, _ := http.NewRequest("GET", server.URL+"/v1/stream/fills?client_name=client1", nil)
+	req.Header.Set("Last-Event-ID", "3")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	body := bufio.NewReader(resp.Body)
+
+	event := readEvent(t, body)
+	assert.Equal(t, "5", event["id"])
+	assert.Equal(t, "fill", event["event"])
+	assert.Contains(t, event["data"], `"fill_id":"fill5"`)
+
+	broker.Publish("", &entity.FillEvent{ID: 6, Order: &entity.HistoryOrder{FillID: "fill6", ClientName: "client2"}})
+	broker.Publish("", &entity.FillEvent{ID: 7, Order: &entity.HistoryOrder{FillID: "fill7", ClientName: "client1"}})
+
+	event = readEvent(t, body)
+	assert.Equal(t, "7", event["id"])
+	assert.Contains(t, event["data"], `"fill_id":"fill7"`)
+}
+
+func TestFillStreamHandler_Heartbeat(t *testing.T) {
+	mockService := &mocks.MockStreamService{}
+	controller := NewStreamController(mockService).(*streamControllerImpl)
+	controller.heartbeatPeriod = 10 * time.Millisecond
+	server := httptest.NewServer(withAPIKey(adminKey, controller.FillStreamHandler))
+	defer server.Close()
+
+	broker := pubsub.New[*entity.FillEvent](1)
+	mockService.On("SubscribeFills", uint64(9)).Return(broker.Subscribe(""), []*entity.FillEvent(nil))
+
+	resp, err := http.Get(server.URL + "/v1/stream/fills?last_event_id=9")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, ": heartbeat", readEvent(t, bufio.NewReader(resp.Body))[":"])
+}
+
+func TestFillStreamHandler_BadRequest(t *testing.T) {
+	controller := NewStreamController(&mocks.MockStreamService{})
+
+	req := httptest.NewRequest("GET", "/v1/stream/fills", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	rr := httptest.NewRecorder()
+
+	withAPIKey(adminKey, controller.FillStreamHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "last_event_id", decodeError(t, rr).Details[0].Field)
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+)
+
+type TCAController interface {
+	GetTCAReportsHandler(w http.ResponseWriter, r *http.Request)
+}
+
+type tcaControllerImpl struct {
+	svc service.TCAService
+}
+
+func NewTCAController(svc service.TCAService) TCAController {
+	return &tcaControllerImpl{svc: svc}
+}
+
+var tcaCSVHeader = []string{
+	"parent_id", "client_name", "exchange_name", "pair", "side", "fill_count", "quantity",
+	"buy_qty", "sell_qty", "avg_buy_price", "avg_sell_price", "start_time", "end_time", "arrival_price",
+	"avg_fill_price", "interval_vwap", "interval_twap", "participation", "shortfall_bps", "vwap_slippage_bps",
+	"twap_slippage_bps", "fees", "fees_bps", "total_cost_bps",
+}
+
+// @Summary Get TCA Reports
+// @Description Transaction cost analysis of parent orders grouped by label or algorithm, as JSON or a CSV download.
+// @Description Filters left out are not applied; list filters may be repeated or comma-separated.
+// @Tags analytics
+// @Produce json,text/csv
+// @Param format query string false "Set to csv to download the report as CSV"
+// @Param client_name query string false "Client Name"
+// @Param exchange_name query string false "Exchange Name"
+// @Param pair query []string false "Trading Pairs" collectionFormat(multi)
+// @Param label query []string false "Labels" collectionFormat(multi)
+// @Param algorithm_name query []string false "Algorithm Names" collectionFormat(multi)
+// @Param from query string false "Earliest fill time, RFC 3339"
+// @Param to query string false "Latest fill time, RFC 3339"
+// @Param group_by query string false "label (default) or algorithm"
+// @Success 200 {array} entity.TCAReport
+// @Failure 400 {object} entity.ErrorResponse "Bad Request"
+// @Failure 401 {object} entity.ErrorResponse "Unauthorized"
+// @Failure 403 {object} entity.ErrorResponse "Forbidden"
+// @Failure 404 {object} entity.ErrorResponse "Not Found"
+// @Failure 500 {object} entity.ErrorResponse "Internal Server Error"
+// @Failure 503 {object} entity.ErrorResponse "Storage Unavailable"
+// @Failure 504 {object} entity.ErrorResponse "Storage Timeout"
+// @Security ApiKeyAuth
+// @Router /tca [get]
+func (c *tcaControllerImpl) GetTCAReportsHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	format := q.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		writeError(w, r, entity.Invalid("format", "unknown format %q", format))
+		return
+	}
+
+	filter, err := historyFilterQuery(q)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	req := entity.TCARequest{HistoryFilter: filter, GroupBy: q.Get("group_by")}
+
+	if err := req.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	reports, err := c.svc.GetTCAReports(&req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="tca_report.csv"`)
+		w.WriteHeader(http.StatusOK)
+		writeTCACSV(w, reports)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	bytes, _ := json.Marshal(reports)
+	w.Write(bytes)
+}
+
+func writeTCACSV(w http.ResponseWriter, reports []*entity.TCAReport) {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+	cw := csv.NewWriter(w)
+	cw.Write(tcaCSVHeader)
+	for _, r := range reports {
+		cw.Write([]string{
+			r.ParentID, r.ClientName, r.ExchangeName, r.Pair, string(r.Side),
+			strconv.Itoa(r.FillCount), f(r.Quantity),
+			f(r.BuyQty), f(r.SellQty), f(r.AvgBuyPrice), f(r.AvgSellPrice),
+			r.StartTime.Format(time.RFC3339Nano), r.EndTime.Format(time.RFC3339Nano), f(r.ArrivalPrice),
+			f(r.AvgFillPrice), f(r.IntervalVwap), f(r.IntervalTwap), f(r.Participation), f(r.ShortfallBps), f(r.VwapSlippageBps),
+			f(r.TwapSlippageBps), f(r.Fees), f(r.FeesBps), f(r.TotalCostBps),
+		})
+	}
+	cw.Flush()
+}
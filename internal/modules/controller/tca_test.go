@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetTCAReportsHandler(t *testing.T) {
+	mockService := &mocks.MockTCAService{}
+	controller := NewTCAController(mockService)
+
+	reports := []*entity.TCAReport{{
+		ParentID:     "parent1",
+		Side:         entity.SideBuy,
+		FillCount:    2,
+		Quantity:     2,
+		StartTime:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		EndTime:      time.Date(2024, 5, 1, 12, 1, 0, 0, time.UTC),
+		ShortfallBps: 12.5,
+	}}
+	mockService.On("GetTCAReports", mock.MatchedBy(func(r *entity.TCARequest) bool {
+		return r.GroupBy == entity.TCAGroupByLabel && len(r.Labels) == 1
+	})).Return(reports, nil)
+
+	req := httptest.NewRequest("GET", "/tca?label=parent1", nil)
+	rr := httptest.NewRecorder()
+
+	http.HandlerFunc(controller.GetTCAReportsHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	expectedBody, _ := json.Marshal(reports)
+	assert.Equal(t, expectedBody, rr.Body.Bytes())
+
+	req = httptest.NewRequest("GET", "/tca?label=parent1&format=csv", nil)
+	rr = httptest.NewRecorder()
+
+	http.HandlerFunc(controller.GetTCAReportsHandler).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "parent_id,client_name,"))
+	assert.Equal(t, "parent1,,,,buy,2,2,0,0,0,0,2024-05-01T12:00:00Z,2024-05-01T12:01:00Z,0,0,0,0,0,12.5,0,0,0,0,0", lines[1])
+}
+
+func TestGetTCAReportsHandler_BadRequest(t *testing.T) {
+	controller := NewTCAController(nil)
+
+	for _, url := range []string{
+		"/tca?group_by=client",
+		"/tca?format=xml",
+		"/tca?format=csv&from=yesterday",
+	} {
+		req := httptest.NewRequest("GET", url, nil)
+		rr := httptest.NewRecorder()
+
+		http.HandlerFunc(controller.GetTCAReportsHandler).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, url)
+	}
+}
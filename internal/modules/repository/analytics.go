@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"gorm.io/gorm"
+)
+
+type AnalyticsRepository interface {
+	GetHistoryOrders(filter *entity.HistoryFilter) ([]*entity.HistoryOrder, error)
+	GetMidsBetween(exchange_name, pair string, from, to time.Time) ([]*entity.BookMid, error)
+	GetMidsAsOf(exchange_name string, pairs []string, times []time.Time, maxAge time.Duration) ([]*entity.BookMid, error)
+	GetLastOrderBookTime(exchange_name, pair string) (time.Time, error)
+	GetTradeBars(req *entity.TradeBarRequest) ([]*entity.TradeBar, error)
+	GetIntervalBar(exchange_name, pair string, from, to time.Time) (*entity.TradeBar, error)
+}
+
+type analyticsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepositoryImpl{db: db}
+}
+
+/*
+GetHistoryOrders retrieves history orders matching the filter, oldest first.
+Empty filter fields are not applied.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *analyticsRepositoryImpl) GetHistoryOrders(filter *entity.HistoryFilter) ([]*entity.HistoryOrder, error) {
+	return findHistoryOrders(r.db, filter)
+}
+
+// findHistoryOrders runs the history order query shared by the repositories that filter fills.
+func findHistoryOrders(db *gorm.DB, filter *entity.HistoryFilter) ([]*entity.HistoryOrder, error) {
+	var orders []*entity.HistoryOrder
+	tx := filterHistoryOrders(db, filter).Order("time_placed").Find(&orders)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return orders, nil
+}
+
+// filterHistoryOrders applies the non-empty fields of filter to a query of history_orders.
+func filterHistoryOrders(db *gorm.DB, filter *entity.HistoryFilter) *gorm.DB {
+	tx := db.Table("history_orders FINAL")
+
+	if filter.ClientName != "" {
+		tx = tx.Where("client_name = ?", filter.ClientName)
+	}
+	if filter.ExchangeName != "" {
+		tx = tx.Where("exchange_name = ?", filter.ExchangeName)
+	}
+	if len(filter.Pairs) > 0 {
+		tx = tx.Where("pair IN ?", filter.Pairs)
+	}
+	if len(filter.Labels) > 0 {
+		tx = tx.Where("label IN ?", filter.Labels)
+	}
+	if len(filter.AlgorithmNames) > 0 {
+		tx = tx.Where("algorithm_name_placed IN ?", filter.AlgorithmNames)
+	}
+	if !filter.From.IsZero() {
+		tx = tx.Where("time_placed >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		tx = tx.Where("time_placed <= ?", filter.To)
+	}
+
+	return tx
+}
+
+/*
+GetMidsBetween retrieves the mids of the order book snapshots of an exchange and trading pair
+taken within [from, to], oldest first. Mids are computed by ClickHouse, so no depth is read.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *analyticsRepositoryImpl) GetMidsBetween(exchange_name, pair string, from, to time.Time) ([]*entity.BookMid, error) {
+	var mids []*entity.BookMid
+	tx := r.db.Table("order_book_dtos FINAL").
+		Select("timestamp AS at, exchange, pair, timestamp, "+bookMidColumn+" AS mid").
+		Where("exchange = ?", exchange_name).
+		Where("pair = ?", pair).
+		Where("timestamp BETWEEN ? AND ?", from, to).
+		Where(bookHasBothSides).
+		Order("timestamp, sequence").
+		Scan(&mids)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if len(mids) == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return mids, nil
+}
+
+/*
+GetMidsAsOf retrieves, for each of times, the mid of the latest order book snapshot of any of pairs taken
+at or before it and at most maxAge earlier. Mids are computed by ClickHouse, so no depth is read.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *analyticsRepositoryImpl) GetMidsAsOf(exchange_name string, pairs []string, times []time.Time, maxAge time.Duration) ([]*entity.BookMid, error) {
+	return findMidsAsOf(r.db, exchange_name, pairs, times, maxAge)
+}
+
+/*
+GetLastOrderBookTime retrieves the time of the latest order book snapshot of an exchange and trading pair,
+the zero time if there is none.
+If a database error occurs, it returns the error.
+*/
+
+func (r *analyticsRepositoryImpl) GetLastOrderBookTime(exchange_name, pair string) (time.Time, error) {
+	var last struct {
+		Timestamp time.Time
+	}
+	tx := r.db.Table("order_book_dtos").
+		Select("max(timestamp) AS timestamp").
+		Where("exchange = ?", exchange_name).
+		Where("pair = ?", pair).
+		Scan(&last)
+
+	if tx.Error != nil {
+		return time.Time{}, storageError(tx.Error)
+	}
+
+	return last.Timestamp, nil
+}
+
+// bookMidColumn computes the mid of a stored snapshot from its best ask and bid.
+const bookMidColumn = `(arrayMin(arrayMap(x -> JSONExtractFloat(x, 'price'), JSONExtractArrayRaw(asks))) +
+	arrayMax(arrayMap(x -> JSONExtractFloat(x, 'price'), JSONExtractArrayRaw(bids)))) / 2`
+
+// bookHasBothSides keeps the snapshots a mid can be taken from.
+const bookHasBothSides = "notEmpty(JSONExtractArrayRaw(asks)) AND notEmpty(JSONExtractArrayRaw(bids))"
+
+/*
+findMidsAsOf runs the as-of mid query shared by the repositories that price at given times, on an exchange
+or on any exchange if exchange_name is empty. An ASOF JOIN picks one snapshot per time, so the rows read back
+are bounded by the number of times rather than by the number of snapshots in the period.
+*/
+func findMidsAsOf(db *gorm.DB, exchange_name string, pairs []string, times []time.Time, maxAge time.Duration) ([]*entity.BookMid, error) {
+	if len(times) == 0 || len(pairs) == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	from, to := times[0], times[0]
+	for _, t := range times {
+		if t.Before(from) {
+			from = t
+		}
+		if t.After(to) {
+			to = t
+		}
+	}
+
+	books := db.Table("order_book_dtos FINAL").
+		Select("1 AS k, exchange, pair, timestamp, " + bookMidColumn + " AS mid")
+	if exchange_name != "" {
+		books = books.Where("exchange = ?", exchange_name)
+	}
+	books = books.Where("pair IN ?", pairs).
+		Where("timestamp BETWEEN ? AND ?", from.Add(-maxAge), to).
+		Where(bookHasBothSides)
+
+	// The times expand to a parenthesised list, which array turns into the array to join.
+	var mids []*entity.BookMid
+	tx := db.Raw(`SELECT t.at AS at, b.exchange AS exchange, b.pair AS pair, b.timestamp AS timestamp, b.mid AS mid
+		FROM (SELECT toDateTime64(arrayJoin(array?), 3) AS at, 1 AS k) AS t
+		ASOF JOIN (?) AS b ON t.k = b.k AND t.at >= b.timestamp
+		ORDER BY at`, times, books).
+		Scan(&mids)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	fresh := mids[:0]
+	for _, m := range mids {
+		if !m.Timestamp.Before(m.At.Add(-maxAge)) {
+			fresh = append(fresh, m)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return fresh, nil
+}
+
+/*
+GetTradeBars aggregates our own fills of an exchange and trading pair into OHLCV bars
+of the requested interval, oldest first.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *analyticsRepositoryImpl) GetTradeBars(req *entity.TradeBarRequest) ([]*entity.TradeBar, error) {
+	tx := r.db.Table("history_orders FINAL").
+		Select(`toStartOfInterval(time_placed, toIntervalSecond(?)) AS bucket_start,
+			argMin(price, time_placed) AS open,
+			max(price) AS high,
+			min(price) AS low,
+			argMax(price, time_placed) AS close,
+			sum(base_qty) AS volume,
+			sumIf(base_qty, side = 'buy') AS buy_volume,
+			sumIf(base_qty, side = 'sell') AS sell_volume,
+			count() AS trade_count,
+			sum(price * base_qty) AS notional`, req.IntervalSeconds).
+		Where("exchange_name = ?", req.ExchangeName).
+		Where("pair = ?", req.Pair)
+
+	if req.ClientName != "" {
+		tx = tx.Where("client_name = ?", req.ClientName)
+	}
+	if len(req.AlgorithmNames) > 0 {
+		tx = tx.Where("algorithm_name_placed IN ?", req.AlgorithmNames)
+	}
+	if !req.From.IsZero() {
+		tx = tx.Where("time_placed >= ?", req.From)
+	}
+	if !req.To.IsZero() {
+		tx = tx.Where("time_placed <= ?", req.To)
+	}
+
+	var bars []*entity.TradeBar
+	tx = tx.Group("bucket_start").
+		Order("bucket_start").
+		Scan(&bars)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if len(bars) == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return bars, nil
+}
+
+/*
+GetIntervalBar aggregates every fill of an exchange and trading pair placed within [from, to],
+whoever the client, into one bar holding their volume and notional.
+If a database error occurs, it returns the error.
+*/
+
+func (r *analyticsRepositoryImpl) GetIntervalBar(exchange_name, pair string, from, to time.Time) (*entity.TradeBar, error) {
+	bar := &entity.TradeBar{BucketStart: from}
+	tx := r.db.Table("history_orders FINAL").
+		Select("sum(base_qty) AS volume, count() AS trade_count, sum(price * base_qty) AS notional").
+		Where("exchange_name = ?", exchange_name).
+		Where("pair = ?", pair).
+		Where("time_placed BETWEEN ? AND ?", from, to).
+		Scan(bar)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	return bar, nil
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/gorm"
+)
+
+func TestGetHistoryOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewAnalyticsRepository(gormDB)
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	filter := &entity.HistoryFilter{
+		ExchangeName: "exchange1",
+		Pairs:        []string{"pair1", "pair2"},
+		From:         from,
+	}
+
+	mock.ExpectQuery("^SELECT \\* FROM history_orders FINAL WHERE exchange_name = \\? AND pair IN \\(\\?,\\?\\) AND time_placed >= \\? ORDER BY time_placed$").
+		WithArgs("exchange1", "pair1", "pair2", from).
+		WillReturnRows(sqlmock.NewRows([]string{"client_name", "exchange_name", "pair", "side", "base_qty", "price", "time_placed"}).
+			AddRow("client1", "exchange1", "pair1", "buy", 1.0, 100.0, from).
+			AddRow("client1", "exchange1", "pair2", "sell", 2.0, 50.0, from.Add(time.Minute)))
+
+	// Test case: valid data
+	orders, err := repo.GetHistoryOrders(filter)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 2)
+
+	// Test case: record not found
+	mock.ExpectQuery("^SELECT \\* FROM history_orders FINAL WHERE client_name = \\? ORDER BY time_placed$").
+		WithArgs("nonexistent_client").
+		WillReturnRows(sqlmock.NewRows([]string{"client_name"}))
+	orders, err = repo.GetHistoryOrders(&entity.HistoryFilter{ClientName: "nonexistent_client"})
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, orders)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestGetMidsBetween(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewAnalyticsRepository(gormDB)
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	mock.ExpectQuery("^SELECT timestamp AS at, exchange, pair, timestamp, (.+) AS mid FROM order_book_dtos FINAL WHERE exchange = \\? AND pair = \\? AND \\(timestamp BETWEEN \\? AND \\?\\) AND (.+) ORDER BY timestamp, sequence$").
+		WithArgs("exchange1", "pair1", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"at", "exchange", "pair", "timestamp", "mid"}).
+			AddRow(from, "exchange1", "pair1", from, 100.0))
+
+	// Test case: valid data
+	mids, err := repo.GetMidsBetween("exchange1", "pair1", from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.BookMid{{At: from, Exchange: "exchange1", Pair: "pair1", Timestamp: from, Mid: 100}}, mids)
+
+	// Test case: record not found
+	mock.ExpectQuery("^SELECT timestamp AS at, (.+) FROM order_book_dtos FINAL (.+)$").
+		WithArgs("exchange1", "pair2", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"at", "exchange", "pair", "timestamp", "mid"}))
+	mids, err = repo.GetMidsBetween("exchange1", "pair2", from, to)
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, mids)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestGetMidsAsOf(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewAnalyticsRepository(gormDB)
+
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	t1 := t0.Add(10 * time.Minute)
+
+	mock.ExpectQuery("^SELECT t.at AS at, (.+) FROM \\(SELECT toDateTime64\\(arrayJoin\\(array\\(\\?,\\?\\)\\), 3\\) AS at, 1 AS k\\) AS t\\s+"+
+		"ASOF JOIN \\(SELECT 1 AS k, exchange, pair, timestamp, (.+) AS mid FROM order_book_dtos FINAL WHERE exchange = \\? AND pair IN \\(\\?\\) AND \\(timestamp BETWEEN \\? AND \\?\\) AND (.+)\\) AS b ON t.k = b.k AND t.at >= b.timestamp\\s+ORDER BY at$").
+		WithArgs(t0, t1, "exchange1", "pair1", t0.Add(-time.Minute), t1).
+		WillReturnRows(sqlmock.NewRows([]string{"at", "exchange", "pair", "timestamp", "mid"}).
+			AddRow(t0, "exchange1", "pair1", t0.Add(-time.Second), 100.0).
+			AddRow(t1, "exchange1", "pair1", t0.Add(time.Second), 101.0))
+
+	// Test case: the mid as of t1 is older than maxAge
+	mids, err := repo.GetMidsAsOf("exchange1", []string{"pair1"}, []time.Time{t0, t1}, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.BookMid{{At: t0, Exchange: "exchange1", Pair: "pair1", Timestamp: t0.Add(-time.Second), Mid: 100}}, mids)
+
+	// Test case: nothing to look up
+	_, err = repo.GetMidsAsOf("exchange1", []string{"pair1"}, nil, time.Minute)
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestGetLastOrderBookTime(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewAnalyticsRepository(gormDB)
+
+	last := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("^SELECT max\\(timestamp\\) AS timestamp FROM `order_book_dtos` WHERE exchange = \\? AND pair = \\?$").
+		WithArgs("exchange1", "pair1").
+		WillReturnRows(sqlmock.NewRows([]string{"timestamp"}).AddRow(last))
+
+	at, err := repo.GetLastOrderBookTime("exchange1", "pair1")
+	assert.NoError(t, err)
+	assert.Equal(t, last, at)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestGetTradeBars(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewAnalyticsRepository(gormDB)
+
+	req := &entity.TradeBarRequest{ExchangeName: "exchange1", Pair: "pair1", ClientName: "client1", IntervalSeconds: 60}
+	bucket := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("^SELECT toStartOfInterval\\(time_placed, toIntervalSecond\\(\\?\\)\\) AS bucket_start,(.+) FROM history_orders FINAL WHERE exchange_name = \\? AND pair = \\? AND client_name = \\? GROUP BY `bucket_start` ORDER BY bucket_start$").
+		WithArgs(int64(60), "exchange1", "pair1", "client1").
+		WillReturnRows(sqlmock.NewRows([]string{"bucket_start", "open", "high", "low", "close", "volume", "buy_volume", "sell_volume", "trade_count", "notional"}).
+			AddRow(bucket, 100.0, 110.0, 90.0, 105.0, 4.0, 3.0, 1.0, 3, 410.0))
+
+	bars, err := repo.GetTradeBars(req)
+	assert.NoError(t, err)
+	assert.Len(t, bars, 1)
+	assert.Equal(t, bucket, bars[0].BucketStart)
+	assert.Equal(t, uint64(3), bars[0].TradeCount)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestGetIntervalBar(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewAnalyticsRepository(gormDB)
+
+	from := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	mock.ExpectQuery("^SELECT sum\\(base_qty\\) AS volume, count\\(\\) AS trade_count, sum\\(price \\* base_qty\\) AS notional FROM history_orders FINAL WHERE exchange_name = \\? AND pair = \\? AND \\(time_placed BETWEEN \\? AND \\?\\)$").
+		WithArgs("exchange1", "pair1", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"volume", "trade_count", "notional"}).AddRow(4.0, 3, 410.0))
+
+	bar, err := repo.GetIntervalBar("exchange1", "pair1", from, to)
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.TradeBar{BucketStart: from, Volume: 4, TradeCount: 3, Notional: 410}, bar)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	GetAPIKeys() ([]*entity.APIKey, error)
+	GetAPIKey(id string) (*entity.APIKey, error)
+	SaveAPIKey(key entity.APIKey) error
+	RevokeAPIKey(id string) error
+}
+
+type apiKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepositoryImpl{db: db}
+}
+
+/*
+GetAPIKeys retrieves every key that is not revoked.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *apiKeyRepositoryImpl) GetAPIKeys() ([]*entity.APIKey, error) {
+	var keys []*entity.APIKey
+	tx := r.db.Table("api_keys FINAL").Where("revoked = ?", false).Order("created_at").Find(&keys)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return keys, nil
+}
+
+/*
+GetAPIKey retrieves a single key that is not revoked.
+If no record is found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *apiKeyRepositoryImpl) GetAPIKey(id string) (*entity.APIKey, error) {
+	var keys []*entity.APIKey
+	tx := r.db.Table("api_keys FINAL").
+		Where("id = ?", id).
+		Where("revoked = ?", false).
+		Find(&keys)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return keys[0], nil
+}
+
+/*
+SaveAPIKey inserts a new version of a key, replacing any earlier one.
+If the save operation fails, it returns the error.
+*/
+
+func (r *apiKeyRepositoryImpl) SaveAPIKey(key entity.APIKey) error {
+	key.UpdatedAt = time.Now()
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = key.UpdatedAt
+	}
+
+	tx := r.db.Create(&key)
+	if tx.Error != nil {
+		return storageError(tx.Error)
+	}
+
+	return nil
+}
+
+/*
+RevokeAPIKey inserts a revoked version of a key so reads no longer return it.
+If the save operation fails, it returns the error.
+*/
+
+func (r *apiKeyRepositoryImpl) RevokeAPIKey(id string) error {
+	return r.SaveAPIKey(entity.APIKey{ID: id, Revoked: true})
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/gorm"
+)
+
+func TestGetAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewAPIKeyRepository(gormDB)
+
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("^SELECT \\* FROM api_keys FINAL WHERE id = \\? AND revoked = \\?$").
+		WithArgs("key1", false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "secret_hash", "scopes", "client_names", "expires_at", "created_at", "updated_at", "revoked"}).
+			AddRow("key1", "desk1", "hash", `["history:read"]`, `["client1"]`, nil, created, created, false))
+
+	// Test case: valid data
+	key, err := repo.GetAPIKey("key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Scope{entity.ScopeReadHistory}, key.Scopes)
+	assert.Equal(t, []string{"client1"}, key.ClientNames)
+	assert.Nil(t, key.ExpiresAt)
+
+	// Test case: record not found
+	mock.ExpectQuery("^SELECT \\* FROM api_keys FINAL WHERE (.+)$").
+		WithArgs("key2", false).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	key, err = repo.GetAPIKey("key2")
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, key)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
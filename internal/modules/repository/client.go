@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ClientRepository interface {
+	GetClientAccounts(clientName string) ([]*entity.ClientAccount, error)
+	GetClientAccount(clientName, exchangeName, label string) (*entity.ClientAccount, error)
+	SaveClientAccount(account entity.ClientAccount) error
+	DeleteClientAccount(clientName, exchangeName, label string) error
+}
+
+type clientRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewClientRepository(db *gorm.DB) ClientRepository {
+	return &clientRepositoryImpl{db: db}
+}
+
+/*
+GetClientAccounts retrieves the registered accounts of a client, or of every client if clientName is empty.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *clientRepositoryImpl) GetClientAccounts(clientName string) ([]*entity.ClientAccount, error) {
+	tx := r.db.Table("client_accounts FINAL").Where("deleted = ?", false)
+	if clientName != "" {
+		tx = tx.Where("client_name = ?", clientName)
+	}
+
+	var accounts []*entity.ClientAccount
+	tx = tx.Order("client_name, exchange_name, label").Find(&accounts)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return accounts, nil
+}
+
+/*
+GetClientAccount retrieves a single registered account.
+If no record is found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *clientRepositoryImpl) GetClientAccount(clientName, exchangeName, label string) (*entity.ClientAccount, error) {
+	var accounts []*entity.ClientAccount
+	tx := r.db.Table("client_accounts FINAL").
+		Where("client_name = ?", clientName).
+		Where("exchange_name = ?", exchangeName).
+		Where("label = ?", label).
+		Where("deleted = ?", false).
+		Find(&accounts)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return accounts[0], nil
+}
+
+/*
+SaveClientAccount inserts a new version of an account, replacing any earlier one.
+If the save operation fails, it returns the error.
+*/
+
+func (r *clientRepositoryImpl) SaveClientAccount(account entity.ClientAccount) error {
+	account.UpdatedAt = time.Now()
+	if account.CreatedAt.IsZero() {
+		account.CreatedAt = account.UpdatedAt
+	}
+
+	tx := r.db.Create(&account)
+	if tx.Error != nil {
+		return storageError(tx.Error)
+	}
+
+	return nil
+}
+
+/*
+DeleteClientAccount inserts a deleted version of an account so reads no longer return it.
+If the save operation fails, it returns the error.
+*/
+
+func (r *clientRepositoryImpl) DeleteClientAccount(clientName, exchangeName, label string) error {
+	return r.SaveClientAccount(entity.ClientAccount{
+		ClientName:   clientName,
+		ExchangeName: exchangeName,
+		Label:        label,
+		Deleted:      true,
+	})
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/egorque1/vortex-test/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/gorm"
+)
+
+func TestGetClientAccount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewClientRepository(gormDB)
+
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("^SELECT \\* FROM client_accounts FINAL WHERE client_name = \\? AND exchange_name = \\? AND label = \\? AND deleted = \\?$").
+		WithArgs("client1", "exchange1", "label1", false).
+		WillReturnRows(sqlmock.NewRows([]string{"client_name", "exchange_name", "label", "pairs", "created_at", "updated_at", "deleted"}).
+			AddRow("client1", "exchange1", "label1", `["pair1","pair2"]`, created, created, false))
+
+	// Test case: valid data
+	account, err := repo.GetClientAccount("client1", "exchange1", "label1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pair1", "pair2"}, account.Pairs)
+
+	// Test case: record not found
+	mock.ExpectQuery("^SELECT \\* FROM client_accounts FINAL WHERE (.+)$").
+		WithArgs("client2", "exchange1", "label1", false).
+		WillReturnRows(sqlmock.NewRows([]string{"client_name"}))
+	account, err = repo.GetClientAccount("client2", "exchange1", "label1")
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, account)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ConversionRepository interface {
+	GetMidsAsOf(exchange_name string, pairs []string, times []time.Time, maxAge time.Duration) ([]*entity.BookMid, error)
+}
+
+type conversionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewConversionRepository(db *gorm.DB) ConversionRepository {
+	return &conversionRepositoryImpl{db: db}
+}
+
+/*
+GetMidsAsOf retrieves, for each of times, the mid of the latest order book snapshot of any of pairs taken
+at or before it and at most maxAge earlier, on an exchange or on any exchange if exchange_name is empty.
+Pairs are matched as they are stored.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *conversionRepositoryImpl) GetMidsAsOf(exchange_name string, pairs []string, times []time.Time, maxAge time.Duration) ([]*entity.BookMid, error) {
+	return findMidsAsOf(r.db, exchange_name, pairs, times, maxAge)
+}
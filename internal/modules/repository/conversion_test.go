@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/egorque1/vortex-test/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/gorm"
+)
+
+func TestGetMidsAsOfPairs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewConversionRepository(gormDB)
+
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("^SELECT t.at AS at, (.+) FROM order_book_dtos FINAL WHERE exchange = \\? AND pair IN \\(\\?,\\?\\) AND (.+)$").
+		WithArgs(at, "exchange1", "BNB/USDT", "USDT/BNB", at.Add(-24*time.Hour), at).
+		WillReturnRows(sqlmock.NewRows([]string{"at", "exchange", "pair", "timestamp", "mid"}).
+			AddRow(at, "exchange1", "USDT/BNB", at.Add(-time.Hour), 0.002))
+
+	// Test case: valid data
+	mids, err := repo.GetMidsAsOf("exchange1", []string{"BNB/USDT", "USDT/BNB"}, []time.Time{at}, 24*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.BookMid{{At: at, Exchange: "exchange1", Pair: "USDT/BNB", Timestamp: at.Add(-time.Hour), Mid: 0.002}}, mids)
+
+	// Test case: record not found on any exchange
+	mock.ExpectQuery("^SELECT t.at AS at, (.+) FROM order_book_dtos FINAL WHERE pair IN \\(\\?\\) AND (.+)$").
+		WithArgs(at, "XYZ/USDT", at.Add(-24*time.Hour), at).
+		WillReturnRows(sqlmock.NewRows([]string{"at", "exchange", "pair", "timestamp", "mid"}))
+	mids, err = repo.GetMidsAsOf("", []string{"XYZ/USDT"}, []time.Time{at}, 24*time.Hour)
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, mids)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"syscall"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"gorm.io/gorm"
+)
+
+// ClickHouse exception codes of failures the caller may retry.
+const (
+	chTimeoutExceeded         = 159
+	chTooManySimultaneousRuns = 202
+	chSocketTimeout           = 209
+	chNetworkError            = 210
+)
+
+/*
+storageError turns a database error into a domain error: a missing record into entity.ErrNotFound,
+and failures to reach ClickHouse or to answer in time into entity.ErrUnavailable and entity.ErrTimeout.
+The original error stays wrapped. Other errors are returned unchanged.
+*/
+func storageError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.ErrNotFound
+	}
+
+	var exception *clickhouse.Exception
+	if errors.As(err, &exception) {
+		switch exception.Code {
+		case chTimeoutExceeded, chSocketTimeout:
+			return &entity.Error{Kind: entity.ErrTimeout, Code: "timeout", Message: "storage timed out", Err: err}
+		case chTooManySimultaneousRuns, chNetworkError:
+			return &entity.Error{Kind: entity.ErrUnavailable, Code: "storage_unavailable", Message: "storage unavailable", Err: err}
+		}
+		return err
+	}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return &entity.Error{Kind: entity.ErrTimeout, Code: "timeout", Message: "storage timed out", Err: err}
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET), errors.As(err, &netErr):
+		return &entity.Error{Kind: entity.ErrUnavailable, Code: "storage_unavailable", Message: "storage unavailable", Err: err}
+	}
+
+	return err
+}
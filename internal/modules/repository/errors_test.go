@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestStorageError(t *testing.T) {
+	assert.Nil(t, storageError(nil))
+	assert.Equal(t, entity.ErrNotFound, storageError(gorm.ErrRecordNotFound))
+
+	unavailable := storageError(fmt.Errorf("query: %w", driver.ErrBadConn))
+	assert.ErrorIs(t, unavailable, entity.ErrUnavailable)
+	assert.ErrorIs(t, unavailable, driver.ErrBadConn)
+
+	assert.ErrorIs(t, storageError(context.DeadlineExceeded), entity.ErrTimeout)
+	assert.ErrorIs(t, storageError(&clickhouse.Exception{Code: chTimeoutExceeded}), entity.ErrTimeout)
+	assert.ErrorIs(t, storageError(&clickhouse.Exception{Code: chNetworkError}), entity.ErrUnavailable)
+
+	other := errors.New("syntax error")
+	assert.Equal(t, other, storageError(other))
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ExportRepository interface {
+	StreamOrderBook(exchange_name, pair string, fn func(*entity.OrderBook) error) error
+	StreamOrderHistory(filter *entity.HistoryFilter, fn func(*entity.HistoryOrder) error) error
+}
+
+type exportRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewExportRepository(db *gorm.DB) ExportRepository {
+	return &exportRepositoryImpl{db: db}
+}
+
+/*
+StreamOrderBook calls fn for every order book snapshot of an exchange and trading pair, oldest first.
+Rows are read one at a time so the result is never held in memory as a whole.
+If no records are found, it returns a "record not found" error.
+If a database error occurs or fn fails, it returns the error.
+*/
+
+func (r *exportRepositoryImpl) StreamOrderBook(exchange_name, pair string, fn func(*entity.OrderBook) error) error {
+	tx := r.db.Table("order_book_dtos FINAL").
+		Where("exchange = ?", exchange_name).
+		Where("pair = ?", pair).
+		Order("timestamp, sequence")
+
+	return streamRows(tx, func(dto *entity.OrderBookDTO) error {
+		orderBook, err := entity.ToOrderBookEntity(dto)
+		if err != nil {
+			return fmt.Errorf("error converting to Entity: %w", err)
+		}
+		return fn(orderBook)
+	})
+}
+
+/*
+StreamOrderHistory calls fn for every history order matching the filter, oldest first.
+Empty filter fields are not applied and rows are read one at a time.
+If no records are found, it returns a "record not found" error.
+If a database error occurs or fn fails, it returns the error.
+*/
+
+func (r *exportRepositoryImpl) StreamOrderHistory(filter *entity.HistoryFilter, fn func(*entity.HistoryOrder) error) error {
+	return streamRows(filterHistoryOrders(r.db, filter).Order("time_placed"), fn)
+}
+
+func streamRows[T any](tx *gorm.DB, fn func(*T) error) error {
+	rows, err := tx.Rows()
+	if err != nil {
+		return storageError(err)
+	}
+	defer rows.Close()
+
+	found := false
+	for rows.Next() {
+		var row T
+		if err := tx.ScanRows(rows, &row); err != nil {
+			return storageError(err)
+		}
+		found = true
+
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return storageError(err)
+	}
+
+	if !found {
+		return entity.ErrNotFound
+	}
+
+	return nil
+}
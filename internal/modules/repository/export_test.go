@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/gorm"
+)
+
+func TestStreamOrderBook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewExportRepository(gormDB)
+
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("^SELECT \\* FROM order_book_dtos FINAL WHERE exchange = \\? AND pair = \\? ORDER BY timestamp, sequence$").
+		WithArgs("exchange1", "pair1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "exchange", "pair", "asks", "bids", "timestamp", "sequence", "dedup_key"}).
+			AddRow(1, "exchange1", "pair1", `[{"price":101,"base_qty":1}]`, `[]`, ts, 1, "").
+			AddRow(2, "exchange1", "pair1", `[]`, `[{"price":99,"base_qty":2}]`, ts.Add(time.Second), 2, ""))
+
+	// Test case: valid data
+	var books []*entity.OrderBook
+	err = repo.StreamOrderBook("exchange1", "pair1", func(ob *entity.OrderBook) error {
+		books = append(books, ob)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, books, 2)
+	assert.Equal(t, 101.0, books[0].Asks[0].Price)
+	assert.Equal(t, int64(2), books[1].Sequence)
+
+	// Test case: record not found
+	mock.ExpectQuery("^SELECT \\* FROM order_book_dtos FINAL WHERE (.+)$").
+		WithArgs("exchange1", "pair2").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	err = repo.StreamOrderBook("exchange1", "pair2", func(ob *entity.OrderBook) error {
+		t.Fatal("unexpected row")
+		return nil
+	})
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"github.com/egorque1/vortex-test/internal/entity"
+	"gorm.io/gorm"
+)
+
+type FeeRepository interface {
+	GetFeeSchedules(exchangeName string) ([]*entity.FeeSchedule, error)
+	SaveFeeSchedule(schedule entity.FeeSchedule) error
+}
+
+type feeRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewFeeRepository(db *gorm.DB) FeeRepository {
+	return &feeRepositoryImpl{db: db}
+}
+
+/*
+GetFeeSchedules retrieves the fee schedules of an exchange, or of every exchange if exchangeName is empty,
+ordered by exchange, client and the time they take effect.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *feeRepositoryImpl) GetFeeSchedules(exchangeName string) ([]*entity.FeeSchedule, error) {
+	tx := r.db.Table("fee_schedules FINAL")
+	if exchangeName != "" {
+		tx = tx.Where("exchange_name = ?", exchangeName)
+	}
+
+	var schedules []*entity.FeeSchedule
+	tx = tx.Order("exchange_name, client_name, effective_from").Find(&schedules)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return schedules, nil
+}
+
+/*
+SaveFeeSchedule saves a fee schedule, replacing one of the same exchange and client taking effect at the same time.
+If the save operation fails, it returns the error.
+*/
+
+func (r *feeRepositoryImpl) SaveFeeSchedule(schedule entity.FeeSchedule) error {
+	return storageError(r.db.Table("fee_schedules").Create(&schedule).Error)
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/gorm"
+)
+
+func TestGetFeeSchedules(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewFeeRepository(gormDB)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("^SELECT \\* FROM fee_schedules FINAL WHERE exchange_name = \\? ORDER BY exchange_name, client_name, effective_from$").
+		WithArgs("exchange1").
+		WillReturnRows(sqlmock.NewRows([]string{"exchange_name", "client_name", "effective_from", "volume_window_days", "tiers", "updated_at"}).
+			AddRow("exchange1", "", from, 30, `[{"min_volume":0,"maker_bps":2,"taker_bps":5}]`, from))
+
+	// Test case: valid data
+	schedules, err := repo.GetFeeSchedules("exchange1")
+	assert.NoError(t, err)
+	assert.Len(t, schedules, 1)
+	assert.Equal(t, []entity.FeeTier{{MinVolume: 0, MakerBps: 2, TakerBps: 5}}, schedules[0].Tiers)
+
+	// Test case: record not found
+	mock.ExpectQuery("^SELECT \\* FROM fee_schedules FINAL WHERE (.+)$").
+		WithArgs("exchange2").
+		WillReturnRows(sqlmock.NewRows([]string{"exchange_name"}))
+	schedules, err = repo.GetFeeSchedules("exchange2")
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, schedules)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
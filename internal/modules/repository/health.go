@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"gorm.io/gorm"
+)
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	GetTables(ctx context.Context) ([]entity.TableStatus, error)
+	GetColumns(ctx context.Context) ([]entity.Column, error)
+}
+
+type healthRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewHealthRepository(db *gorm.DB) HealthRepository {
+	return &healthRepositoryImpl{db: db}
+}
+
+/*
+Ping checks that the database can be reached.
+If the connection fails or ctx is done first, it returns the error.
+*/
+
+func (r *healthRepositoryImpl) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return storageError(err)
+	}
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return storageError(err)
+	}
+
+	return nil
+}
+
+/*
+GetTables retrieves the tables and views of the database with their row counts, zero for views.
+If a database error occurs, it returns the error.
+*/
+
+func (r *healthRepositoryImpl) GetTables(ctx context.Context) ([]entity.TableStatus, error) {
+	var tables []entity.TableStatus
+	tx := r.db.WithContext(ctx).
+		Raw("SELECT name, coalesce(total_rows, 0) AS rows FROM system.tables WHERE database = currentDatabase() ORDER BY name").
+		Scan(&tables)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	return tables, nil
+}
+
+/*
+GetColumns retrieves the columns of every table of the database with their types.
+If a database error occurs, it returns the error.
+*/
+
+func (r *healthRepositoryImpl) GetColumns(ctx context.Context) ([]entity.Column, error) {
+	var columns []entity.Column
+	tx := r.db.WithContext(ctx).
+		Raw("SELECT table, name, type FROM system.columns WHERE database = currentDatabase() ORDER BY table, position").
+		Scan(&columns)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	return columns, nil
+}
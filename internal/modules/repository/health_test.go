@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/gorm"
+)
+
+func TestGetTables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewHealthRepository(gormDB)
+
+	mock.ExpectQuery("^SELECT name, coalesce\\(total_rows, 0\\) AS rows FROM system.tables WHERE database = currentDatabase\\(\\) ORDER BY name$").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "rows"}).
+			AddRow("history_orders", 3).
+			AddRow("order_book_dtos", 5))
+
+	// Test case: valid data
+	tables, err := repo.GetTables(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.TableStatus{{Name: "history_orders", Rows: 3}, {Name: "order_book_dtos", Rows: 5}}, tables)
+
+	// Test case: database error
+	mock.ExpectQuery("^SELECT name, (.+) FROM system.tables (.+)$").WillReturnError(errors.New("connection refused"))
+	_, err = repo.GetTables(context.Background())
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetColumns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewHealthRepository(gormDB)
+
+	mock.ExpectQuery("^SELECT table, name, type FROM system.columns WHERE database = currentDatabase\\(\\) ORDER BY table, position$").
+		WillReturnRows(sqlmock.NewRows([]string{"table", "name", "type"}).
+			AddRow("history_orders", "side", "Enum8('buy' = 1, 'sell' = 2)").
+			AddRow("history_orders", "commission_asset", "LowCardinality(String)"))
+
+	// Test case: valid data
+	columns, err := repo.GetColumns(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Column{
+		{Table: "history_orders", Name: "side", Type: "Enum8('buy' = 1, 'sell' = 2)"},
+		{Table: "history_orders", Name: "commission_asset", Type: "LowCardinality(String)"},
+	}, columns)
+
+	// Test case: database error
+	mock.ExpectQuery("^SELECT table, (.+) FROM system.columns (.+)$").WillReturnError(errors.New("connection refused"))
+	_, err = repo.GetColumns(context.Background())
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"github.com/egorque1/vortex-test/internal/entity"
+	"gorm.io/gorm"
+)
+
+type OrderEventRepository interface {
+	GetOrderEvents(orderID string) ([]*entity.OrderEvent, error)
+	SaveOrderEvent(event entity.OrderEvent) error
+	GetOrderFills(orderID string) ([]*entity.HistoryOrder, error)
+}
+
+type orderEventRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOrderEventRepository(db *gorm.DB) OrderEventRepository {
+	return &orderEventRepositoryImpl{db: db}
+}
+
+/*
+GetOrderEvents retrieves the lifecycle events of an order, oldest first.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *orderEventRepositoryImpl) GetOrderEvents(orderID string) ([]*entity.OrderEvent, error) {
+	var events []*entity.OrderEvent
+	tx := r.db.Where("order_id = ?", orderID).
+		Order("event_time").
+		Find(&events)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return events, nil
+}
+
+/*
+SaveOrderEvent saves an order lifecycle event to the database.
+If the save operation fails, it returns the error.
+*/
+
+func (r *orderEventRepositoryImpl) SaveOrderEvent(event entity.OrderEvent) error {
+	tx := r.db.Create(&event)
+	if tx.Error != nil {
+		return storageError(tx.Error)
+	}
+
+	return nil
+}
+
+/*
+GetOrderFills retrieves the history orders recorded for an order, oldest first.
+An order without fills returns an empty slice.
+If a database error occurs, it returns the error.
+*/
+
+func (r *orderEventRepositoryImpl) GetOrderFills(orderID string) ([]*entity.HistoryOrder, error) {
+	var fills []*entity.HistoryOrder
+	tx := r.db.Table("history_orders FINAL").
+		Where("order_id = ?", orderID).
+		Order("time_placed").
+		Find(&fills)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	return fills, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/gorm"
+)
+
+func TestGetOrderEvents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewOrderEventRepository(gormDB)
+
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("^SELECT \\* FROM `order_events` WHERE order_id = \\? ORDER BY event_time$").
+		WithArgs("order1").
+		WillReturnRows(sqlmock.NewRows([]string{"order_id", "status", "filled_qty", "event_time"}).
+			AddRow("order1", "new", 0.0, t0).
+			AddRow("order1", "partially_filled", 0.5, t0.Add(time.Second)))
+
+	// Test case: events oldest first
+	events, err := repo.GetOrderEvents("order1")
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, entity.OrderStatusNew, events[0].Status)
+	assert.Equal(t, 0.5, events[1].FilledQty)
+
+	// Test case: record not found
+	mock.ExpectQuery("^SELECT \\* FROM `order_events` WHERE (.+)$").
+		WithArgs("order2").
+		WillReturnRows(sqlmock.NewRows([]string{"order_id"}))
+	events, err = repo.GetOrderEvents("order2")
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, events)
+
+	// Test case: database error
+	mock.ExpectQuery("^SELECT \\* FROM `order_events` WHERE (.+)$").
+		WithArgs("order3").
+		WillReturnError(errors.New("connection refused"))
+	_, err = repo.GetOrderEvents("order3")
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveOrderEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewOrderEventRepository(gormDB)
+
+	event := entity.OrderEvent{OrderID: "order1", Status: entity.OrderStatusNew, EventTime: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("^INSERT INTO `order_events` (.+) VALUES (.+)$").
+		ExpectExec().
+		WithArgs("order1", "", "", "", "", entity.OrderStatusNew, 0.0, "", event.EventTime).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Test case: saved
+	err = repo.SaveOrderEvent(event)
+	assert.NoError(t, err)
+
+	// Test case: database error
+	mock.ExpectBegin()
+	mock.ExpectPrepare("^INSERT INTO `order_events` (.+) VALUES (.+)$").
+		ExpectExec().
+		WillReturnError(errors.New("connection refused"))
+	mock.ExpectRollback()
+
+	err = repo.SaveOrderEvent(event)
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOrderFills(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewOrderEventRepository(gormDB)
+
+	mock.ExpectQuery("^SELECT \\* FROM history_orders FINAL WHERE order_id = \\? ORDER BY time_placed$").
+		WithArgs("order1").
+		WillReturnRows(sqlmock.NewRows([]string{"order_id", "base_qty", "price"}).
+			AddRow("order1", 0.5, 100.0))
+
+	// Test case: fills of the order
+	fills, err := repo.GetOrderFills("order1")
+	assert.NoError(t, err)
+	assert.Len(t, fills, 1)
+	assert.Equal(t, 0.5, fills[0].BaseQty)
+
+	// Test case: an order without fills
+	mock.ExpectQuery("^SELECT \\* FROM history_orders FINAL WHERE (.+)$").
+		WithArgs("order2").
+		WillReturnRows(sqlmock.NewRows([]string{"order_id"}))
+	fills, err = repo.GetOrderFills("order2")
+	assert.NoError(t, err)
+	assert.Empty(t, fills)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
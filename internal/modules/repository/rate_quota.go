@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"gorm.io/gorm"
+)
+
+type RateQuotaRepository interface {
+	GetRateQuotas() ([]*entity.RateQuota, error)
+	SaveRateQuota(quota entity.RateQuota) error
+	DeleteRateQuota(keyID, route string) error
+}
+
+type rateQuotaRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRateQuotaRepository(db *gorm.DB) RateQuotaRepository {
+	return &rateQuotaRepositoryImpl{db: db}
+}
+
+/*
+GetRateQuotas retrieves every stored quota that is not deleted.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *rateQuotaRepositoryImpl) GetRateQuotas() ([]*entity.RateQuota, error) {
+	var quotas []*entity.RateQuota
+	tx := r.db.Table("rate_quotas FINAL").Where("deleted = ?", false).Order("key_id, route").Find(&quotas)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return quotas, nil
+}
+
+/*
+SaveRateQuota inserts a new version of a quota, replacing any earlier one for the same key and route.
+If the save operation fails, it returns the error.
+*/
+
+func (r *rateQuotaRepositoryImpl) SaveRateQuota(quota entity.RateQuota) error {
+	quota.UpdatedAt = time.Now()
+
+	tx := r.db.Create(&quota)
+	if tx.Error != nil {
+		return storageError(tx.Error)
+	}
+
+	return nil
+}
+
+/*
+DeleteRateQuota inserts a deleted version of a quota so reads no longer return it.
+If the save operation fails, it returns the error.
+*/
+
+func (r *rateQuotaRepositoryImpl) DeleteRateQuota(keyID, route string) error {
+	return r.SaveRateQuota(entity.RateQuota{KeyID: keyID, Route: route, Deleted: true})
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/gorm"
+)
+
+func TestGetRateQuotas(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewRateQuotaRepository(gormDB)
+
+	updated := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("^SELECT \\* FROM rate_quotas FINAL WHERE deleted = \\? ORDER BY key_id, route$").
+		WithArgs(false).
+		WillReturnRows(sqlmock.NewRows([]string{"key_id", "route", "rate", "burst", "updated_at", "deleted"}).
+			AddRow("", "read", 50.0, 100, updated, false).
+			AddRow("key1", "GET /v1/history/{clientName}", 5.0, 10, updated, false))
+
+	// Test case: valid data
+	quotas, err := repo.GetRateQuotas()
+	assert.NoError(t, err)
+	assert.Len(t, quotas, 2)
+	assert.Equal(t, entity.RateQuota{KeyID: "key1", Route: "GET /v1/history/{clientName}", Rate: 5, Burst: 10, UpdatedAt: updated}, *quotas[1])
+
+	// Test case: record not found
+	mock.ExpectQuery("^SELECT \\* FROM rate_quotas FINAL WHERE (.+)$").
+		WithArgs(false).
+		WillReturnRows(sqlmock.NewRows([]string{"key_id"}))
+	quotas, err = repo.GetRateQuotas()
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, quotas)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ReportRepository interface {
+	GetCommissionReport(req *entity.CommissionReportRequest) ([]*entity.CommissionReport, error)
+}
+
+type reportRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepositoryImpl{db: db}
+}
+
+/*
+GetCommissionReport aggregates commissions from the commission_daily table.
+Rows are bucketed by day or month and grouped by the requested dimensions.
+Rows of the same day and dimensions not yet summed by a merge are summed by the query.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *reportRepositoryImpl) GetCommissionReport(req *entity.CommissionReportRequest) ([]*entity.CommissionReport, error) {
+	period := "day"
+	if req.Period == entity.ReportPeriodMonth {
+		period = "toStartOfMonth(day)"
+	}
+
+	columns := append([]string{period + " AS period"}, req.GroupBy...)
+	groups := append([]string{"period"}, req.GroupBy...)
+
+	tx := r.db.Table("commission_daily").
+		Select(strings.Join(columns, ", ") + ", sum(order_count) AS order_count, sum(notional) AS notional, sum(commission) AS commission")
+
+	if req.ClientName != "" {
+		tx = tx.Where("client_name = ?", req.ClientName)
+	}
+	if req.ExchangeName != "" {
+		tx = tx.Where("exchange_name = ?", req.ExchangeName)
+	}
+	if req.Pair != "" {
+		tx = tx.Where("pair = ?", req.Pair)
+	}
+	if req.AlgorithmNamePlaced != "" {
+		tx = tx.Where("algorithm_name_placed = ?", req.AlgorithmNamePlaced)
+	}
+	if !req.From.IsZero() {
+		tx = tx.Where("day >= toDate(?)", req.From)
+	}
+	if !req.To.IsZero() {
+		tx = tx.Where("day <= toDate(?)", req.To)
+	}
+
+	var report []*entity.CommissionReport
+	tx = tx.Group(strings.Join(groups, ", ")).
+		Order(strings.Join(groups, ", ")).
+		Scan(&report)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if len(report) == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return report, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/gorm"
+)
+
+func TestGetCommissionReport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewReportRepository(gormDB)
+
+	req := &entity.CommissionReportRequest{
+		ClientName: "client1",
+		Period:     entity.ReportPeriodMonth,
+		GroupBy:    []string{"pair"},
+	}
+
+	month := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("^SELECT toStartOfMonth\\(day\\) AS period, pair, sum\\(order_count\\) AS order_count, sum\\(notional\\) AS notional, sum\\(commission\\) AS commission FROM `commission_daily` WHERE client_name = \\? GROUP BY period, pair ORDER BY period, pair$").
+		WithArgs("client1").
+		WillReturnRows(sqlmock.NewRows([]string{"period", "pair", "order_count", "notional", "commission"}).
+			AddRow(month, "pair1", 3, 1500.0, 1.5))
+
+	// Test case: valid data
+	report, err := repo.GetCommissionReport(req)
+	assert.NoError(t, err)
+	assert.Len(t, report, 1)
+	assert.Equal(t, "pair1", report[0].Pair)
+	assert.Equal(t, uint64(3), report[0].OrderCount)
+
+	// Test case: no rows
+	mock.ExpectQuery("^SELECT (.+) FROM `commission_daily`").
+		WithArgs("client1").
+		WillReturnRows(sqlmock.NewRows([]string{"period", "pair", "order_count", "notional", "commission"}))
+	report, err = repo.GetCommissionReport(req)
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, report)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -1,17 +1,28 @@
 package repository
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 
+	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/egorque1/vortex-test/internal/entity"
 	"gorm.io/gorm"
 )
 
 type OrderRepository interface {
 	GetOrderBook(exchange_name, pair string) ([]*entity.OrderBook, error)
+	GetLatestOrderBook(exchange_name, pair string) (*entity.OrderBook, error)
 	SaveOrderBook(orderBook []*entity.OrderBook) error
 	GetOrderHistory(client *entity.Client) ([]*entity.HistoryOrder, error)
+	FindOrderHistory(filter *entity.HistoryFilter) ([]*entity.HistoryOrder, error)
 	SaveOrderHistory(order entity.HistoryOrder) error
+	SaveOrderBookBatch(orderBook []*entity.OrderBook) error
+	SaveOrderHistoryBatch(orders []*entity.HistoryOrder) error
+	GetExistingOrderBookKeys(keys []string) ([]string, error)
+	GetExistingHistoryOrderKeys(keys []string) ([]string, error)
 }
 
 type orderRepositoryImpl struct {
@@ -31,16 +42,17 @@ If a database error occurs, it returns the error.
 
 func (r *orderRepositoryImpl) GetOrderBook(exchange_name, pair string) ([]*entity.OrderBook, error) {
 	var orderBookDTOs []*entity.OrderBookDTO
-	tx := r.db.Where("exchange = ?", exchange_name).
+	tx := r.db.Table("order_book_dtos FINAL").
+		Where("exchange = ?", exchange_name).
 		Where("pair = ?", pair).
 		Find(&orderBookDTOs)
 
-	if tx.RowsAffected == 0 {
-		return nil, gorm.ErrRecordNotFound
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
 	}
 
-	if tx.Error != nil {
-		return nil, tx.Error
+	if tx.RowsAffected == 0 {
+		return nil, entity.ErrNotFound
 	}
 
 	var orderBooks []*entity.OrderBook
@@ -55,6 +67,48 @@ func (r *orderRepositoryImpl) GetOrderBook(exchange_name, pair string) ([]*entit
 	return orderBooks, nil
 }
 
+/*
+GetLatestOrderBook retrieves the latest order book snapshot of an exchange and trading pair.
+Pairs are matched regardless of case and separator.
+If no record is found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *orderRepositoryImpl) GetLatestOrderBook(exchange_name, pair string) (*entity.OrderBook, error) {
+	return latestOrderBook(r.db, exchange_name, pair)
+}
+
+// latestOrderBook reads the latest order book snapshot of an exchange and pair, stored in any spelling of the pair.
+func latestOrderBook(db *gorm.DB, exchange_name, pair string) (*entity.OrderBook, error) {
+	pairs := []string{pair}
+	if base, quote, ok := entity.SplitPair(pair); ok {
+		pairs = entity.PairSpellings(base, quote)
+	}
+
+	var orderBookDTOs []*entity.OrderBookDTO
+	tx := db.Table("order_book_dtos FINAL").
+		Where("exchange = ?", exchange_name).
+		Where("pair IN ?", pairs).
+		Order("timestamp DESC, sequence DESC").
+		Limit(1).
+		Find(&orderBookDTOs)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if len(orderBookDTOs) == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	orderBook, err := entity.ToOrderBookEntity(orderBookDTOs[0])
+	if err != nil {
+		return nil, fmt.Errorf("error converting to Entity: %w", err)
+	}
+
+	return orderBook, nil
+}
+
 /*
 SaveOrderBook saves an order book entity to the database.
 It converts array of order books entities to DTOs and attempts to save them.
@@ -69,7 +123,7 @@ func (r *orderRepositoryImpl) SaveOrderBook(order []*entity.OrderBook) error {
 		}
 
 		if err := r.db.Create(orderBookDTO).Error; err != nil {
-			return fmt.Errorf("error saving OrderBook: %w", err)
+			return fmt.Errorf("error saving OrderBook: %w", storageError(err))
 		}
 	}
 	return nil
@@ -84,34 +138,138 @@ If a database error occurs, it returns the error.
 
 func (r *orderRepositoryImpl) GetOrderHistory(client *entity.Client) ([]*entity.HistoryOrder, error) {
 	var orderHistory []*entity.HistoryOrder
-	tx := r.db.
+	tx := r.db.Table("history_orders FINAL").
 		Where("client_name = ?", client.ClientName).
 		Where("exchange_name = ?", client.ExchangeName).
 		Where("label = ?", client.Label).
 		Where("pair = ?", client.Pair).
 		Find(&orderHistory)
 
-	if tx.RowsAffected == 0 {
-		return nil, gorm.ErrRecordNotFound
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
 	}
 
-	if tx.Error != nil {
-		return nil, tx.Error
+	if tx.RowsAffected == 0 {
+		return nil, entity.ErrNotFound
 	}
 
 	return orderHistory, nil
 }
 
+/*
+FindOrderHistory retrieves history orders matching the filter, oldest first.
+Empty filter fields are not applied.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *orderRepositoryImpl) FindOrderHistory(filter *entity.HistoryFilter) ([]*entity.HistoryOrder, error) {
+	return findHistoryOrders(r.db, filter)
+}
+
 /*
 SaveOrderHistory saves a history order entity to the database.
+A retry of the same insert is dropped by the database, so the fill is counted once in commission_daily.
 If the save operation fails, it returns the error.
 */
 
 func (r *orderRepositoryImpl) SaveOrderHistory(order entity.HistoryOrder) error {
-	tx := r.db.Create(&order)
+	tx := withInsertToken(r.db, []string{order.DedupKey}).Create(&order)
 	if tx.Error != nil {
-		return tx.Error
+		return storageError(tx.Error)
 	}
 
 	return nil
 }
+
+/*
+SaveOrderBookBatch saves order book entities to the database with a single insert.
+If the conversion or save operation fails, it returns an error and nothing is saved.
+*/
+
+func (r *orderRepositoryImpl) SaveOrderBookBatch(orderBook []*entity.OrderBook) error {
+	if len(orderBook) == 0 {
+		return nil
+	}
+
+	orderBookDTOs := make([]*entity.OrderBookDTO, len(orderBook))
+	for i, o := range orderBook {
+		orderBookDTO, err := entity.ToOrderBookDTO(o)
+		if err != nil {
+			return fmt.Errorf("error converting to DTO: %w", err)
+		}
+		orderBookDTOs[i] = orderBookDTO
+	}
+
+	if err := r.db.Create(&orderBookDTOs).Error; err != nil {
+		return fmt.Errorf("error saving OrderBook batch: %w", storageError(err))
+	}
+	return nil
+}
+
+/*
+SaveOrderHistoryBatch saves history order entities to the database with a single insert.
+A retry of the same batch is dropped by the database, so its fills are counted once in commission_daily.
+If the save operation fails, it returns the error.
+*/
+
+func (r *orderRepositoryImpl) SaveOrderHistoryBatch(orders []*entity.HistoryOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(orders))
+	for i, o := range orders {
+		keys[i] = o.DedupKey
+	}
+	if err := withInsertToken(r.db, keys).Create(&orders).Error; err != nil {
+		return fmt.Errorf("error saving HistoryOrder batch: %w", storageError(err))
+	}
+	return nil
+}
+
+/*
+GetExistingOrderBookKeys returns which of the given dedup keys are already stored in order_book_dtos.
+If a database error occurs, it returns the error.
+*/
+
+func (r *orderRepositoryImpl) GetExistingOrderBookKeys(keys []string) ([]string, error) {
+	return r.existingKeys(&entity.OrderBookDTO{}, keys)
+}
+
+/*
+GetExistingHistoryOrderKeys returns which of the given dedup keys are already stored in history_orders.
+If a database error occurs, it returns the error.
+*/
+
+func (r *orderRepositoryImpl) GetExistingHistoryOrderKeys(keys []string) ([]string, error) {
+	return r.existingKeys(&entity.HistoryOrder{}, keys)
+}
+
+/*
+withInsertToken sends an insert with a deduplication token derived from the dedup keys of its rows,
+so history_orders drops an insert of the same rows it received shortly before together with its view inserts.
+*/
+func withInsertToken(db *gorm.DB, keys []string) *gorm.DB {
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	settings := clickhouse.Settings{"insert_deduplication_token": hex.EncodeToString(sum[:])}
+	return db.WithContext(clickhouse.Context(context.Background(), clickhouse.WithSettings(settings)))
+}
+
+func (r *orderRepositoryImpl) existingKeys(model any, keys []string) ([]string, error) {
+	existing := []string{}
+	if len(keys) == 0 {
+		return existing, nil
+	}
+
+	tx := r.db.Model(model).
+		Distinct().
+		Where("dedup_key IN ?", keys).
+		Pluck("dedup_key", &existing)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	return existing, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"syscall"
 	"testing"
 	"time"
 
@@ -28,7 +29,7 @@ func TestGetOrderBook(t *testing.T) {
 
 	repo := NewOrderRepository(gormDB)
 
-	mock.ExpectQuery("^SELECT \\* FROM `order_book_dtos` WHERE exchange = \\? AND pair = \\?$").
+	mock.ExpectQuery("^SELECT \\* FROM order_book_dtos FINAL WHERE exchange = \\? AND pair = \\?$").
 		WithArgs("exchange1", "pair1").
 		WillReturnRows(sqlmock.NewRows([]string{"id", "exchange", "pair", "asks", "bids"}).
 			AddRow(1, "exchange1", "pair1", `[]`, `[]`))
@@ -39,22 +40,69 @@ func TestGetOrderBook(t *testing.T) {
 	assert.NotNil(t, orderBooks)
 	assert.Len(t, orderBooks, 1)
 
-	// Test case: record not found (using gorm.ErrRecordNotFound)
-	mock.ExpectQuery("^SELECT \\* FROM `order_book_dtos` WHERE exchange = \\? AND pair = \\?$").
+	// Test case: record not found
+	mock.ExpectQuery("^SELECT \\* FROM order_book_dtos FINAL WHERE exchange = \\? AND pair = \\?$").
 		WithArgs("nonexistent_exchange", "nonexistent_pair").
-		WillReturnError(gorm.ErrRecordNotFound)
+		WillReturnRows(sqlmock.NewRows([]string{"id", "exchange", "pair", "asks", "bids"}))
 	orderBooks, err = repo.GetOrderBook("nonexistent_exchange", "nonexistent_pair")
-	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, err, entity.ErrNotFound)
 	assert.Nil(t, orderBooks)
 
 	// Test case: database error (using sql.ErrNoRows)
-	mock.ExpectQuery("^SELECT \\* FROM `order_book_dtos` WHERE exchange = \\? AND pair = \\?$").
+	mock.ExpectQuery("^SELECT \\* FROM order_book_dtos FINAL WHERE exchange = \\? AND pair = \\?$").
 		WithArgs("invalid_exchange", "invalid_pair").
 		WillReturnError(sql.ErrNoRows)
 	orderBooks, err = repo.GetOrderBook("invalid_exchange", "invalid_pair")
 	assert.NotNil(t, err)
 	assert.Nil(t, orderBooks)
 
+	// Test case: storage unreachable is not reported as not found
+	mock.ExpectQuery("^SELECT \\* FROM order_book_dtos FINAL WHERE exchange = \\? AND pair = \\?$").
+		WithArgs("exchange1", "pair1").
+		WillReturnError(syscall.ECONNREFUSED)
+	orderBooks, err = repo.GetOrderBook("exchange1", "pair1")
+	assert.ErrorIs(t, err, entity.ErrUnavailable)
+	assert.NotErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, orderBooks)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestGetLatestOrderBook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewOrderRepository(gormDB)
+
+	query := "^SELECT \\* FROM order_book_dtos FINAL WHERE exchange = \\? AND pair IN \\(\\?,\\?,\\?,\\?,\\?,\\?\\) ORDER BY timestamp DESC, sequence DESC LIMIT \\?$"
+	mock.ExpectQuery(query).
+		WithArgs("exchange1", "BTC/USDT", "btc/usdt", "BTC-USDT", "btc-usdt", "BTC_USDT", "btc_usdt", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "exchange", "pair", "asks", "bids", "sequence"}).
+			AddRow(1, "exchange1", "BTC/USDT", `[]`, `[]`, 7))
+
+	orderBook, err := repo.GetLatestOrderBook("exchange1", "btc-usdt")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), orderBook.Sequence)
+
+	mock.ExpectQuery(query).
+		WithArgs("exchange1", "ETH/USDT", "eth/usdt", "ETH-USDT", "eth-usdt", "ETH_USDT", "eth_usdt", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "exchange", "pair", "asks", "bids"}))
+
+	orderBook, err = repo.GetLatestOrderBook("exchange1", "ETH/USDT")
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, orderBook)
+
 	err = mock.ExpectationsWereMet()
 	assert.NoError(t, err)
 }
@@ -118,7 +166,7 @@ func TestGetOrderHistory(t *testing.T) {
 		Pair:         "pair1",
 	}
 
-	mock.ExpectQuery("^SELECT \\* FROM `history_orders` WHERE client_name = \\? AND exchange_name = \\? AND label = \\? AND pair = \\?$").
+	mock.ExpectQuery("^SELECT \\* FROM history_orders FINAL WHERE client_name = \\? AND exchange_name = \\? AND label = \\? AND pair = \\?$").
 		WithArgs("client1", "exchange1", "label1", "pair1").
 		WillReturnRows(sqlmock.NewRows([]string{"client_name", "exchange_name", "label", "pair", "side", "type", "base_qty", "price", "algorithm_name_placed", "lowest_sell_prc", "highest_buy_prc", "commission_quote_qty", "time_placed"}).
 			AddRow("client1", "exchange1", "label1", "pair1", "buy", "market", 1.0, 100.0, "algo1", 105.0, 95.0, 0.1, time.Now()))
@@ -136,8 +184,19 @@ func TestGetOrderHistory(t *testing.T) {
 		Label:        "nonexistent_label",
 		Pair:         "nonexistent_pair",
 	}
+	mock.ExpectQuery("^SELECT \\* FROM history_orders FINAL WHERE client_name = \\? AND exchange_name = \\? AND label = \\? AND pair = \\?$").
+		WithArgs("nonexistent_client", "nonexistent_exchange", "nonexistent_label", "nonexistent_pair").
+		WillReturnRows(sqlmock.NewRows([]string{"client_name", "exchange_name", "label", "pair"}))
 	historyOrders, err = repo.GetOrderHistory(client)
-	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, historyOrders)
+
+	// Test case: storage unreachable is not reported as not found
+	mock.ExpectQuery("^SELECT \\* FROM history_orders FINAL (.+)$").
+		WillReturnError(syscall.ECONNREFUSED)
+	historyOrders, err = repo.GetOrderHistory(client)
+	assert.ErrorIs(t, err, entity.ErrUnavailable)
+	assert.NotErrorIs(t, err, entity.ErrNotFound)
 	assert.Nil(t, historyOrders)
 
 	// Verify all expectations were met
@@ -170,3 +229,37 @@ func TestSaveOrderHistory(t *testing.T) {
 	err = repo.SaveOrderHistory(orderHistory)
 	assert.Error(t, err)
 }
+
+func TestGetExistingOrderBookKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewOrderRepository(gormDB)
+
+	mock.ExpectQuery("^SELECT DISTINCT `dedup_key` FROM `order_book_dtos` WHERE dedup_key IN \\(\\?,\\?\\)$").
+		WithArgs("key1", "key2").
+		WillReturnRows(sqlmock.NewRows([]string{"dedup_key"}).AddRow("key2"))
+
+	// Test case: one key already stored
+	keys, err := repo.GetExistingOrderBookKeys([]string{"key1", "key2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key2"}, keys)
+
+	// Test case: nothing to look up
+	keys, err = repo.GetExistingOrderBookKeys(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"gorm.io/gorm"
+)
+
+// maxRiskBreaches bounds the number of breaches returned at once.
+const maxRiskBreaches = 1000
+
+type RiskRepository interface {
+	GetRiskLimits(clientName string) ([]*entity.RiskLimit, error)
+	GetRiskLimit(clientName, exchangeName, pair string) (*entity.RiskLimit, error)
+	SaveRiskLimit(limit entity.RiskLimit) error
+	GetExposure(clientName, exchangeName, pair string, dayStart time.Time) (*entity.RiskExposure, error)
+	GetLatestOrderBook(exchange_name, pair string) (*entity.OrderBook, error)
+	SaveRiskBreaches(breaches []*entity.RiskBreach) error
+	GetRiskBreaches(clientName string) ([]*entity.RiskBreach, error)
+}
+
+type riskRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRiskRepository(db *gorm.DB) RiskRepository {
+	return &riskRepositoryImpl{db: db}
+}
+
+/*
+GetRiskLimits retrieves the risk limits of a client, or of every client if clientName is empty.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *riskRepositoryImpl) GetRiskLimits(clientName string) ([]*entity.RiskLimit, error) {
+	tx := r.db.Table("risk_limits FINAL")
+	if clientName != "" {
+		tx = tx.Where("client_name = ?", clientName)
+	}
+
+	var limits []*entity.RiskLimit
+	tx = tx.Order("client_name, exchange_name, pair").Find(&limits)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return limits, nil
+}
+
+/*
+GetRiskLimit retrieves the limits applying to a pair: the pair's own limits if set,
+otherwise the limits the client has for every pair of the exchange.
+If no record is found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *riskRepositoryImpl) GetRiskLimit(clientName, exchangeName, pair string) (*entity.RiskLimit, error) {
+	var limits []*entity.RiskLimit
+	tx := r.db.Table("risk_limits FINAL").
+		Where("client_name = ?", clientName).
+		Where("exchange_name = ?", exchangeName).
+		Where("pair IN ?", []string{pair, ""}).
+		Order("pair DESC").
+		Limit(1).
+		Find(&limits)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if len(limits) == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return limits[0], nil
+}
+
+/*
+SaveRiskLimit saves a new version of a risk limit, replacing the previous one.
+If the save operation fails, it returns the error.
+*/
+
+func (r *riskRepositoryImpl) SaveRiskLimit(limit entity.RiskLimit) error {
+	return storageError(r.db.Table("risk_limits").Create(&limit).Error)
+}
+
+/*
+GetExposure sums the fills of a client in a pair into a position, its cost, fees paid,
+and the notional traded since dayStart.
+If a database error occurs, it returns the error.
+*/
+
+func (r *riskRepositoryImpl) GetExposure(clientName, exchangeName, pair string, dayStart time.Time) (*entity.RiskExposure, error) {
+	var exposure entity.RiskExposure
+	tx := r.db.Table("history_orders FINAL").
+		Select(`sum(if(side = 'buy', base_qty, -base_qty)) AS position,
+			sum(if(side = 'buy', base_qty, -base_qty) * price) AS cost,
+			sum(commission_quote_qty) AS fees,
+			sumIf(base_qty * price, time_placed >= ?) AS daily_notional`, dayStart).
+		Where("client_name = ?", clientName).
+		Where("exchange_name = ?", exchangeName).
+		Where("pair = ?", pair).
+		Scan(&exposure)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	return &exposure, nil
+}
+
+/*
+GetLatestOrderBook retrieves the most recent order book snapshot of an exchange and trading pair,
+matching the pair regardless of case and separator as OrderRepository does.
+If no record is found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *riskRepositoryImpl) GetLatestOrderBook(exchange_name, pair string) (*entity.OrderBook, error) {
+	return latestOrderBook(r.db, exchange_name, pair)
+}
+
+/*
+SaveRiskBreaches saves detected risk breaches.
+If the save operation fails, it returns the error.
+*/
+
+func (r *riskRepositoryImpl) SaveRiskBreaches(breaches []*entity.RiskBreach) error {
+	if len(breaches) == 0 {
+		return nil
+	}
+	return storageError(r.db.Table("risk_breaches").Create(&breaches).Error)
+}
+
+/*
+GetRiskBreaches retrieves the latest risk breaches of a client, or of every client if clientName is empty, newest first.
+If no records are found, it returns a "record not found" error.
+If a database error occurs, it returns the error.
+*/
+
+func (r *riskRepositoryImpl) GetRiskBreaches(clientName string) ([]*entity.RiskBreach, error) {
+	tx := r.db.Table("risk_breaches")
+	if clientName != "" {
+		tx = tx.Where("client_name = ?", clientName)
+	}
+
+	var breaches []*entity.RiskBreach
+	tx = tx.Order("detected_at DESC").Limit(maxRiskBreaches).Find(&breaches)
+
+	if tx.Error != nil {
+		return nil, storageError(tx.Error)
+	}
+
+	if tx.RowsAffected == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	return breaches, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"github.com/egorque1/vortex-test/internal/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/clickhouse"
+	"gorm.io/gorm"
+)
+
+func TestGetRiskLimit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewRiskRepository(gormDB)
+
+	mock.ExpectQuery("^SELECT \\* FROM risk_limits FINAL WHERE client_name = \\? AND exchange_name = \\? AND pair IN \\(\\?,\\?\\) ORDER BY pair DESC LIMIT \\?$").
+		WithArgs("client1", "exchange1", "pair1", "", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"client_name", "exchange_name", "pair", "max_position"}).
+			AddRow("client1", "exchange1", "", 10.0))
+
+	// Test case: falls back to the limits of every pair
+	limit, err := repo.GetRiskLimit("client1", "exchange1", "pair1")
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, limit.MaxPosition)
+
+	// Test case: record not found
+	mock.ExpectQuery("^SELECT \\* FROM risk_limits FINAL WHERE (.+)$").
+		WithArgs("client2", "exchange1", "pair1", "", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"client_name"}))
+	limit, err = repo.GetRiskLimit("client2", "exchange1", "pair1")
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, limit)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
+
+func TestGetExposure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT version()").WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("mock_version"))
+
+	gormDB, err := gorm.Open(clickhouse.New(clickhouse.Config{DriverName: "clickhouse", Conn: db}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("error creating gorm DB: %v", err)
+	}
+
+	repo := NewRiskRepository(gormDB)
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("^SELECT (.+) FROM history_orders FINAL WHERE client_name = \\? AND exchange_name = \\? AND pair = \\?$").
+		WithArgs(day, "client1", "exchange1", "pair1").
+		WillReturnRows(sqlmock.NewRows([]string{"position", "cost", "fees", "daily_notional"}).
+			AddRow(2.0, 200.0, 0.2, 100.0))
+
+	exposure, err := repo.GetExposure("client1", "exchange1", "pair1", day)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, exposure.Position)
+	assert.Equal(t, 100.0, exposure.DailyNotional)
+
+	err = mock.ExpectationsWereMet()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	orderv1 "github.com/egorque1/vortex-test/api/order/v1"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+/*
+methodScopes are the scopes the methods require, as main.go requires them of the REST routes.
+The history methods also check the client of each request against the key.
+Other methods, such as reflection, only need a valid key.
+*/
+var methodScopes = map[string]entity.Scope{
+	orderv1.OrderService_GetOrderBook_FullMethodName:        entity.ScopeReadBooks,
+	orderv1.OrderService_SubscribeOrderBooks_FullMethodName: entity.ScopeReadBooks,
+	orderv1.OrderService_SaveOrderBook_FullMethodName:       entity.ScopeWriteBooks,
+	orderv1.OrderService_GetOrderHistory_FullMethodName:     entity.ScopeReadHistory,
+	orderv1.OrderService_FindOrderHistory_FullMethodName:    entity.ScopeReadHistory,
+	orderv1.OrderService_SubscribeFills_FullMethodName:      entity.ScopeReadHistory,
+	orderv1.OrderService_SaveOrderHistory_FullMethodName:    entity.ScopeWriteHistory,
+}
+
+/*
+authenticate checks the API key or JWT in the authorization metadata, as a bearer token, or in the x-api-key metadata
+against the scope of method, and returns ctx carrying the key. As controller.Authenticate does for REST requests,
+the calls failing authentication are limited by peer IP to the quota of the ip group of limits,
+and a peer IP over it gets a ResourceExhausted status before its credential is checked.
+*/
+func authenticate(ctx context.Context, auth service.AuthService, limits service.RateLimitService, method string) (context.Context, error) {
+	ip := "ip:" + peerIP(ctx)
+	if result, _ := limits.Check(ip, "", "", "ip"); !result.Allowed {
+		return nil, rateLimited(result)
+	}
+
+	key, err := authenticateToken(ctx, auth)
+	if err != nil {
+		if errors.Is(err, entity.ErrUnauthorized) {
+			limits.Allow(ip, "", "", "ip")
+		}
+		return nil, err
+	}
+	if scope, ok := methodScopes[method]; ok {
+		if err := key.Require(scope); err != nil {
+			return nil, err
+		}
+	}
+
+	return service.WithAPIKey(ctx, key), nil
+}
+
+// authenticateToken returns the key of the API key or JWT in the authorization or x-api-key metadata of ctx.
+func authenticateToken(ctx context.Context, auth service.AuthService) (*entity.APIKey, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var token string
+	if values := md.Get("authorization"); len(values) > 0 {
+		token, _ = strings.CutPrefix(values[0], "Bearer ")
+	} else if values := md.Get("x-api-key"); len(values) > 0 {
+		token = values[0]
+	}
+	if token == "" {
+		return nil, entity.ErrInvalidAPIKey
+	}
+	return auth.Authenticate(token)
+}
+
+// authorize checks that the key of the call has scope for clientName, or for every client if clientName is empty.
+func authorize(ctx context.Context, scope entity.Scope, clientName string) error {
+	key := service.APIKeyFromContext(ctx)
+	if key == nil {
+		return entity.ErrInvalidAPIKey
+	}
+	return key.Allow(scope, clientName)
+}
+
+func unaryAuth(auth service.AuthService, limits service.RateLimitService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, auth, limits, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamAuth(auth service.AuthService, limits service.RateLimitService) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), auth, limits, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream is a server stream whose context carries the key of the call.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
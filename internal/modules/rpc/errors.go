@@ -0,0 +1,89 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the google.rpc.ErrorInfo details of errors.
+const errorDomain = "vortex-test"
+
+// errorKinds maps the kinds of domain errors to their status code and default reason, as the REST API maps them to HTTP statuses.
+var errorKinds = []struct {
+	kind   error
+	code   codes.Code
+	reason string
+}{
+	{entity.ErrValidation, codes.InvalidArgument, "validation_failed"},
+	{entity.ErrUnauthorized, codes.Unauthenticated, "unauthorized"},
+	{entity.ErrForbidden, codes.PermissionDenied, "forbidden"},
+	{entity.ErrNotFound, codes.NotFound, "not_found"},
+	{entity.ErrConflict, codes.AlreadyExists, "conflict"},
+	{entity.ErrUnprocessable, codes.FailedPrecondition, "unprocessable"},
+	{entity.ErrUnavailable, codes.Unavailable, "storage_unavailable"},
+	{entity.ErrTimeout, codes.DeadlineExceeded, "timeout"},
+}
+
+/*
+statusError turns a domain error into a status with a google.rpc.ErrorInfo detail whose reason is the error code,
+and a google.rpc.BadRequest detail listing the invalid fields of a validation error.
+Errors of no known kind are internal errors; their detail is logged and not sent.
+*/
+func statusError(method string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code, reason, message := codes.Internal, "internal", "internal server error"
+	for _, k := range errorKinds {
+		if errors.Is(err, k.kind) {
+			code, reason, message = k.code, k.reason, k.kind.Error()
+			break
+		}
+	}
+
+	var domain *entity.Error
+	if errors.As(err, &domain) && code != codes.Internal {
+		reason, message = domain.Code, domain.Message
+	}
+	if code == codes.Internal {
+		log.Printf("rpc %s: %v", method, err)
+	} else if code != codes.Unavailable && code != codes.DeadlineExceeded {
+		message = err.Error()
+	}
+
+	st, _ := status.New(code, message).WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain})
+
+	var invalid *entity.ValidationError
+	if errors.As(err, &invalid) {
+		badRequest := &errdetails.BadRequest{}
+		for _, f := range invalid.Fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Message})
+		}
+		st, _ = st.WithDetails(badRequest)
+	}
+
+	return st.Err()
+}
+
+func unaryErrors(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, statusError(info.FullMethod, err)
+	}
+	return resp, nil
+}
+
+func streamErrors(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		return statusError(info.FullMethod, err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"context"
+	"net"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+	"github.com/egorque1/vortex-test/internal/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+/*
+rateLimit takes a call to method from the quotas of limits, as controller.RateLimit does for REST requests:
+a call not authenticated is limited by peer IP in the ip group, an authenticated one by its key,
+in the write group if method needs a write scope and the read group otherwise, or by a quota of method itself.
+Returns a ResourceExhausted status with a google.rpc.RetryInfo detail if the call is over its quota.
+*/
+func rateLimit(ctx context.Context, limits service.RateLimitService, method string) error {
+	var principal, keyID, route, group string
+	if key := service.APIKeyFromContext(ctx); key != nil {
+		principal, keyID, route, group = "key:"+key.ID, key.ID, method, "read"
+		if scope := methodScopes[method]; scope == entity.ScopeWriteBooks || scope == entity.ScopeWriteHistory {
+			group = "write"
+		}
+	} else {
+		principal, group = "ip:"+peerIP(ctx), "ip"
+	}
+
+	if result, _ := limits.Allow(principal, keyID, route, group); !result.Allowed {
+		return rateLimited(result)
+	}
+	return nil
+}
+
+// rateLimited returns the status of a call over its quota, with a google.rpc.RetryInfo detail.
+func rateLimited(result ratelimit.Result) error {
+	st, _ := status.New(codes.ResourceExhausted, "too many requests").WithDetails(
+		&errdetails.ErrorInfo{Reason: "rate_limited", Domain: errorDomain},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(result.RetryAfter)},
+	)
+	return st.Err()
+}
+
+// peerIP returns the IP address of the caller, or an empty string if it is unknown.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func unaryRateLimit(limits service.RateLimitService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := rateLimit(ctx, limits, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamRateLimit(limits service.RateLimitService) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := rateLimit(ss.Context(), limits, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,192 @@
+package rpc
+
+import (
+	"context"
+	"log"
+
+	orderv1 "github.com/egorque1/vortex-test/api/order/v1"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/service"
+	"github.com/egorque1/vortex-test/internal/protoconv"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+type orderServerImpl struct {
+	orderv1.UnimplementedOrderServiceServer
+	svc    service.OrderService
+	stream service.StreamService
+}
+
+func NewOrderServer(svc service.OrderService, stream service.StreamService) orderv1.OrderServiceServer {
+	return &orderServerImpl{svc: svc, stream: stream}
+}
+
+/*
+NewServer returns a gRPC server serving orders to callers presenting an API key accepted by auth,
+within the quotas of limits, with reflection so tools such as grpcurl can list the API.
+Calls failing authentication are limited by peer IP, and authenticated calls by key.
+*/
+func NewServer(orders orderv1.OrderServiceServer, auth service.AuthService, limits service.RateLimitService) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryErrors, unaryAuth(auth, limits), unaryRateLimit(limits)),
+		grpc.ChainStreamInterceptor(streamErrors, streamAuth(auth, limits), streamRateLimit(limits)),
+	)
+	orderv1.RegisterOrderServiceServer(server, orders)
+	reflection.Register(server)
+	return server
+}
+
+func (s *orderServerImpl) GetOrderBook(ctx context.Context, req *orderv1.GetOrderBookRequest) (*orderv1.GetOrderBookResponse, error) {
+	if err := entity.Required("exchange", req.GetExchange(), "pair", req.GetPair()); err != nil {
+		return nil, err
+	}
+
+	books, err := s.svc.GetOrderBook(req.GetExchange(), req.GetPair())
+	if err != nil {
+		return nil, err
+	}
+
+	return &orderv1.GetOrderBookResponse{Books: protoconv.ToOrderBooks(books)}, nil
+}
+
+func (s *orderServerImpl) SaveOrderBook(ctx context.Context, req *orderv1.SaveOrderBookRequest) (*orderv1.SaveResult, error) {
+	result, err := s.svc.SaveOrderBook(protoconv.FromOrderBooks(req.GetBooks()))
+	if err != nil {
+		return nil, err
+	}
+
+	return protoconv.ToSaveResult(result), nil
+}
+
+func (s *orderServerImpl) GetOrderHistory(ctx context.Context, req *orderv1.GetOrderHistoryRequest) (*orderv1.GetOrderHistoryResponse, error) {
+	client := protoconv.FromClient(req.GetClient())
+	if err := authorize(ctx, entity.ScopeReadHistory, client.ClientName); err != nil {
+		return nil, err
+	}
+
+	orders, err := s.svc.GetOrderHistory(client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &orderv1.GetOrderHistoryResponse{Orders: protoconv.ToHistoryOrders(orders)}, nil
+}
+
+func (s *orderServerImpl) FindOrderHistory(ctx context.Context, req *orderv1.HistoryFilter) (*orderv1.GetOrderHistoryResponse, error) {
+	filter := protoconv.FromHistoryFilter(req)
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, entity.ScopeReadHistory, filter.ClientName); err != nil {
+		return nil, err
+	}
+
+	orders, err := s.svc.FindOrderHistory(&filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &orderv1.GetOrderHistoryResponse{Orders: protoconv.ToHistoryOrders(orders)}, nil
+}
+
+func (s *orderServerImpl) SaveOrderHistory(ctx context.Context, req *orderv1.SaveOrderHistoryRequest) (*orderv1.SaveResult, error) {
+	order := protoconv.FromHistoryOrder(req.GetOrder())
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, entity.ScopeWriteHistory, order.ClientName); err != nil {
+		return nil, err
+	}
+
+	result, err := s.svc.SaveOrderHistory(order)
+	if err != nil {
+		return nil, err
+	}
+
+	return protoconv.ToSaveResult(result), nil
+}
+
+func (s *orderServerImpl) SubscribeOrderBooks(req *orderv1.SubscribeOrderBooksRequest, stream orderv1.OrderService_SubscribeOrderBooksServer) error {
+	keys := make([]entity.BookKey, len(req.GetBooks()))
+	for i, k := range req.GetBooks() {
+		keys[i] = entity.BookKey{Exchange: k.GetExchange(), Pair: k.GetPair()}
+	}
+	if err := entity.ValidateBookKeys(keys); err != nil {
+		return err
+	}
+
+	sub, snapshots, err := s.stream.SubscribeOrderBooks(keys)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	filter := entity.NewSnapshotFilter(snapshots)
+	for _, book := range snapshots {
+		if err := stream.Send(&orderv1.OrderBookMessage{Type: orderv1.OrderBookMessage_TYPE_SNAPSHOT, Book: protoconv.ToOrderBook(book)}); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case book, ok := <-sub.C:
+			if !ok {
+				log.Printf("rpc: disconnecting slow order book subscriber")
+				return status.Error(codes.ResourceExhausted, "slow consumer")
+			}
+			if !filter.Pass(book) {
+				continue
+			}
+			if err := stream.Send(&orderv1.OrderBookMessage{Type: orderv1.OrderBookMessage_TYPE_UPDATE, Book: protoconv.ToOrderBook(book)}); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (s *orderServerImpl) SubscribeFills(req *orderv1.SubscribeFillsRequest, stream orderv1.OrderService_SubscribeFillsServer) error {
+	filter := protoconv.FromHistoryFilter(req.GetFilter())
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	if err := authorize(stream.Context(), entity.ScopeReadHistory, filter.ClientName); err != nil {
+		return err
+	}
+
+	sub, missed := s.stream.SubscribeFills(req.GetLastEventId())
+	defer sub.Close()
+
+	send := func(event *entity.FillEvent) error {
+		if !filter.Matches(event.Order) {
+			return nil
+		}
+		return stream.Send(&orderv1.FillEvent{Id: event.ID, Order: protoconv.ToHistoryOrder(event.Order)})
+	}
+
+	for _, event := range missed {
+		if err := send(event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case event, ok := <-sub.C:
+			if !ok {
+				log.Printf("rpc: disconnecting slow fill subscriber")
+				return status.Error(codes.ResourceExhausted, "slow consumer")
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	orderv1 "github.com/egorque1/vortex-test/api/order/v1"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/egorque1/vortex-test/internal/pubsub"
+	"github.com/egorque1/vortex-test/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newClient(t *testing.T, svc *mocks.MockOrderService, stream *mocks.MockStreamService) orderv1.OrderServiceClient {
+	return newClientWithKey(t, svc, stream, &entity.APIKey{ID: "key1", Scopes: []entity.Scope{entity.ScopeAdmin}}, nil)
+}
+
+/*
+newClientWithKey returns a client presenting the token "token1", which the server authenticates as key,
+and limits with limits, or does not limit if limits is nil.
+*/
+func newClientWithKey(t *testing.T, svc *mocks.MockOrderService, stream *mocks.MockStreamService, key *entity.APIKey, limits *mocks.MockRateLimitService) orderv1.OrderServiceClient {
+	auth := &mocks.MockAuthService{}
+	auth.On("Authenticate", "token1").Return(key, nil)
+	auth.On("Authenticate", mock.Anything).Return((*entity.APIKey)(nil), entity.ErrInvalidAPIKey)
+
+	if limits == nil {
+		limits = &mocks.MockRateLimitService{}
+		limits.On("Allow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(ratelimit.Result{Allowed: true}, false)
+		limits.On("Check", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(ratelimit.Result{Allowed: true}, false)
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	server := NewServer(NewOrderServer(svc, stream), auth, limits)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	withToken := func(ctx context.Context) context.Context {
+		if _, ok := metadata.FromOutgoingContext(ctx); ok {
+			return ctx
+		}
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer token1")
+	}
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(withToken(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(withToken(ctx), desc, cc, method, opts...)
+		}))
+	if err != nil {
+		t.Fatalf("error dialing: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return orderv1.NewOrderServiceClient(conn)
+}
+
+func errorReason(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestGetOrderBook(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	client := newClient(t, mockService, &mocks.MockStreamService{})
+
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	books := []*entity.OrderBook{{ID: 1, Exchange: "exchange1", Pair: "BTC/USDT", Asks: []entity.DepthOrder{{Price: 101, BaseQty: 1}}, Timestamp: at}}
+	mockService.On("GetOrderBook", "exchange1", "BTC/USDT").Return(books, nil)
+	mockService.On("GetOrderBook", "exchange1", "ETH/USDT").Return([]*entity.OrderBook(nil), entity.ErrNotFound)
+
+	resp, err := client.GetOrderBook(context.Background(), &orderv1.GetOrderBookRequest{Exchange: "exchange1", Pair: "BTC/USDT"})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Books, 1)
+	assert.Equal(t, 101.0, resp.Books[0].Asks[0].Price)
+	assert.Equal(t, at, resp.Books[0].Timestamp.AsTime())
+
+	_, err = client.GetOrderBook(context.Background(), &orderv1.GetOrderBookRequest{Exchange: "exchange1", Pair: "ETH/USDT"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "not_found", errorReason(err))
+
+	_, err = client.GetOrderBook(context.Background(), &orderv1.GetOrderBookRequest{Exchange: "exchange1"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSaveOrderHistory(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	client := newClient(t, mockService, &mocks.MockStreamService{})
+
+	mockService.On("SaveOrderHistory", mock.MatchedBy(func(o entity.HistoryOrder) bool {
+		return o.ClientName == "client1" && o.Side == entity.SideSell && o.Type == entity.OrderTypeStopLimit
+	})).Return(&entity.SaveResult{Saved: 1, Duplicates: []string{}, RiskBreaches: []*entity.RiskBreach{{Limit: entity.RiskLimitMaxPosition, Value: 11}}}, nil).Once()
+	mockService.On("SaveOrderHistory", mock.Anything).Return((*entity.SaveResult)(nil), entity.ErrUnregisteredClient)
+
+	order := &orderv1.HistoryOrder{ClientName: "client1", Side: orderv1.Side_SIDE_SELL, Type: orderv1.OrderType_ORDER_TYPE_STOP_LIMIT}
+	resp, err := client.SaveOrderHistory(context.Background(), &orderv1.SaveOrderHistoryRequest{Order: order})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), resp.Saved)
+	assert.Equal(t, entity.RiskLimitMaxPosition, resp.RiskBreaches[0].Limit)
+
+	_, err = client.SaveOrderHistory(context.Background(), &orderv1.SaveOrderHistoryRequest{Order: order})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, "unregistered_client", errorReason(err))
+
+	_, err = client.SaveOrderHistory(context.Background(), &orderv1.SaveOrderHistoryRequest{Order: &orderv1.HistoryOrder{ClientName: "client1"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	var fields []string
+	for _, d := range status.Convert(err).Details() {
+		if badRequest, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range badRequest.FieldViolations {
+				fields = append(fields, v.Field)
+			}
+		}
+	}
+	assert.Equal(t, []string{"side", "type"}, fields)
+}
+
+func TestAuthentication(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	key := &entity.APIKey{ID: "key1", Scopes: []entity.Scope{entity.ScopeReadHistory}, ClientNames: []string{"client1"}}
+	client := newClientWithKey(t, mockService, &mocks.MockStreamService{}, key, nil)
+
+	mockService.On("FindOrderHistory", mock.Anything).Return([]*entity.HistoryOrder{}, nil)
+
+	_, err := client.FindOrderHistory(context.Background(), &orderv1.HistoryFilter{ClientName: "client1"})
+	assert.NoError(t, err)
+
+	_, err = client.FindOrderHistory(context.Background(), &orderv1.HistoryFilter{ClientName: "client2"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, "forbidden", errorReason(err))
+
+	_, err = client.GetOrderBook(context.Background(), &orderv1.GetOrderBookRequest{Exchange: "exchange1", Pair: "BTC/USDT"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token2")
+	_, err = client.FindOrderHistory(ctx, &orderv1.HistoryFilter{ClientName: "client1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "invalid_api_key", errorReason(err))
+
+	mockService.AssertNumberOfCalls(t, "FindOrderHistory", 1)
+}
+
+func TestRateLimit(t *testing.T) {
+	mockService := &mocks.MockOrderService{}
+	limits := &mocks.MockRateLimitService{}
+	key := &entity.APIKey{ID: "key1", Scopes: []entity.Scope{entity.ScopeAdmin}}
+	client := newClientWithKey(t, mockService, &mocks.MockStreamService{}, key, limits)
+
+	anyIP := mock.MatchedBy(func(principal string) bool { return strings.HasPrefix(principal, "ip:") })
+	mockService.On("SaveOrderBook", mock.Anything).Return(&entity.SaveResult{}, nil)
+	limits.On("Check", anyIP, "", "", "ip").Return(ratelimit.Result{Allowed: true}, true).Times(3)
+	limits.On("Allow", "key:key1", "key1", orderv1.OrderService_SaveOrderBook_FullMethodName, "write").Return(ratelimit.Result{Allowed: true}, true).Once()
+	limits.On("Allow", "key:key1", "key1", orderv1.OrderService_SaveOrderBook_FullMethodName, "write").Return(ratelimit.Result{RetryAfter: 2 * time.Second}, true)
+
+	_, err := client.SaveOrderBook(context.Background(), &orderv1.SaveOrderBookRequest{})
+	assert.NoError(t, err)
+
+	_, err = client.SaveOrderBook(context.Background(), &orderv1.SaveOrderBookRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, "rate_limited", errorReason(err))
+
+	// Calls failing authentication take from the bucket of their peer IP, and once it is empty every call from the IP is rejected.
+	limits.On("Allow", anyIP, "", "", "ip").Return(ratelimit.Result{}, true).Once()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token2")
+	_, err = client.SaveOrderBook(ctx, &orderv1.SaveOrderBookRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	limits.On("Check", anyIP, "", "", "ip").Return(ratelimit.Result{RetryAfter: time.Second}, true)
+	_, err = client.SaveOrderBook(context.Background(), &orderv1.SaveOrderBookRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	mockService.AssertNumberOfCalls(t, "SaveOrderBook", 1)
+	limits.AssertExpectations(t)
+}
+
+func TestSubscribeOrderBooks(t *testing.T) {
+	mockStream := &mocks.MockStreamService{}
+	client := newClient(t, &mocks.MockOrderService{}, mockStream)
+
+	broker := pubsub.New[*entity.OrderBook](8)
+	snapshot := &entity.OrderBook{Exchange: "exchange1", Pair: "BTC/USDT", Sequence: 5}
+	keys := []entity.BookKey{{Exchange: "exchange1", Pair: "BTC/USDT"}}
+	mockStream.On("SubscribeOrderBooks", keys).Return(broker.Subscribe("exchange1:BTC/USDT"), []*entity.OrderBook{snapshot}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	stream, err := client.SubscribeOrderBooks(ctx, &orderv1.SubscribeOrderBooksRequest{Books: []*orderv1.BookKey{{Exchange: "exchange1", Pair: "BTC/USDT"}}})
+	assert.NoError(t, err)
+
+	msg, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, orderv1.OrderBookMessage_TYPE_SNAPSHOT, msg.Type)
+	assert.Equal(t, int64(5), msg.Book.Sequence)
+
+	broker.Publish("exchange1:BTC/USDT", &entity.OrderBook{Exchange: "exchange1", Pair: "BTC/USDT", Sequence: 4})
+	broker.Publish("exchange1:BTC/USDT", &entity.OrderBook{Exchange: "exchange1", Pair: "BTC/USDT", Sequence: 6})
+
+	msg, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, orderv1.OrderBookMessage_TYPE_UPDATE, msg.Type)
+	assert.Equal(t, int64(6), msg.Book.Sequence)
+
+	stream, err = client.SubscribeOrderBooks(ctx, &orderv1.SubscribeOrderBooksRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSubscribeFills(t *testing.T) {
+	mockStream := &mocks.MockStreamService{}
+	client := newClient(t, &mocks.MockOrderService{}, mockStream)
+
+	broker := pubsub.New[*entity.FillEvent](8)
+	missed := []*entity.FillEvent{
+		{ID: 3, Order: &entity.HistoryOrder{FillID: "fill3", ClientName: "client2"}},
+		{ID: 4, Order: &entity.HistoryOrder{FillID: "fill4", ClientName: "client1"}},
+	}
+	mockStream.On("SubscribeFills", uint64(2)).Return(broker.Subscribe(""), missed)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	stream, err := client.SubscribeFills(ctx, &orderv1.SubscribeFillsRequest{Filter: &orderv1.HistoryFilter{ClientName: "client1"}, LastEventId: 2})
+	assert.NoError(t, err)
+
+	event, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), event.Id)
+
+	broker.Publish("", &entity.FillEvent{ID: 5, Order: &entity.HistoryOrder{FillID: "fill5", ClientName: "client1", Side: entity.SideBuy}})
+
+	event, err = stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "fill5", event.Order.FillId)
+	assert.Equal(t, orderv1.Side_SIDE_BUY, event.Order.Side)
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"math"
+	"sort"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+)
+
+type AnalyticsService interface {
+	GetAlgorithmPerformance(req *entity.AlgorithmPerformanceRequest) ([]*entity.AlgorithmPerformance, error)
+	GetSlippageAnalysis(req *entity.SlippageRequest) ([]*entity.SlippageAnalysis, error)
+	GetMarkouts(req *entity.MarkoutRequest) ([]*entity.MarkoutAnalysis, error)
+	GetTradeBars(req *entity.TradeBarRequest) ([]*entity.TradeBar, error)
+}
+
+type analyticsServiceImpl struct {
+	repo repository.AnalyticsRepository
+}
+
+func NewAnalyticsService(repo repository.AnalyticsRepository) AnalyticsService {
+	return &analyticsServiceImpl{repo: repo}
+}
+
+/*
+GetAlgorithmPerformance ranks algorithms by the requested metric over the filtered order history.
+Realized PnL uses average cost per exchange and pair; slippage is measured against
+the opposite side of the book recorded at placement. Lower fees and slippage rank higher,
+every other metric ranks higher when larger.
+Also returns an error if one occures.
+*/
+
+func (s *analyticsServiceImpl) GetAlgorithmPerformance(req *entity.AlgorithmPerformanceRequest) ([]*entity.AlgorithmPerformance, error) {
+	orders, err := s.repo.GetHistoryOrders(&req.HistoryFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	type algorithmState struct {
+		perf          *entity.AlgorithmPerformance
+		positions     map[string]*position
+		slippageSum   float64
+		slippageCount int
+	}
+
+	states := make(map[string]*algorithmState)
+	for _, o := range orders {
+		st, ok := states[o.AlgorithmNamePlaced]
+		if !ok {
+			st = &algorithmState{
+				perf:      &entity.AlgorithmPerformance{AlgorithmNamePlaced: o.AlgorithmNamePlaced},
+				positions: make(map[string]*position),
+			}
+			states[o.AlgorithmNamePlaced] = st
+		}
+
+		st.perf.FillCount++
+		st.perf.TradedNotional += o.Price * o.BaseQty
+		st.perf.Fees += o.CommissionQuoteQty
+
+		sign := sideSign(o.Side)
+		if sign == 0 {
+			continue
+		}
+
+		key := o.ExchangeName + "|" + o.Pair
+		pos, ok := st.positions[key]
+		if !ok {
+			pos = &position{}
+			st.positions[key] = pos
+		}
+		st.perf.RealizedPnL += pos.fill(sign*o.BaseQty, o.Price)
+
+		if bps, ok := touchSlippageBps(o); ok {
+			st.slippageSum += bps
+			st.slippageCount++
+		}
+	}
+
+	result := make([]*entity.AlgorithmPerformance, 0, len(states))
+	for _, st := range states {
+		st.perf.NetPnL = st.perf.RealizedPnL - st.perf.Fees
+		if st.slippageCount > 0 {
+			st.perf.AvgSlippageBps = st.slippageSum / float64(st.slippageCount)
+		}
+		result = append(result, st.perf)
+	}
+
+	rankAlgorithms(result, req.SortBy)
+
+	return result, nil
+}
+
+/*
+GetTradeBars returns OHLCV bars of our own fills with the volume-weighted average price of each bar.
+Also returns an error if one occures.
+*/
+
+func (s *analyticsServiceImpl) GetTradeBars(req *entity.TradeBarRequest) ([]*entity.TradeBar, error) {
+	bars, err := s.repo.GetTradeBars(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bar := range bars {
+		if bar.Volume != 0 {
+			bar.Vwap = bar.Notional / bar.Volume
+		}
+	}
+
+	return bars, nil
+}
+
+func rankAlgorithms(perf []*entity.AlgorithmPerformance, sortBy string) {
+	metric := func(p *entity.AlgorithmPerformance) float64 {
+		switch sortBy {
+		case entity.SortByFillCount:
+			return float64(p.FillCount)
+		case entity.SortByTradedNotional:
+			return p.TradedNotional
+		case entity.SortByRealizedPnL:
+			return p.RealizedPnL
+		case entity.SortBySlippage:
+			return -p.AvgSlippageBps
+		case entity.SortByFees:
+			return -p.Fees
+		default:
+			return p.NetPnL
+		}
+	}
+
+	sort.SliceStable(perf, func(i, j int) bool {
+		mi, mj := metric(perf[i]), metric(perf[j])
+		if mi != mj {
+			return mi > mj
+		}
+		return perf[i].AlgorithmNamePlaced < perf[j].AlgorithmNamePlaced
+	})
+
+	for i, p := range perf {
+		p.Rank = i + 1
+	}
+}
+
+// sideSign returns 1 for buys, -1 for sells and 0 for sides it does not recognise.
+func sideSign(side entity.Side) float64 {
+	switch side {
+	case entity.SideBuy:
+		return 1
+	case entity.SideSell:
+		return -1
+	default:
+		return 0
+	}
+}
+
+/*
+touchSlippageBps returns how far an order was filled beyond the touch recorded at placement,
+in basis points of the touch. Buys compare against the lowest sell price and sells against
+the highest buy price; a positive value means the fill was worse than the touch.
+The second result is false when the side or the recorded touch is missing.
+*/
+func touchSlippageBps(o *entity.HistoryOrder) (float64, bool) {
+	switch sideSign(o.Side) {
+	case 1:
+		if o.LowestSellPrc <= 0 {
+			return 0, false
+		}
+		return (o.Price - o.LowestSellPrc) / o.LowestSellPrc * 10000, true
+	case -1:
+		if o.HighestBuyPrc <= 0 {
+			return 0, false
+		}
+		return (o.HighestBuyPrc - o.Price) / o.HighestBuyPrc * 10000, true
+	default:
+		return 0, false
+	}
+}
+
+// position tracks a signed inventory at its average entry price.
+type position struct {
+	qty      float64
+	avgPrice float64
+}
+
+// fill applies a signed fill quantity to the position and returns the PnL it realizes.
+func (p *position) fill(qty, price float64) float64 {
+	if qty == 0 {
+		return 0
+	}
+
+	if p.qty == 0 || (p.qty > 0) == (qty > 0) {
+		total := math.Abs(p.qty) + math.Abs(qty)
+		p.avgPrice = (p.avgPrice*math.Abs(p.qty) + price*math.Abs(qty)) / total
+		p.qty += qty
+		return 0
+	}
+
+	closed := math.Min(math.Abs(qty), math.Abs(p.qty))
+	realized := (price - p.avgPrice) * closed
+	if p.qty < 0 {
+		realized = -realized
+	}
+
+	remaining := p.qty + qty
+	switch {
+	case remaining == 0:
+		p.avgPrice = 0
+	case (remaining > 0) != (p.qty > 0):
+		p.avgPrice = price
+	}
+	p.qty = remaining
+
+	return realized
+}
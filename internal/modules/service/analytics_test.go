@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAlgorithmPerformance(t *testing.T) {
+	mockRepo := new(mocks.MockAnalyticsRepository)
+	mockService := NewAnalyticsService(mockRepo)
+
+	now := time.Now()
+	orders := []*entity.HistoryOrder{
+		{ExchangeName: "exchange1", Pair: "pair1", Side: "buy", BaseQty: 2, Price: 100, AlgorithmNamePlaced: "algo1", LowestSellPrc: 100, HighestBuyPrc: 99, CommissionQuoteQty: 0.2, TimePlaced: now},
+		{ExchangeName: "exchange1", Pair: "pair1", Side: "sell", BaseQty: 1, Price: 110, AlgorithmNamePlaced: "algo1", LowestSellPrc: 111, HighestBuyPrc: 110, CommissionQuoteQty: 0.1, TimePlaced: now.Add(time.Second)},
+		{ExchangeName: "exchange1", Pair: "pair1", Side: "sell", BaseQty: 1, Price: 99, AlgorithmNamePlaced: "algo2", LowestSellPrc: 101, HighestBuyPrc: 100, CommissionQuoteQty: 0.1, TimePlaced: now},
+		{ExchangeName: "exchange1", Pair: "pair1", Side: "buy", BaseQty: 1, Price: 105, AlgorithmNamePlaced: "algo2", LowestSellPrc: 104, HighestBuyPrc: 103, CommissionQuoteQty: 0.1, TimePlaced: now.Add(time.Second)},
+	}
+
+	req := &entity.AlgorithmPerformanceRequest{SortBy: entity.SortByNetPnL}
+	mockRepo.On("GetHistoryOrders", &req.HistoryFilter).Return(orders, nil)
+
+	result, err := mockService.GetAlgorithmPerformance(req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	assert.Equal(t, "algo1", result[0].AlgorithmNamePlaced)
+	assert.Equal(t, 1, result[0].Rank)
+	assert.Equal(t, 2, result[0].FillCount)
+	assert.InDelta(t, 310, result[0].TradedNotional, 1e-9)
+	assert.InDelta(t, 10, result[0].RealizedPnL, 1e-9)
+	assert.InDelta(t, 9.7, result[0].NetPnL, 1e-9)
+	assert.InDelta(t, 0, result[0].AvgSlippageBps, 1e-9)
+
+	assert.Equal(t, "algo2", result[1].AlgorithmNamePlaced)
+	assert.Equal(t, 2, result[1].Rank)
+	assert.InDelta(t, -6, result[1].RealizedPnL, 1e-9)
+	assert.InDelta(t, 98.0769230769, result[1].AvgSlippageBps, 1e-6)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPositionFill(t *testing.T) {
+	p := &position{}
+
+	assert.Zero(t, p.fill(1, 100))
+	assert.Zero(t, p.fill(1, 110))
+	assert.InDelta(t, 105, p.avgPrice, 1e-9)
+
+	// Flip from long 2 to short 1: closes 2 at 120, opens short at 120.
+	assert.InDelta(t, 30, p.fill(-3, 120), 1e-9)
+	assert.InDelta(t, -1, p.qty, 1e-9)
+	assert.InDelta(t, 120, p.avgPrice, 1e-9)
+
+	assert.InDelta(t, 20, p.fill(1, 100), 1e-9)
+	assert.Zero(t, p.qty)
+}
+
+func TestGetTradeBars(t *testing.T) {
+	mockRepo := new(mocks.MockAnalyticsRepository)
+	mockService := NewAnalyticsService(mockRepo)
+
+	req := &entity.TradeBarRequest{ExchangeName: "exchange1", Pair: "pair1", IntervalSeconds: 60}
+	bars := []*entity.TradeBar{
+		{Open: 100, High: 110, Low: 90, Close: 105, Volume: 4, BuyVolume: 3, SellVolume: 1, TradeCount: 3, Notional: 410},
+		{TradeCount: 1},
+	}
+
+	mockRepo.On("GetTradeBars", req).Return(bars, nil)
+
+	result, err := mockService.GetTradeBars(req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.InDelta(t, 102.5, result[0].Vwap, 1e-9)
+	assert.Zero(t, result[1].Vwap)
+
+	mockRepo.AssertExpectations(t)
+}
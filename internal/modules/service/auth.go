@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/cache"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+)
+
+/*
+apiKeyCacheTTL is how long a key lookup is reused when authenticating requests,
+and so how long a key revoked through another instance keeps working here.
+*/
+const apiKeyCacheTTL = time.Minute
+
+// apiKeyPrefix starts every API key, which is apiKeyPrefix, the key ID, a dot and the secret.
+const apiKeyPrefix = "vx_"
+
+type AuthService interface {
+	CreateAPIKey(key entity.APIKey) (string, *entity.APIKey, error)
+	GetAPIKeys() ([]*entity.APIKey, error)
+	RevokeAPIKey(id string) error
+	Authenticate(token string) (*entity.APIKey, error)
+}
+
+type authServiceImpl struct {
+	repo      repository.APIKeyRepository
+	jwtSecret []byte
+	keys      *cache.Cache
+}
+
+// NewAuthService returns a service accepting the stored API keys and, when jwtSecret is not empty, JWTs signed with it.
+func NewAuthService(repo repository.APIKeyRepository, jwtSecret []byte) AuthService {
+	return &authServiceImpl{repo: repo, jwtSecret: jwtSecret, keys: cache.New(apiKeyCacheTTL)}
+}
+
+/*
+CreateAPIKey generates and stores a new key with the name, scopes, clients and expiry of key.
+Returns the API key to hand to the caller, which cannot be recovered later, and the stored key.
+Also returns an error if one occures.
+*/
+
+func (s *authServiceImpl) CreateAPIKey(key entity.APIKey) (string, *entity.APIKey, error) {
+	if err := key.Validate(); err != nil {
+		return "", nil, err
+	}
+
+	id, err := randomString(6)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomString(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	key.ID, key.SecretHash, key.Revoked = id, hashSecret(secret), false
+	key.CreatedAt = time.Now()
+	if err := s.repo.SaveAPIKey(key); err != nil {
+		return "", nil, err
+	}
+
+	return apiKeyPrefix + id + "." + secret, &key, nil
+}
+
+/*
+GetAPIKeys returns every key that is not revoked.
+Also returns an error if one occures.
+*/
+
+func (s *authServiceImpl) GetAPIKeys() ([]*entity.APIKey, error) {
+	return s.repo.GetAPIKeys()
+}
+
+/*
+RevokeAPIKey revokes a key so it is no longer accepted.
+Returns a "record not found" error if there is no such key, or another error if one occures.
+*/
+
+func (s *authServiceImpl) RevokeAPIKey(id string) error {
+	if _, err := s.repo.GetAPIKey(id); err != nil {
+		return err
+	}
+
+	if err := s.repo.RevokeAPIKey(id); err != nil {
+		return err
+	}
+	s.keys.Delete(id)
+
+	return nil
+}
+
+/*
+Authenticate returns the key a request presents, either an API key or a JWT when a JWT secret is configured.
+Returns entity.ErrInvalidAPIKey if the token is unknown, revoked, expired or badly signed, or another error if one occures.
+*/
+
+func (s *authServiceImpl) Authenticate(token string) (*entity.APIKey, error) {
+	if strings.Count(token, ".") == 2 && len(s.jwtSecret) > 0 {
+		return s.authenticateJWT(token)
+	}
+
+	id, secret, ok := strings.Cut(strings.TrimPrefix(token, apiKeyPrefix), ".")
+	if !ok || !strings.HasPrefix(token, apiKeyPrefix) {
+		return nil, entity.ErrInvalidAPIKey
+	}
+
+	// Unknown IDs are cached as nil too, so callers guessing keys do not query storage on every request.
+	var key *entity.APIKey
+	if cached, ok := s.keys.Get(id); ok {
+		key = cached.(*entity.APIKey)
+	} else {
+		found, err := s.repo.GetAPIKey(id)
+		if err != nil && !errors.Is(err, entity.ErrNotFound) {
+			return nil, err
+		}
+		key = found
+		s.keys.Set(id, key)
+	}
+	if key == nil {
+		return nil, entity.ErrInvalidAPIKey
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(key.SecretHash)) != 1 {
+		return nil, entity.ErrInvalidAPIKey
+	}
+	if key.Expired(time.Now()) {
+		return nil, fmt.Errorf("%w: the API key expired at %s", entity.ErrInvalidAPIKey, key.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return key, nil
+}
+
+// jwtClaims are the claims of a JWT read into an entity.APIKey: the subject names the caller.
+type jwtClaims struct {
+	Subject   string         `json:"sub"`
+	Scopes    []entity.Scope `json:"scopes"`
+	Clients   []string       `json:"clients"`
+	ExpiresAt int64          `json:"exp"`
+}
+
+// authenticateJWT verifies a JWT signed with HS256 and returns its claims as a key.
+func (s *authServiceImpl) authenticateJWT(token string) (*entity.APIKey, error) {
+	parts := strings.Split(token, ".")
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return nil, entity.ErrInvalidAPIKey
+	}
+
+	mac := hmac.New(sha256.New, s.jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, entity.ErrInvalidAPIKey
+	}
+
+	var claims jwtClaims
+	if err := decodeJWTPart(parts[1], &claims); err != nil || claims.Subject == "" {
+		return nil, entity.ErrInvalidAPIKey
+	}
+
+	key := &entity.APIKey{ID: "jwt:" + claims.Subject, Name: claims.Subject, Scopes: claims.Scopes, ClientNames: claims.Clients}
+	if claims.ExpiresAt != 0 {
+		expiresAt := time.Unix(claims.ExpiresAt, 0)
+		key.ExpiresAt = &expiresAt
+	}
+	if key.Expired(time.Now()) {
+		return nil, fmt.Errorf("%w: the token expired at %s", entity.ErrInvalidAPIKey, key.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return key, nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+type apiKeyContextKey struct{}
+
+// WithAPIKey returns a copy of ctx carrying the key a request was authenticated with.
+func WithAPIKey(ctx context.Context, key *entity.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+// APIKeyFromContext returns the key a request was authenticated with, or nil if it was not.
+func APIKeyFromContext(ctx context.Context) *entity.APIKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*entity.APIKey)
+	return key
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	authService := NewAuthService(mockRepo, nil)
+
+	var saved entity.APIKey
+	mockRepo.On("SaveAPIKey", mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(0).(entity.APIKey)
+	}).Return(nil)
+
+	token, key, err := authService.CreateAPIKey(entity.APIKey{Name: "collector", Scopes: []entity.Scope{entity.ScopeWriteBooks}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "collector", key.Name)
+	assert.NotEmpty(t, saved.ID)
+	// Only the hash of the secret is stored.
+	assert.Equal(t, hashSecret(token[len(apiKeyPrefix+saved.ID)+1:]), saved.SecretHash)
+
+	// The returned key authenticates as the stored one.
+	mockRepo.On("GetAPIKey", saved.ID).Return(&saved, nil).Once()
+
+	authenticated, err := authService.Authenticate(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, saved.ID, authenticated.ID)
+
+	// A wrong secret for the same key is rejected, using the cached key.
+	_, err = authService.Authenticate(apiKeyPrefix + saved.ID + ".wrong")
+	assert.ErrorIs(t, err, entity.ErrInvalidAPIKey)
+	mockRepo.AssertNumberOfCalls(t, "GetAPIKey", 1)
+}
+
+func TestCreateAPIKey_InvalidScope(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	authService := NewAuthService(mockRepo, nil)
+
+	_, _, err := authService.CreateAPIKey(entity.APIKey{Name: "collector", Scopes: []entity.Scope{"books:delete"}})
+
+	assert.ErrorIs(t, err, entity.ErrValidation)
+	mockRepo.AssertNotCalled(t, "SaveAPIKey", mock.Anything)
+}
+
+func TestAuthenticate(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	authService := NewAuthService(mockRepo, nil)
+
+	expired := time.Now().Add(-time.Hour)
+	mockRepo.On("GetAPIKey", "old").Return(&entity.APIKey{ID: "old", SecretHash: hashSecret("secret"), ExpiresAt: &expired}, nil)
+	mockRepo.On("GetAPIKey", "gone").Return((*entity.APIKey)(nil), entity.ErrNotFound)
+
+	for _, token := range []string{"vx_old.secret", "vx_gone.secret", "old.secret", "vx_old"} {
+		_, err := authService.Authenticate(token)
+		assert.ErrorIs(t, err, entity.ErrInvalidAPIKey, token)
+	}
+
+	// Unknown keys are remembered, so guessing again does not query storage.
+	_, err := authService.Authenticate("vx_gone.other")
+	assert.ErrorIs(t, err, entity.ErrInvalidAPIKey)
+	mockRepo.AssertNumberOfCalls(t, "GetAPIKey", 2)
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	authService := NewAuthService(mockRepo, nil)
+
+	key := &entity.APIKey{ID: "key1", SecretHash: hashSecret("secret"), Scopes: []entity.Scope{entity.ScopeReadBooks}}
+	mockRepo.On("GetAPIKey", "key1").Return(key, nil).Twice()
+	mockRepo.On("RevokeAPIKey", "key1").Return(nil)
+
+	_, err := authService.Authenticate("vx_key1.secret")
+	assert.NoError(t, err)
+
+	assert.NoError(t, authService.RevokeAPIKey("key1"))
+
+	// Revoking drops the cached key, so the next request looks it up again.
+	mockRepo.On("GetAPIKey", "key1").Return((*entity.APIKey)(nil), entity.ErrNotFound)
+	_, err = authService.Authenticate("vx_key1.secret")
+	assert.ErrorIs(t, err, entity.ErrInvalidAPIKey)
+}
+
+func signJWT(secret []byte, header, claims string) string {
+	signingInput := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuthenticate_JWT(t *testing.T) {
+	secret := []byte("jwt-secret")
+	authService := NewAuthService(new(mocks.MockAPIKeyRepository), secret)
+
+	token := signJWT(secret, `{"alg":"HS256","typ":"JWT"}`, `{"sub":"desk1","scopes":["history:read"],"clients":["client1"]}`)
+
+	key, err := authService.Authenticate(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "desk1", key.Name)
+	assert.NoError(t, key.Allow(entity.ScopeReadHistory, "client1"))
+	assert.ErrorIs(t, key.Allow(entity.ScopeReadHistory, "client2"), entity.ErrForbidden)
+
+	for _, bad := range []string{
+		signJWT([]byte("other"), `{"alg":"HS256"}`, `{"sub":"desk1"}`),
+		signJWT(secret, `{"alg":"none"}`, `{"sub":"desk1"}`),
+		signJWT(secret, `{"alg":"HS256"}`, `{"sub":"desk1","exp":1}`),
+	} {
+		_, err := authService.Authenticate(bad)
+		assert.ErrorIs(t, err, entity.ErrInvalidAPIKey, bad)
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/cache"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+)
+
+// clientCacheTTL is how long a registry lookup is reused when validating incoming orders.
+const clientCacheTTL = time.Minute
+
+type ClientService interface {
+	CreateClient(account entity.ClientAccount) error
+	GetClients(clientName string) ([]*entity.ClientAccount, error)
+	GetClient(clientName, exchangeName, label string) (*entity.ClientAccount, error)
+	UpdateClient(account entity.ClientAccount) error
+	DeleteClient(clientName, exchangeName, label string) error
+	ValidateOrder(order *entity.HistoryOrder) error
+}
+
+type clientServiceImpl struct {
+	repo     repository.ClientRepository
+	accounts *cache.Cache
+}
+
+func NewClientService(repo repository.ClientRepository) ClientService {
+	return &clientServiceImpl{repo: repo, accounts: cache.New(clientCacheTTL)}
+}
+
+/*
+CreateClient registers a new client account.
+Returns entity.ErrClientExists if the account is already registered, or another error if one occures.
+*/
+
+func (s *clientServiceImpl) CreateClient(account entity.ClientAccount) error {
+	_, err := s.repo.GetClientAccount(account.ClientName, account.ExchangeName, account.Label)
+	if err == nil {
+		return entity.ErrClientExists
+	}
+	if !errors.Is(err, entity.ErrNotFound) {
+		return err
+	}
+
+	return s.repo.SaveClientAccount(account)
+}
+
+/*
+GetClients returns the registered accounts of a client, or of every client if clientName is empty.
+Also returns an error if one occures.
+*/
+
+func (s *clientServiceImpl) GetClients(clientName string) ([]*entity.ClientAccount, error) {
+	return s.repo.GetClientAccounts(clientName)
+}
+
+/*
+GetClient returns a single registered account.
+Also returns an error if one occures.
+*/
+
+func (s *clientServiceImpl) GetClient(clientName, exchangeName, label string) (*entity.ClientAccount, error) {
+	return s.repo.GetClientAccount(clientName, exchangeName, label)
+}
+
+/*
+UpdateClient replaces the pairs of a registered account, keeping its creation time.
+Returns a "record not found" error if the account is not registered, or another error if one occures.
+*/
+
+func (s *clientServiceImpl) UpdateClient(account entity.ClientAccount) error {
+	existing, err := s.repo.GetClientAccount(account.ClientName, account.ExchangeName, account.Label)
+	if err != nil {
+		return err
+	}
+
+	account.CreatedAt = existing.CreatedAt
+	if err := s.repo.SaveClientAccount(account); err != nil {
+		return err
+	}
+	s.accounts.Delete(accountKey(account.ClientName, account.ExchangeName, account.Label))
+
+	return nil
+}
+
+/*
+DeleteClient removes a registered account.
+Returns a "record not found" error if the account is not registered, or another error if one occures.
+*/
+
+func (s *clientServiceImpl) DeleteClient(clientName, exchangeName, label string) error {
+	if _, err := s.repo.GetClientAccount(clientName, exchangeName, label); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteClientAccount(clientName, exchangeName, label); err != nil {
+		return err
+	}
+	s.accounts.Delete(accountKey(clientName, exchangeName, label))
+
+	return nil
+}
+
+/*
+ValidateOrder checks that the order's client, exchange and label are registered
+and, when the account lists pairs, that the order's pair is one of them.
+Returns entity.ErrUnregisteredClient if not, or another error if one occures.
+*/
+
+func (s *clientServiceImpl) ValidateOrder(order *entity.HistoryOrder) error {
+	key := accountKey(order.ClientName, order.ExchangeName, order.Label)
+
+	var account *entity.ClientAccount
+	if cached, ok := s.accounts.Get(key); ok {
+		account = cached.(*entity.ClientAccount)
+	} else {
+		found, err := s.repo.GetClientAccount(order.ClientName, order.ExchangeName, order.Label)
+		if errors.Is(err, entity.ErrNotFound) {
+			return fmt.Errorf("%w: %s/%s/%s", entity.ErrUnregisteredClient, order.ClientName, order.ExchangeName, order.Label)
+		}
+		if err != nil {
+			return err
+		}
+		account = found
+		s.accounts.Set(key, account)
+	}
+
+	if !account.AllowsPair(order.Pair) {
+		return fmt.Errorf("%w: pair %s is not registered for %s/%s/%s", entity.ErrUnregisteredClient, order.Pair, order.ClientName, order.ExchangeName, order.Label)
+	}
+
+	return nil
+}
+
+func accountKey(clientName, exchangeName, label string) string {
+	return clientName + "\x00" + exchangeName + "\x00" + label
+}
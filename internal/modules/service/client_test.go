@@ -0,0 +1,74 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateClient(t *testing.T) {
+	mockRepo := new(mocks.MockClientRepository)
+	mockService := NewClientService(mockRepo)
+
+	account := entity.ClientAccount{ClientName: "client1", ExchangeName: "exchange1", Label: "label1"}
+
+	mockRepo.On("GetClientAccount", "client1", "exchange1", "label1").Return((*entity.ClientAccount)(nil), entity.ErrNotFound).Once()
+	mockRepo.On("SaveClientAccount", account).Return(nil)
+
+	assert.NoError(t, mockService.CreateClient(account))
+
+	mockRepo.On("GetClientAccount", "client1", "exchange1", "label1").Return(&account, nil)
+
+	assert.ErrorIs(t, mockService.CreateClient(account), entity.ErrClientExists)
+	mockRepo.AssertNumberOfCalls(t, "SaveClientAccount", 1)
+}
+
+func TestUpdateClient(t *testing.T) {
+	mockRepo := new(mocks.MockClientRepository)
+	mockService := NewClientService(mockRepo)
+
+	created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	existing := &entity.ClientAccount{ClientName: "client1", ExchangeName: "exchange1", Label: "label1", CreatedAt: created}
+
+	mockRepo.On("GetClientAccount", "client1", "exchange1", "label1").Return(existing, nil)
+	mockRepo.On("SaveClientAccount", mock.MatchedBy(func(a entity.ClientAccount) bool {
+		return a.CreatedAt.Equal(created) && len(a.Pairs) == 1
+	})).Return(nil)
+
+	err := mockService.UpdateClient(entity.ClientAccount{ClientName: "client1", ExchangeName: "exchange1", Label: "label1", Pairs: []string{"pair1"}})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestValidateOrder(t *testing.T) {
+	mockRepo := new(mocks.MockClientRepository)
+	mockService := NewClientService(mockRepo)
+
+	account := &entity.ClientAccount{ClientName: "client1", ExchangeName: "exchange1", Label: "label1", Pairs: []string{"pair1"}}
+	mockRepo.On("GetClientAccount", "client1", "exchange1", "label1").Return(account, nil).Once()
+	mockRepo.On("GetClientAccount", "clinet1", "exchange1", "label1").Return((*entity.ClientAccount)(nil), entity.ErrNotFound)
+
+	order := &entity.HistoryOrder{ClientName: "client1", ExchangeName: "exchange1", Label: "label1", Pair: "pair1"}
+	assert.NoError(t, mockService.ValidateOrder(order))
+
+	// The second lookup of the same account is served from the cache.
+	order.Pair = "pair2"
+	assert.ErrorIs(t, mockService.ValidateOrder(order), entity.ErrUnregisteredClient)
+
+	// Listed pairs match regardless of case and separator.
+	account.Pairs = []string{"BTC/USDT"}
+	order.Pair = "btc-usdt"
+	assert.NoError(t, mockService.ValidateOrder(order))
+	order.Pair = "ETH/USDT"
+	assert.ErrorIs(t, mockService.ValidateOrder(order), entity.ErrUnregisteredClient)
+
+	typo := &entity.HistoryOrder{ClientName: "clinet1", ExchangeName: "exchange1", Label: "label1", Pair: "pair1"}
+	assert.ErrorIs(t, mockService.ValidateOrder(typo), entity.ErrUnregisteredClient)
+
+	mockRepo.AssertNumberOfCalls(t, "GetClientAccount", 2)
+}
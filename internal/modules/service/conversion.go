@@ -0,0 +1,269 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+)
+
+// conversionBridges are the assets tried in order when no order book prices an asset directly in the currency.
+var conversionBridges = []string{"USDT", "USDC", "USD", "BTC", "ETH"}
+
+// maxRateAge is the age of the oldest order book snapshot a conversion rate is taken from.
+const maxRateAge = 24 * time.Hour
+
+type ConversionService interface {
+	Convert(exchangeName, asset, currency string, qty float64, at time.Time) (float64, error)
+	ConvertAll(conversions []*entity.Conversion) error
+	GetConvertedCommissions(req *entity.CommissionConversionRequest) ([]*entity.ConvertedCommission, error)
+}
+
+type conversionServiceImpl struct {
+	repo      repository.ConversionRepository
+	analytics repository.AnalyticsRepository
+}
+
+func NewConversionService(repo repository.ConversionRepository, analytics repository.AnalyticsRepository) ConversionService {
+	return &conversionServiceImpl{repo: repo, analytics: analytics}
+}
+
+type rateKey struct {
+	exchange, from, to string
+}
+
+/*
+rateCache holds the rates of the asset pairs priced while serving one request.
+The rate of each pair is looked up once, as of every time the request converts at,
+and every conversion takes its rate from there.
+*/
+type rateCache struct {
+	times []time.Time
+	rates map[rateKey]map[int64]float64
+}
+
+func newRateCache(times []time.Time) *rateCache {
+	return &rateCache{times: times, rates: make(map[rateKey]map[int64]float64)}
+}
+
+/*
+Convert values qty of an asset in currency at the mid price of the latest order book snapshot taken before at.
+Books of the exchange are preferred to those of other exchanges, and when no pair of the two assets is stored
+the asset is converted through a common bridge asset such as USDT or BTC.
+Returns entity.ErrNoConversionRate when no book prices the asset, or another error if one occures.
+*/
+
+func (s *conversionServiceImpl) Convert(exchangeName, asset, currency string, qty float64, at time.Time) (float64, error) {
+	c := &entity.Conversion{ExchangeName: exchangeName, Asset: asset, Currency: currency, Qty: qty, At: at}
+	if err := s.ConvertAll([]*entity.Conversion{c}); err != nil {
+		return 0, err
+	}
+	if c.Unpriced {
+		return 0, entity.ErrNoConversionRate
+	}
+	return c.Value, nil
+}
+
+/*
+ConvertAll values every conversion as Convert does, looking the rates of each pair up once for all of them.
+Conversions no book prices are marked unpriced.
+Returns an error if one occures.
+*/
+
+func (s *conversionServiceImpl) ConvertAll(conversions []*entity.Conversion) error {
+	if len(conversions) == 0 {
+		return nil
+	}
+
+	seen := make(map[int64]bool, len(conversions))
+	times := make([]time.Time, 0, len(conversions))
+	for _, c := range conversions {
+		if !seen[c.At.UnixMilli()] {
+			seen[c.At.UnixMilli()] = true
+			times = append(times, c.At)
+		}
+	}
+
+	cache := newRateCache(times)
+	for _, c := range conversions {
+		bridges := conversionBridges
+		if base, quote, ok := entity.SplitPair(c.Pair); ok {
+			bridges = append([]string{quote, base}, conversionBridges...)
+		}
+
+		rate, err := s.rate(cache, c.ExchangeName, strings.ToUpper(c.Asset), strings.ToUpper(c.Currency), c.At, bridges)
+		if errors.Is(err, entity.ErrNoConversionRate) {
+			c.Value, c.Unpriced = 0, true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		c.Value, c.Unpriced = c.Qty*rate, false
+	}
+
+	return nil
+}
+
+/*
+GetConvertedCommissions totals the commissions and notionals of the filtered fills per exchange,
+valued in the requested currency when each fill was placed, so fee rates can be compared across venues.
+Fills that cannot be valued are counted as unpriced and left out of the totals.
+Also returns an error if one occures.
+*/
+
+func (s *conversionServiceImpl) GetConvertedCommissions(req *entity.CommissionConversionRequest) ([]*entity.ConvertedCommission, error) {
+	fills, err := s.analytics.GetHistoryOrders(&req.HistoryFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every fill needs its commission and its notional, which is in the quote asset, valued in the currency.
+	conversions := make([]*entity.Conversion, 0, 2*len(fills))
+	for _, f := range fills {
+		_, quote, _ := entity.SplitPair(f.Pair)
+		asset, qty := f.Commission()
+		conversions = append(conversions,
+			&entity.Conversion{ExchangeName: f.ExchangeName, Pair: f.Pair, Asset: asset, Currency: req.Currency, Qty: qty, At: f.TimePlaced},
+			&entity.Conversion{ExchangeName: f.ExchangeName, Pair: f.Pair, Asset: quote, Currency: req.Currency, Qty: f.BaseQty * f.Price, At: f.TimePlaced},
+		)
+	}
+	if err := s.ConvertAll(conversions); err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]*entity.ConvertedCommission)
+	unpriced := make(map[string]map[string]bool)
+	for i, f := range fills {
+		total, ok := totals[f.ExchangeName]
+		if !ok {
+			total = &entity.ConvertedCommission{ExchangeName: f.ExchangeName, Currency: req.Currency, UnpricedAssets: []string{}}
+			totals[f.ExchangeName] = total
+			unpriced[f.ExchangeName] = make(map[string]bool)
+		}
+		total.Fills++
+
+		commission, notional := conversions[2*i], conversions[2*i+1]
+		if commission.Unpriced || notional.Unpriced {
+			total.Unpriced++
+			for _, c := range []*entity.Conversion{commission, notional} {
+				if c.Unpriced {
+					unpriced[f.ExchangeName][c.Asset] = true
+				}
+			}
+			continue
+		}
+
+		total.Commission += commission.Value
+		total.Notional += notional.Value
+	}
+
+	result := make([]*entity.ConvertedCommission, 0, len(totals))
+	for exchange, total := range totals {
+		for asset := range unpriced[exchange] {
+			total.UnpricedAssets = append(total.UnpricedAssets, asset)
+		}
+		sort.Strings(total.UnpricedAssets)
+		total.FeeRateBps = feeRateBps(total.Commission, total.Notional)
+		result = append(result, total)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ExchangeName < result[j].ExchangeName })
+
+	return result, nil
+}
+
+// rate returns the price of one unit of from in to, directly or through the first bridge that prices both.
+func (s *conversionServiceImpl) rate(cache *rateCache, exchangeName, from, to string, at time.Time, bridges []string) (float64, error) {
+	if from == "" || to == "" {
+		return 0, entity.ErrNoConversionRate
+	}
+	if from == to {
+		return 1, nil
+	}
+
+	direct, err := s.directRate(cache, exchangeName, from, to, at)
+	if !errors.Is(err, entity.ErrNoConversionRate) {
+		return direct, err
+	}
+
+	for _, bridge := range bridges {
+		if bridge == "" || bridge == from || bridge == to {
+			continue
+		}
+		first, err := s.directRate(cache, exchangeName, from, bridge, at)
+		if errors.Is(err, entity.ErrNoConversionRate) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		second, err := s.directRate(cache, exchangeName, bridge, to, at)
+		if errors.Is(err, entity.ErrNoConversionRate) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		return first * second, nil
+	}
+
+	return 0, entity.ErrNoConversionRate
+}
+
+/*
+directRate prices from in to with the latest book of the pair in either direction taken before at and within maxRateAge,
+on the exchange first and then on any exchange.
+*/
+func (s *conversionServiceImpl) directRate(cache *rateCache, exchangeName, from, to string, at time.Time) (float64, error) {
+	exchanges := []string{exchangeName}
+	if exchangeName != "" {
+		exchanges = append(exchanges, "")
+	}
+
+	for _, exchange := range exchanges {
+		rates, err := s.loadRates(cache, exchange, from, to)
+		if err != nil {
+			return 0, err
+		}
+		if rate, ok := rates[at.UnixMilli()]; ok {
+			return rate, nil
+		}
+	}
+
+	return 0, entity.ErrNoConversionRate
+}
+
+/*
+loadRates returns the rates of from in to on an exchange, or on every exchange if it is empty,
+as of every time of cache, keyed by that time in milliseconds.
+*/
+func (s *conversionServiceImpl) loadRates(cache *rateCache, exchange, from, to string) (map[int64]float64, error) {
+	key := rateKey{exchange, from, to}
+	if rates, ok := cache.rates[key]; ok {
+		return rates, nil
+	}
+
+	pairs := append(entity.PairSpellings(from, to), entity.PairSpellings(to, from)...)
+	mids, err := s.repo.GetMidsAsOf(exchange, pairs, cache.times, maxRateAge)
+	if err != nil && !errors.Is(err, entity.ErrNotFound) {
+		return nil, err
+	}
+
+	rates := make(map[int64]float64, len(mids))
+	for _, m := range mids {
+		if m.Mid <= 0 {
+			continue
+		}
+		rate := m.Mid
+		if base, _, _ := entity.SplitPair(m.Pair); base != from {
+			rate = 1 / rate
+		}
+		rates[m.At.UnixMilli()] = rate
+	}
+	cache.rates[key] = rates
+
+	return rates, nil
+}
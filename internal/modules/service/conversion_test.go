@@ -0,0 +1,111 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// pairsOf matches the pair spellings looked up for two assets in either direction.
+func pairsOf(from, to string) []string {
+	return append(entity.PairSpellings(from, to), entity.PairSpellings(to, from)...)
+}
+
+// rateAt is the mid of a book of pair taken at taken, looked up as of at.
+func rateAt(exchange, pair string, mid float64, at, taken time.Time) *entity.BookMid {
+	return &entity.BookMid{At: at, Exchange: exchange, Pair: pair, Timestamp: taken, Mid: mid}
+}
+
+func TestConvert(t *testing.T) {
+	mockRepo := new(mocks.MockConversionRepository)
+	mockService := NewConversionService(mockRepo, new(mocks.MockAnalyticsRepository))
+
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	times := []time.Time{at}
+	none := []*entity.BookMid(nil)
+
+	// BNB is only quoted in USDT, on another exchange, and USDT only against USD.
+	mockRepo.On("GetMidsAsOf", "exchange1", pairsOf("BNB", "USD"), times, maxRateAge).Return(none, entity.ErrNotFound)
+	mockRepo.On("GetMidsAsOf", "", pairsOf("BNB", "USD"), times, maxRateAge).Return(none, entity.ErrNotFound)
+	mockRepo.On("GetMidsAsOf", "exchange1", pairsOf("BNB", "USDT"), times, maxRateAge).Return(none, entity.ErrNotFound)
+	mockRepo.On("GetMidsAsOf", "", pairsOf("BNB", "USDT"), times, maxRateAge).Return([]*entity.BookMid{rateAt("exchange2", "BNB/USDT", 500, at, at.Add(-time.Minute))}, nil)
+	mockRepo.On("GetMidsAsOf", "exchange1", pairsOf("USDT", "USD"), times, maxRateAge).Return([]*entity.BookMid{rateAt("exchange1", "usd-usdt", 1.25, at, at)}, nil)
+
+	value, err := mockService.Convert("exchange1", "BNB", "USD", 2, at)
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*500/1.25, value, 1e-9)
+
+	// No book prices XYZ within maxRateAge.
+	mockRepo.On("GetMidsAsOf", mock.Anything, mock.Anything, times, maxRateAge).Return(none, entity.ErrNotFound)
+
+	_, err = mockService.Convert("exchange1", "XYZ", "USD", 1, at)
+
+	assert.ErrorIs(t, err, entity.ErrNoConversionRate)
+}
+
+func TestConvertAllLoadsEachPairOnce(t *testing.T) {
+	mockRepo := new(mocks.MockConversionRepository)
+	mockService := NewConversionService(mockRepo, new(mocks.MockAnalyticsRepository))
+
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	later := at.Add(time.Hour)
+
+	// Both conversions look the pair up in one query, as of each of their times.
+	mockRepo.On("GetMidsAsOf", "exchange1", pairsOf("BNB", "USDT"), []time.Time{at, later}, maxRateAge).Return([]*entity.BookMid{
+		rateAt("exchange1", "BNB/USDT", 400, at, at),
+		rateAt("exchange1", "BNB/USDT", 500, later, later),
+	}, nil).Once()
+
+	conversions := []*entity.Conversion{
+		{ExchangeName: "exchange1", Asset: "BNB", Currency: "USDT", Qty: 1, At: at},
+		{ExchangeName: "exchange1", Asset: "BNB", Currency: "USDT", Qty: 1, At: later},
+	}
+	err := mockService.ConvertAll(conversions)
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 400.0, conversions[0].Value, 1e-9)
+	assert.InDelta(t, 500.0, conversions[1].Value, 1e-9)
+	mockRepo.AssertNumberOfCalls(t, "GetMidsAsOf", 1)
+}
+
+func TestGetConvertedCommissions(t *testing.T) {
+	mockRepo := new(mocks.MockConversionRepository)
+	mockAnalytics := new(mocks.MockAnalyticsRepository)
+	mockService := NewConversionService(mockRepo, mockAnalytics)
+
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fills := []*entity.HistoryOrder{
+		// Charged 0.5 USDT on 1000 USDT of notional.
+		{ExchangeName: "exchange1", Pair: "BTC/USDT", BaseQty: 0.01, Price: 100000, CommissionQuoteQty: 0.5, TimePlaced: at},
+		// Charged 0.001 BNB, worth 0.5 USDT, on 1000 USDT of notional.
+		{ExchangeName: "exchange2", Pair: "BTC/USDT", BaseQty: 0.01, Price: 100000, CommissionAsset: "bnb", CommissionQty: 0.001, TimePlaced: at},
+		// Charged in a token without a book.
+		{ExchangeName: "exchange2", Pair: "BTC/USDT", BaseQty: 0.01, Price: 100000, CommissionAsset: "XYZ", CommissionQty: 1, TimePlaced: at},
+	}
+	req := &entity.CommissionConversionRequest{Currency: "USDT"}
+
+	mockAnalytics.On("GetHistoryOrders", &req.HistoryFilter).Return(fills, nil)
+	mockRepo.On("GetMidsAsOf", "exchange2", pairsOf("BNB", "USDT"), []time.Time{at}, maxRateAge).Return([]*entity.BookMid{rateAt("exchange2", "BNB/USDT", 500, at, at)}, nil)
+	mockRepo.On("GetMidsAsOf", mock.Anything, mock.Anything, []time.Time{at}, maxRateAge).Return([]*entity.BookMid(nil), entity.ErrNotFound)
+
+	result, err := mockService.GetConvertedCommissions(req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	assert.Equal(t, "exchange1", result[0].ExchangeName)
+	assert.InDelta(t, 5.0, result[0].FeeRateBps, 1e-9)
+
+	assert.Equal(t, "exchange2", result[1].ExchangeName)
+	assert.Equal(t, 2, result[1].Fills)
+	assert.Equal(t, 1, result[1].Unpriced)
+	assert.Equal(t, []string{"XYZ"}, result[1].UnpricedAssets)
+	assert.InDelta(t, 0.5, result[1].Commission, 1e-9)
+	assert.InDelta(t, 1000.0, result[1].Notional, 1e-9)
+	assert.InDelta(t, 5.0, result[1].FeeRateBps, 1e-9)
+}
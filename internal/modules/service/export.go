@@ -0,0 +1,177 @@
+package service
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+)
+
+type ExportService interface {
+	ExportOrderBook(req *entity.OrderBookRequest, opts *entity.ExportOptions, w io.Writer) error
+	ExportOrderHistory(filter *entity.HistoryFilter, opts *entity.ExportOptions, w io.Writer) error
+}
+
+type exportServiceImpl struct {
+	repo repository.ExportRepository
+}
+
+func NewExportService(repo repository.ExportRepository) ExportService {
+	return &exportServiceImpl{repo: repo}
+}
+
+var historyOrderColumns = []column{
+	{"order_id", stringColumn},
+	{"exchange_order_id", stringColumn},
+	{"fill_id", stringColumn},
+	{"dedup_key", stringColumn},
+	{"client_name", stringColumn},
+	{"exchange_name", stringColumn},
+	{"label", stringColumn},
+	{"pair", stringColumn},
+	{"side", stringColumn},
+	{"type", stringColumn},
+	{"base_qty", float64Column},
+	{"price", float64Column},
+	{"algorithm_name_placed", stringColumn},
+	{"lowest_sell_prc", float64Column},
+	{"highest_buy_prc", float64Column},
+	{"commission_quote_qty", float64Column},
+	{"commission_asset", stringColumn},
+	{"commission_qty", float64Column},
+	{"time_placed", timeColumn},
+}
+
+var orderBookColumns = []column{
+	{"id", int64Column},
+	{"exchange", stringColumn},
+	{"pair", stringColumn},
+	{"timestamp", timeColumn},
+	{"sequence", int64Column},
+}
+
+/*
+ExportOrderHistory writes the fills matching the filter to w as CSV or Parquet, one row per fill, oldest first.
+Nothing is written when no fill matches.
+Also returns an error if one occures.
+*/
+
+func (s *exportServiceImpl) ExportOrderHistory(filter *entity.HistoryFilter, opts *entity.ExportOptions, w io.Writer) error {
+	var table tableWriter
+	err := s.repo.StreamOrderHistory(filter, func(o *entity.HistoryOrder) error {
+		if table == nil {
+			var err error
+			if table, err = newTableWriter(opts.Format, w, historyOrderColumns); err != nil {
+				return err
+			}
+		}
+
+		return table.WriteRow([]any{
+			o.OrderID, o.ExchangeOrderID, o.FillID, o.DedupKey, o.ClientName, o.ExchangeName, o.Label, o.Pair,
+			string(o.Side), string(o.Type), o.BaseQty, o.Price, o.AlgorithmNamePlaced,
+			o.LowestSellPrc, o.HighestBuyPrc, o.CommissionQuoteQty, o.CommissionAsset, o.CommissionQty, o.TimePlaced,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return table.Close()
+}
+
+/*
+ExportOrderBook writes the order book snapshots of an exchange and trading pair to w as CSV or Parquet.
+The levels layout writes one row per snapshot with opts.Depth price and quantity columns per side,
+the rows layout writes one row per price level.
+Nothing is written when there are no snapshots.
+Also returns an error if one occures.
+*/
+
+func (s *exportServiceImpl) ExportOrderBook(req *entity.OrderBookRequest, opts *entity.ExportOptions, w io.Writer) error {
+	columns, toRows := orderBookLevels(opts.Depth)
+	if opts.Layout == entity.BookLayoutRows {
+		columns, toRows = orderBookRows()
+	}
+
+	var table tableWriter
+	err := s.repo.StreamOrderBook(req.Exchange_name, req.Pair, func(ob *entity.OrderBook) error {
+		if table == nil {
+			var err error
+			if table, err = newTableWriter(opts.Format, w, columns); err != nil {
+				return err
+			}
+		}
+
+		for _, row := range toRows(ob) {
+			if err := table.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return table.Close()
+}
+
+func orderBookValues(ob *entity.OrderBook) []any {
+	return []any{ob.ID, ob.Exchange, ob.Pair, ob.Timestamp, ob.Sequence}
+}
+
+// orderBookLevels flattens the first depth levels of each side into columns. Missing levels are left empty.
+func orderBookLevels(depth int) ([]column, func(*entity.OrderBook) [][]any) {
+	columns := append([]column{}, orderBookColumns...)
+	for _, side := range []string{"ask", "bid"} {
+		for level := 1; level <= depth; level++ {
+			n := strconv.Itoa(level)
+			columns = append(columns,
+				column{side + "_price_" + n, float64Column},
+				column{side + "_qty_" + n, float64Column},
+			)
+		}
+	}
+
+	toRows := func(ob *entity.OrderBook) [][]any {
+		row := orderBookValues(ob)
+		for _, levels := range [][]entity.DepthOrder{ob.Asks, ob.Bids} {
+			for level := 0; level < depth; level++ {
+				if level < len(levels) {
+					row = append(row, levels[level].Price, levels[level].BaseQty)
+				} else {
+					row = append(row, nil, nil)
+				}
+			}
+		}
+		return [][]any{row}
+	}
+
+	return columns, toRows
+}
+
+// orderBookRows explodes every level of a snapshot into its own row.
+func orderBookRows() ([]column, func(*entity.OrderBook) [][]any) {
+	columns := append(append([]column{}, orderBookColumns...),
+		column{"side", stringColumn},
+		column{"level", int64Column},
+		column{"price", float64Column},
+		column{"base_qty", float64Column},
+	)
+
+	toRows := func(ob *entity.OrderBook) [][]any {
+		rows := make([][]any, 0, len(ob.Asks)+len(ob.Bids))
+		for _, side := range []struct {
+			name   string
+			levels []entity.DepthOrder
+		}{{"ask", ob.Asks}, {"bid", ob.Bids}} {
+			for i, l := range side.levels {
+				rows = append(rows, append(orderBookValues(ob), side.name, int64(i+1), l.Price, l.BaseQty))
+			}
+		}
+		return rows
+	}
+
+	return columns, toRows
+}
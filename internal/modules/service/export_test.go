@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func streamBooks(books ...*entity.OrderBook) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		fn := args.Get(2).(func(*entity.OrderBook) error)
+		for _, b := range books {
+			fn(b)
+		}
+	}
+}
+
+func TestExportOrderHistory(t *testing.T) {
+	mockRepo := new(mocks.MockExportRepository)
+	mockService := NewExportService(mockRepo)
+
+	filter := &entity.HistoryFilter{ClientName: "client1", ExchangeName: "exchange1", Labels: []string{"label1"}, Pairs: []string{"pair1"}}
+	order := &entity.HistoryOrder{
+		OrderID:    "o1",
+		ClientName: "client1",
+		Pair:       "pair1",
+		Side:       entity.SideBuy,
+		Type:       entity.OrderTypeLimit,
+		BaseQty:    1.5,
+		Price:      100,
+		TimePlaced: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	mockRepo.On("StreamOrderHistory", filter, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(func(*entity.HistoryOrder) error)(order)
+	}).Return(nil)
+
+	var buf bytes.Buffer
+	err := mockService.ExportOrderHistory(filter, &entity.ExportOptions{Format: entity.ExportFormatCSV}, &buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"order_id,exchange_order_id,fill_id,dedup_key,client_name,exchange_name,label,pair,side,type,base_qty,price,"+
+			"algorithm_name_placed,lowest_sell_prc,highest_buy_prc,commission_quote_qty,commission_asset,commission_qty,time_placed\n"+
+			"o1,,,,client1,,,pair1,buy,limit,1.5,100,,0,0,0,,0,2024-05-01T12:00:00Z\n",
+		buf.String())
+}
+
+func TestExportOrderHistory_NotFound(t *testing.T) {
+	mockRepo := new(mocks.MockExportRepository)
+	mockService := NewExportService(mockRepo)
+
+	mockRepo.On("StreamOrderHistory", mock.Anything, mock.Anything).Return(entity.ErrNotFound)
+
+	var buf bytes.Buffer
+	err := mockService.ExportOrderHistory(&entity.HistoryFilter{}, &entity.ExportOptions{Format: entity.ExportFormatCSV}, &buf)
+
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Zero(t, buf.Len())
+}
+
+func TestExportOrderBook(t *testing.T) {
+	book := &entity.OrderBook{
+		ID:        1,
+		Exchange:  "exchange1",
+		Pair:      "pair1",
+		Asks:      []entity.DepthOrder{{Price: 101, BaseQty: 1}, {Price: 102, BaseQty: 2}},
+		Bids:      []entity.DepthOrder{{Price: 99, BaseQty: 3}},
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Sequence:  7,
+	}
+	req := &entity.OrderBookRequest{Exchange_name: "exchange1", Pair: "pair1"}
+
+	tests := []struct {
+		name     string
+		opts     entity.ExportOptions
+		expected string
+	}{
+		{
+			name: "levels",
+			opts: entity.ExportOptions{Format: entity.ExportFormatCSV, Layout: entity.BookLayoutLevels, Depth: 2},
+			expected: "id,exchange,pair,timestamp,sequence,ask_price_1,ask_qty_1,ask_price_2,ask_qty_2,bid_price_1,bid_qty_1,bid_price_2,bid_qty_2\n" +
+				"1,exchange1,pair1,2024-05-01T12:00:00Z,7,101,1,102,2,99,3,,\n",
+		},
+		{
+			name: "rows",
+			opts: entity.ExportOptions{Format: entity.ExportFormatCSV, Layout: entity.BookLayoutRows},
+			expected: "id,exchange,pair,timestamp,sequence,side,level,price,base_qty\n" +
+				"1,exchange1,pair1,2024-05-01T12:00:00Z,7,ask,1,101,1\n" +
+				"1,exchange1,pair1,2024-05-01T12:00:00Z,7,ask,2,102,2\n" +
+				"1,exchange1,pair1,2024-05-01T12:00:00Z,7,bid,1,99,3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockExportRepository)
+			mockService := NewExportService(mockRepo)
+
+			mockRepo.On("StreamOrderBook", "exchange1", "pair1", mock.Anything).Run(streamBooks(book)).Return(nil)
+
+			var buf bytes.Buffer
+			err := mockService.ExportOrderBook(req, &tt.opts, &buf)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestExportOrderBook_Parquet(t *testing.T) {
+	mockRepo := new(mocks.MockExportRepository)
+	mockService := NewExportService(mockRepo)
+
+	book := &entity.OrderBook{
+		Exchange:  "exchange1",
+		Pair:      "pair1",
+		Asks:      []entity.DepthOrder{{Price: 101, BaseQty: 1}},
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	mockRepo.On("StreamOrderBook", "exchange1", "pair1", mock.Anything).Run(streamBooks(book, book)).Return(nil)
+
+	var buf bytes.Buffer
+	opts := &entity.ExportOptions{Format: entity.ExportFormatParquet, Layout: entity.BookLayoutLevels, Depth: 1}
+	err := mockService.ExportOrderBook(&entity.OrderBookRequest{Exchange_name: "exchange1", Pair: "pair1"}, opts, &buf)
+	assert.NoError(t, err)
+
+	type row struct {
+		Exchange  string    `parquet:"exchange,optional"`
+		Timestamp time.Time `parquet:"timestamp,optional,timestamp(millisecond)"`
+		AskPrice1 *float64  `parquet:"ask_price_1,optional"`
+		BidPrice1 *float64  `parquet:"bid_price_1,optional"`
+	}
+	rows, err := parquet.Read[row](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "exchange1", rows[0].Exchange)
+	assert.True(t, book.Timestamp.Equal(rows[0].Timestamp))
+	assert.Equal(t, 101.0, *rows[0].AskPrice1)
+	assert.Nil(t, rows[0].BidPrice1)
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+)
+
+/*
+feeVolumeCurrency is the currency tier volumes are measured in, so fills quoted in different assets add up.
+Fills of pairs whose quote asset is not known are counted at their notional, those that cannot be valued are left out.
+*/
+const feeVolumeCurrency = "USDT"
+
+type FeeService interface {
+	SetFeeSchedule(schedule entity.FeeSchedule) error
+	GetFeeSchedules(exchangeName string) ([]*entity.FeeSchedule, error)
+	ReconcileCommissions(req *entity.FeeReconciliationRequest) (*entity.FeeReconciliation, error)
+}
+
+type feeServiceImpl struct {
+	repo       repository.FeeRepository
+	analytics  repository.AnalyticsRepository
+	conversion ConversionService
+}
+
+func NewFeeService(repo repository.FeeRepository, analytics repository.AnalyticsRepository, conversion ConversionService) FeeService {
+	return &feeServiceImpl{repo: repo, analytics: analytics, conversion: conversion}
+}
+
+/*
+SetFeeSchedule saves the fee schedule of an exchange, or a client override of it.
+Returns an error if one occures.
+*/
+
+func (s *feeServiceImpl) SetFeeSchedule(schedule entity.FeeSchedule) error {
+	schedule.UpdatedAt = time.Now()
+	return s.repo.SaveFeeSchedule(schedule)
+}
+
+/*
+GetFeeSchedules returns the fee schedules of an exchange, or of every exchange if exchangeName is empty.
+Also returns an error if one occures.
+*/
+
+func (s *feeServiceImpl) GetFeeSchedules(exchangeName string) ([]*entity.FeeSchedule, error) {
+	return s.repo.GetFeeSchedules(exchangeName)
+}
+
+/*
+ReconcileCommissions recomputes the commission of every filtered fill from the fee schedule in effect
+when it was placed and reports the fills charged differently.
+The tier is chosen by the notional the client traded on the exchange, across all pairs and valued in
+feeVolumeCurrency, over the schedule's volume window before the fill.
+Commissions charged in another asset than those of the pair are valued in quote at the order book prices of the time.
+Also returns an error if one occures.
+*/
+
+func (s *feeServiceImpl) ReconcileCommissions(req *entity.FeeReconciliationRequest) (*entity.FeeReconciliation, error) {
+	fills, err := s.analytics.GetHistoryOrders(&req.HistoryFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.repo.GetFeeSchedules(req.ExchangeName)
+	if err != nil && !errors.Is(err, entity.ErrNotFound) {
+		return nil, err
+	}
+
+	volumes, err := s.tradedVolumes(fills, schedules)
+	if err != nil {
+		return nil, err
+	}
+
+	commissions, err := s.quoteCommissions(fills, schedules)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.FeeReconciliation{Fills: len(fills), Discrepancies: []*entity.FeeDiscrepancy{}}
+	for i, f := range fills {
+		schedule := scheduleFor(schedules, f)
+		if schedule == nil {
+			result.Unscheduled++
+			continue
+		}
+
+		if commissions[i].Unpriced {
+			result.Unpriced++
+			continue
+		}
+		actual := commissions[i].Value
+
+		window := time.Duration(schedule.VolumeWindowDays) * 24 * time.Hour
+		volume := volumes[volumeKey{f.ClientName, f.ExchangeName}].before(f.TimePlaced, window)
+		d := reconcileFill(f, actual, schedule.Tier(volume), volume)
+
+		if math.Abs(d.Difference) > d.Notional*req.ToleranceBps/10000 {
+			if d.Difference > 0 {
+				result.Overcharged += d.Difference
+			} else {
+				result.Undercharged -= d.Difference
+			}
+			result.Discrepancies = append(result.Discrepancies, d)
+		}
+	}
+
+	return result, nil
+}
+
+/*
+quoteCommissions values the commission of every scheduled fill in the quote asset of its pair,
+converting those charged in another asset in one batch.
+*/
+func (s *feeServiceImpl) quoteCommissions(fills []*entity.HistoryOrder, schedules []*entity.FeeSchedule) ([]*entity.Conversion, error) {
+	commissions := make([]*entity.Conversion, len(fills))
+	var conversions []*entity.Conversion
+	for i, f := range fills {
+		_, quote, _ := entity.SplitPair(f.Pair)
+		if f.HasQuoteCommission() || scheduleFor(schedules, f) == nil {
+			commissions[i] = &entity.Conversion{Asset: quote, Currency: quote, Qty: f.CommissionQuoteQty, Value: f.CommissionQuoteQty}
+			continue
+		}
+
+		asset, qty := f.Commission()
+		commissions[i] = &entity.Conversion{ExchangeName: f.ExchangeName, Pair: f.Pair, Asset: asset, Currency: quote, Qty: qty, At: f.TimePlaced}
+		conversions = append(conversions, commissions[i])
+	}
+
+	if err := s.conversion.ConvertAll(conversions); err != nil {
+		return nil, err
+	}
+	return commissions, nil
+}
+
+// tradedVolumes loads the fills needed to know the trailing volume of every client and exchange in fills.
+func (s *feeServiceImpl) tradedVolumes(fills []*entity.HistoryOrder, schedules []*entity.FeeSchedule) (map[volumeKey]*volumeSeries, error) {
+	if len(schedules) == 0 {
+		return nil, nil
+	}
+
+	longest := 0
+	for _, sc := range schedules {
+		longest = max(longest, sc.VolumeWindowDays)
+	}
+
+	bounds := make(map[volumeKey][2]time.Time)
+	for _, f := range fills {
+		key := volumeKey{f.ClientName, f.ExchangeName}
+		b, ok := bounds[key]
+		if !ok || f.TimePlaced.Before(b[0]) {
+			b[0] = f.TimePlaced
+		}
+		if !ok || f.TimePlaced.After(b[1]) {
+			b[1] = f.TimePlaced
+		}
+		bounds[key] = b
+	}
+
+	histories := make(map[volumeKey][]*entity.Conversion, len(bounds))
+	var conversions []*entity.Conversion
+	for key, b := range bounds {
+		history, err := s.analytics.GetHistoryOrders(&entity.HistoryFilter{
+			ClientName:   key.client,
+			ExchangeName: key.exchange,
+			From:         b[0].Add(-time.Duration(longest) * 24 * time.Hour),
+			To:           b[1],
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range history {
+			notional := f.BaseQty * f.Price
+			c := &entity.Conversion{ExchangeName: f.ExchangeName, Pair: f.Pair, Currency: feeVolumeCurrency, Qty: notional, At: f.TimePlaced}
+			if _, quote, ok := entity.SplitPair(f.Pair); ok {
+				c.Asset = quote
+				conversions = append(conversions, c)
+			} else {
+				c.Value = notional
+			}
+			histories[key] = append(histories[key], c)
+		}
+	}
+
+	if len(conversions) > 0 {
+		if err := s.conversion.ConvertAll(conversions); err != nil {
+			return nil, err
+		}
+	}
+
+	volumes := make(map[volumeKey]*volumeSeries, len(histories))
+	for key, history := range histories {
+		volumes[key] = newVolumeSeries(history)
+	}
+
+	return volumes, nil
+}
+
+/*
+scheduleFor returns the schedule in effect for a fill: the latest one that took effect before it,
+preferring a client override to the exchange's schedule. It returns nil if there is none.
+*/
+func scheduleFor(schedules []*entity.FeeSchedule, fill *entity.HistoryOrder) *entity.FeeSchedule {
+	var exchange, client *entity.FeeSchedule
+	for _, sc := range schedules {
+		if sc.ExchangeName != fill.ExchangeName || sc.EffectiveFrom.After(fill.TimePlaced) {
+			continue
+		}
+		switch sc.ClientName {
+		case "":
+			if exchange == nil || sc.EffectiveFrom.After(exchange.EffectiveFrom) {
+				exchange = sc
+			}
+		case fill.ClientName:
+			if client == nil || sc.EffectiveFrom.After(client.EffectiveFrom) {
+				client = sc
+			}
+		}
+	}
+
+	if client != nil {
+		return client
+	}
+	return exchange
+}
+
+func reconcileFill(f *entity.HistoryOrder, actual float64, tier entity.FeeTier, volume float64) *entity.FeeDiscrepancy {
+	notional := f.BaseQty * f.Price
+	maker := notional * tier.MakerBps / 10000
+	taker := notional * tier.TakerBps / 10000
+
+	liquidity := f.Type.Liquidity()
+	var expected float64
+	switch liquidity {
+	case entity.LiquidityMaker:
+		expected = maker
+	case entity.LiquidityTaker:
+		expected = taker
+	default:
+		expected = math.Min(math.Max(actual, math.Min(maker, taker)), math.Max(maker, taker))
+	}
+
+	difference := actual - expected
+	return &entity.FeeDiscrepancy{
+		OrderID:            f.OrderID,
+		DedupKey:           f.DedupKey,
+		ClientName:         f.ClientName,
+		ExchangeName:       f.ExchangeName,
+		Pair:               f.Pair,
+		Type:               f.Type,
+		Liquidity:          liquidity,
+		TimePlaced:         f.TimePlaced,
+		Notional:           notional,
+		TierVolume:         volume,
+		ExpectedCommission: expected,
+		ActualCommission:   actual,
+		Difference:         difference,
+		DifferenceBps:      feeRateBps(difference, notional),
+	}
+}
+
+type volumeKey struct{ client, exchange string }
+
+// volumeSeries answers trailing volume queries with cumulative notionals of fills in time order.
+type volumeSeries struct {
+	times      []time.Time
+	cumulative []float64
+}
+
+// newVolumeSeries builds the series of the notionals of fills valued in feeVolumeCurrency, leaving out those that could not be.
+func newVolumeSeries(notionals []*entity.Conversion) *volumeSeries {
+	sorted := make([]*entity.Conversion, 0, len(notionals))
+	for _, n := range notionals {
+		if !n.Unpriced {
+			sorted = append(sorted, n)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+
+	v := &volumeSeries{
+		times:      make([]time.Time, len(sorted)),
+		cumulative: make([]float64, len(sorted)+1),
+	}
+	for i, n := range sorted {
+		v.times[i] = n.At
+		v.cumulative[i+1] = v.cumulative[i] + n.Value
+	}
+	return v
+}
+
+// before returns the notional traded in [t-window, t).
+func (v *volumeSeries) before(t time.Time, window time.Duration) float64 {
+	from := sort.Search(len(v.times), func(i int) bool { return !v.times[i].Before(t.Add(-window)) })
+	to := sort.Search(len(v.times), func(i int) bool { return !v.times[i].Before(t) })
+	if to <= from {
+		return 0
+	}
+	return v.cumulative[to] - v.cumulative[from]
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReconcileCommissions(t *testing.T) {
+	mockFees := new(mocks.MockFeeRepository)
+	mockAnalytics := new(mocks.MockAnalyticsRepository)
+	mockConversion := new(mocks.MockConversionService)
+	mockService := NewFeeService(mockFees, mockAnalytics, mockConversion)
+
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	schedules := []*entity.FeeSchedule{
+		{
+			ExchangeName:     "exchange1",
+			EffectiveFrom:    start.AddDate(-1, 0, 0),
+			VolumeWindowDays: 30,
+			Tiers: []entity.FeeTier{
+				{MinVolume: 0, MakerBps: 2, TakerBps: 5},
+				{MinVolume: 10000, MakerBps: -1, TakerBps: 4},
+			},
+		},
+		{
+			ExchangeName:     "exchange1",
+			ClientName:       "vip",
+			EffectiveFrom:    start.AddDate(-1, 0, 0),
+			VolumeWindowDays: 30,
+			Tiers:            []entity.FeeTier{{MinVolume: 0, MakerBps: 0, TakerBps: 1}},
+		},
+	}
+
+	fill := func(client string, minutes int, typ entity.OrderType, notional, commission float64) *entity.HistoryOrder {
+		return &entity.HistoryOrder{
+			OrderID:            client + "-" + string(typ),
+			ClientName:         client,
+			ExchangeName:       "exchange1",
+			Pair:               "pair1",
+			Type:               typ,
+			BaseQty:            1,
+			Price:              notional,
+			CommissionQuoteQty: commission,
+			TimePlaced:         start.Add(time.Duration(minutes) * time.Minute),
+		}
+	}
+
+	native := func(minutes int, asset string, qty float64) *entity.HistoryOrder {
+		f := fill("vip", minutes, entity.OrderTypeLimit, 1000, 0)
+		f.OrderID, f.CommissionAsset, f.CommissionQty = "vip-"+asset, asset, qty
+		return f
+	}
+
+	// A fill 20 days earlier puts client1 in the second tier.
+	earlier := fill("client1", -60*24*20, entity.OrderTypeLimit, 10000, 3)
+	fills := []*entity.HistoryOrder{
+		fill("client1", 0, entity.OrderTypeMarket, 1000, 0.5),                 // taker at 4 bps is 0.4, overcharged by 0.1
+		fill("client1", 1, entity.OrderTypePostOnly, 1000, -0.1),              // maker rebate of 1 bps, matches
+		fill("client1", 2, entity.OrderTypeLimit, 1000, 0.2),                  // anywhere between -0.1 and 0.4 matches
+		fill("vip", 0, entity.OrderTypeMarket, 1000, 0.05),                    // override taker of 1 bps is 0.1, undercharged by 0.05
+		{ClientName: "client1", ExchangeName: "exchange2", TimePlaced: start}, // no schedule
+		native(1, "BNB", 0.0002),                                              // worth 0.1 in quote, matches
+		native(2, "XYZ", 1),                                                   // cannot be valued
+	}
+	req := &entity.FeeReconciliationRequest{HistoryFilter: entity.HistoryFilter{From: start}, ToleranceBps: 0.1}
+
+	mockAnalytics.On("GetHistoryOrders", &req.HistoryFilter).Return(fills, nil)
+	mockFees.On("GetFeeSchedules", "").Return(schedules, nil)
+	mockAnalytics.On("GetHistoryOrders", mock.MatchedBy(func(f *entity.HistoryFilter) bool {
+		return f.ClientName == "client1" && f.ExchangeName == "exchange1" && f.From.Equal(start.AddDate(0, 0, -30))
+	})).Return(append([]*entity.HistoryOrder{earlier}, fills[:3]...), nil)
+	mockAnalytics.On("GetHistoryOrders", mock.MatchedBy(func(f *entity.HistoryFilter) bool {
+		return f.ClientName == "vip"
+	})).Return(fills[3:4], nil)
+	mockAnalytics.On("GetHistoryOrders", mock.MatchedBy(func(f *entity.HistoryFilter) bool {
+		return f.ExchangeName == "exchange2"
+	})).Return(fills[4:5], nil)
+	mockConversion.On("ConvertAll", mock.MatchedBy(func(c []*entity.Conversion) bool {
+		return len(c) == 2 && c[0].Asset == "BNB" && c[0].Qty == 0.0002 && c[1].Asset == "XYZ"
+	})).Run(func(args mock.Arguments) {
+		c := args.Get(0).([]*entity.Conversion)
+		c[0].Value = 0.1
+		c[1].Unpriced = true
+	}).Return(nil)
+
+	result, err := mockService.ReconcileCommissions(req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, result.Fills)
+	assert.Equal(t, 1, result.Unscheduled)
+	assert.Equal(t, 1, result.Unpriced)
+	assert.Len(t, result.Discrepancies, 2)
+
+	over := result.Discrepancies[0]
+	assert.Equal(t, "client1-market", over.OrderID)
+	assert.Equal(t, entity.LiquidityTaker, over.Liquidity)
+	assert.Equal(t, 10000.0, over.TierVolume)
+	assert.InDelta(t, 0.4, over.ExpectedCommission, 1e-9)
+	assert.InDelta(t, 1.0, over.DifferenceBps, 1e-9)
+
+	under := result.Discrepancies[1]
+	assert.Equal(t, "vip-market", under.OrderID)
+	assert.InDelta(t, -0.05, under.Difference, 1e-9)
+
+	assert.InDelta(t, 0.1, result.Overcharged, 1e-9)
+	assert.InDelta(t, 0.05, result.Undercharged, 1e-9)
+}
+
+func TestFeeScheduleTier(t *testing.T) {
+	schedule := &entity.FeeSchedule{Tiers: []entity.FeeTier{{MinVolume: 0, TakerBps: 5}, {MinVolume: 100, TakerBps: 4}, {MinVolume: 1000, TakerBps: 3}}}
+
+	assert.Equal(t, 5.0, schedule.Tier(99).TakerBps)
+	assert.Equal(t, 4.0, schedule.Tier(100).TakerBps)
+	assert.Equal(t, 3.0, schedule.Tier(5000).TakerBps)
+}
+
+func TestTradedVolumes_QuoteCurrencies(t *testing.T) {
+	mockAnalytics := new(mocks.MockAnalyticsRepository)
+	mockConversion := new(mocks.MockConversionService)
+	svc := &feeServiceImpl{analytics: mockAnalytics, conversion: mockConversion}
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fill := &entity.HistoryOrder{ClientName: "client1", ExchangeName: "exchange1", Pair: "ETH/BTC", BaseQty: 10, Price: 0.05, TimePlaced: start}
+	history := []*entity.HistoryOrder{
+		{ClientName: "client1", ExchangeName: "exchange1", Pair: "BTC/USDT", BaseQty: 0.01, Price: 100000, TimePlaced: start.Add(-time.Hour)},
+		{ClientName: "client1", ExchangeName: "exchange1", Pair: "ETH/BTC", BaseQty: 2, Price: 0.05, TimePlaced: start.Add(-time.Minute)},
+		{ClientName: "client1", ExchangeName: "exchange1", Pair: "XYZ/ABC", BaseQty: 1, Price: 1, TimePlaced: start.Add(-time.Minute)},
+	}
+	schedules := []*entity.FeeSchedule{{ExchangeName: "exchange1", VolumeWindowDays: 1}}
+
+	mockAnalytics.On("GetHistoryOrders", mock.Anything).Return(history, nil)
+	// 0.1 BTC is worth 6000 USDT, ABC cannot be valued.
+	mockConversion.On("ConvertAll", mock.MatchedBy(func(c []*entity.Conversion) bool {
+		return len(c) == 3 && c[0].Currency == "USDT"
+	})).Run(func(args mock.Arguments) {
+		for _, c := range args.Get(0).([]*entity.Conversion) {
+			switch c.Asset {
+			case "USDT":
+				c.Value = c.Qty
+			case "BTC":
+				c.Value = c.Qty * 60000
+			default:
+				c.Unpriced = true
+			}
+		}
+	}).Return(nil)
+
+	volumes, err := svc.tradedVolumes([]*entity.HistoryOrder{fill}, schedules)
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 1000+6000, volumes[volumeKey{"client1", "exchange1"}].before(start, 24*time.Hour), 1e-9)
+}
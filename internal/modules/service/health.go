@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+)
+
+// healthCheckTimeout bounds each query against the database made to check health.
+const healthCheckTimeout = 2 * time.Second
+
+// IngestQueue counts the order books collectors have sent that are not saved yet. It is safe for concurrent use.
+type IngestQueue struct {
+	pending  atomic.Int64
+	capacity int
+}
+
+// NewIngestQueue returns a queue that is saturated once capacity books are pending.
+func NewIngestQueue(capacity int) *IngestQueue {
+	return &IngestQueue{capacity: capacity}
+}
+
+// Add counts n received books.
+func (q *IngestQueue) Add(n int) {
+	q.pending.Add(int64(n))
+}
+
+// Done counts n books saved or given up on.
+func (q *IngestQueue) Done(n int) {
+	q.pending.Add(-int64(n))
+}
+
+func (q *IngestQueue) Status() entity.IngestStatus {
+	return entity.IngestStatus{Pending: int(q.pending.Load()), Capacity: q.capacity}
+}
+
+type HealthService interface {
+	Ready(ctx context.Context) *entity.Readiness
+	Status(ctx context.Context) *entity.ServiceStatus
+}
+
+type healthServiceImpl struct {
+	repo      repository.HealthRepository
+	queue     *IngestQueue
+	tables    []string
+	columns   []entity.Column
+	version   string
+	startedAt time.Time
+}
+
+// NewHealthService returns a service reporting the service ready once tables and columns exist and while queue is not saturated.
+func NewHealthService(repo repository.HealthRepository, queue *IngestQueue, tables []string, columns []entity.Column, version string) HealthService {
+	return &healthServiceImpl{repo: repo, queue: queue, tables: tables, columns: columns, version: version, startedAt: time.Now()}
+}
+
+/*
+Ready checks that ClickHouse can be reached, that the migrations created every table and column
+and that the ingestion queue is not saturated.
+Its messages never carry database errors, which only Status reports.
+*/
+
+func (s *healthServiceImpl) Ready(ctx context.Context) *entity.Readiness {
+	readiness, _ := s.check(ctx)
+	return readiness
+}
+
+/*
+Status returns the version and uptime of the service, the readiness checks,
+the latency of a ping to ClickHouse and the row counts of its tables.
+*/
+
+func (s *healthServiceImpl) Status(ctx context.Context) *entity.ServiceStatus {
+	readiness, database := s.check(ctx)
+	return &entity.ServiceStatus{
+		Version:       s.version,
+		StartedAt:     s.startedAt,
+		UptimeSeconds: time.Since(s.startedAt).Seconds(),
+		Readiness:     *readiness,
+		Database:      *database,
+		Ingest:        s.queue.Status(),
+	}
+}
+
+func (s *healthServiceImpl) check(ctx context.Context) (*entity.Readiness, *entity.DatabaseStatus) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	database := &entity.DatabaseStatus{Tables: []entity.TableStatus{}}
+	start := time.Now()
+	err := s.repo.Ping(ctx)
+	database.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	database.Reachable = err == nil
+
+	var columns []entity.Column
+	if err == nil {
+		database.Tables, err = s.repo.GetTables(ctx)
+	}
+	if err == nil {
+		columns, err = s.repo.GetColumns(ctx)
+	}
+	if err != nil {
+		database.Error = err.Error()
+	}
+
+	clickhouse := entity.Check{Name: "clickhouse", OK: database.Reachable}
+	if !clickhouse.OK {
+		clickhouse.Message = "database unreachable"
+	}
+	checks := []entity.Check{
+		clickhouse,
+		s.checkMigrations(database, columns),
+		s.checkIngest(),
+	}
+
+	readiness := &entity.Readiness{Ready: true, Checks: checks}
+	for _, c := range checks {
+		readiness.Ready = readiness.Ready && c.OK
+	}
+	return readiness, database
+}
+
+func (s *healthServiceImpl) checkMigrations(database *entity.DatabaseStatus, columns []entity.Column) entity.Check {
+	check := entity.Check{Name: "migrations"}
+	if !database.Reachable {
+		check.Message = "database unreachable"
+		return check
+	}
+	if database.Error != "" {
+		check.Message = "schema could not be read"
+		return check
+	}
+
+	var missing []string
+	for _, table := range s.tables {
+		if !slices.ContainsFunc(database.Tables, func(t entity.TableStatus) bool { return t.Name == table }) {
+			missing = append(missing, table)
+		}
+	}
+	if len(missing) > 0 {
+		check.Message = fmt.Sprintf("missing tables %v", missing)
+		return check
+	}
+
+	var outdated []string
+	for _, column := range s.columns {
+		if !slices.Contains(columns, column) {
+			outdated = append(outdated, column.Table+"."+column.Name)
+		}
+	}
+	if len(outdated) > 0 {
+		check.Message = fmt.Sprintf("missing or outdated columns %v", outdated)
+		return check
+	}
+
+	check.OK = true
+	return check
+}
+
+func (s *healthServiceImpl) checkIngest() entity.Check {
+	status := s.queue.Status()
+	if status.Pending >= status.Capacity {
+		return entity.Check{Name: "ingest", Message: fmt.Sprintf("%d order books waiting to be saved, at most %d allowed", status.Pending, status.Capacity)}
+	}
+	return entity.Check{Name: "ingest", OK: true}
+}
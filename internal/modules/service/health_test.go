@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReady(t *testing.T) {
+	mockRepo := new(mocks.MockHealthRepository)
+	queue := NewIngestQueue(2)
+	columns := []entity.Column{{Table: "history_orders", Name: "side", Type: "Enum8('buy' = 1, 'sell' = 2)"}}
+	healthService := NewHealthService(mockRepo, queue, []string{"order_book_dtos", "history_orders"}, columns, "v1.2.3")
+
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockRepo.On("GetColumns", mock.Anything).Return(columns, nil)
+	mockRepo.On("GetTables", mock.Anything).Return([]entity.TableStatus{{Name: "history_orders", Rows: 3}, {Name: "order_book_dtos", Rows: 5}}, nil).Once()
+
+	// Test case: every check passes
+	readiness := healthService.Ready(context.Background())
+	assert.True(t, readiness.Ready)
+	assert.Equal(t, []entity.Check{{Name: "clickhouse", OK: true}, {Name: "migrations", OK: true}, {Name: "ingest", OK: true}}, readiness.Checks)
+
+	// Test case: a table is missing and the ingestion queue is saturated
+	mockRepo.On("GetTables", mock.Anything).Return([]entity.TableStatus{{Name: "order_book_dtos"}}, nil).Once()
+	queue.Add(2)
+
+	readiness = healthService.Ready(context.Background())
+	assert.False(t, readiness.Ready)
+	assert.True(t, readiness.Checks[0].OK)
+	assert.Equal(t, "missing tables [history_orders]", readiness.Checks[1].Message)
+	assert.False(t, readiness.Checks[2].OK)
+
+	queue.Done(2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReady_OutdatedColumns(t *testing.T) {
+	mockRepo := new(mocks.MockHealthRepository)
+	columns := []entity.Column{
+		{Table: "history_orders", Name: "side", Type: "Enum8('buy' = 1, 'sell' = 2)"},
+		{Table: "history_orders", Name: "commission_asset", Type: "LowCardinality(String)"},
+	}
+	healthService := NewHealthService(mockRepo, NewIngestQueue(2), []string{"history_orders"}, columns, "v1.2.3")
+
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockRepo.On("GetTables", mock.Anything).Return([]entity.TableStatus{{Name: "history_orders"}}, nil)
+
+	// Test case: side is still a String and commission_asset was never added
+	mockRepo.On("GetColumns", mock.Anything).Return([]entity.Column{{Table: "history_orders", Name: "side", Type: "String"}}, nil).Once()
+
+	readiness := healthService.Ready(context.Background())
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, entity.Check{Name: "migrations", Message: "missing or outdated columns [history_orders.side history_orders.commission_asset]"}, readiness.Checks[1])
+
+	// Test case: the columns cannot be read
+	mockRepo.On("GetColumns", mock.Anything).Return([]entity.Column(nil), errors.New("code: 497, message: default: Not enough privileges")).Once()
+
+	readiness = healthService.Ready(context.Background())
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, entity.Check{Name: "migrations", Message: "schema could not be read"}, readiness.Checks[1])
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReady_DatabaseUnreachable(t *testing.T) {
+	mockRepo := new(mocks.MockHealthRepository)
+	healthService := NewHealthService(mockRepo, NewIngestQueue(2), []string{"order_book_dtos"}, nil, "v1.2.3")
+
+	mockRepo.On("Ping", mock.Anything).Return(errors.New("dial tcp 10.0.0.5:9000: connection refused"))
+
+	// Test case: the driver error is kept out of the unauthenticated readiness checks
+	readiness := healthService.Ready(context.Background())
+	assert.False(t, readiness.Ready)
+	assert.Equal(t, entity.Check{Name: "clickhouse", Message: "database unreachable"}, readiness.Checks[0])
+	assert.False(t, readiness.Checks[1].OK)
+	assert.True(t, readiness.Checks[2].OK)
+	mockRepo.AssertNotCalled(t, "GetTables", mock.Anything)
+
+	// Test case: the admin status still reports it
+	status := healthService.Status(context.Background())
+	assert.Equal(t, "dial tcp 10.0.0.5:9000: connection refused", status.Database.Error)
+	assert.Equal(t, "database unreachable", status.Readiness.Checks[0].Message)
+}
+
+func TestStatus(t *testing.T) {
+	mockRepo := new(mocks.MockHealthRepository)
+	queue := NewIngestQueue(10)
+	healthService := NewHealthService(mockRepo, queue, []string{"order_book_dtos"}, nil, "v1.2.3")
+
+	tables := []entity.TableStatus{{Name: "order_book_dtos", Rows: 5}}
+	mockRepo.On("Ping", mock.Anything).Return(nil)
+	mockRepo.On("GetTables", mock.Anything).Return(tables, nil)
+	mockRepo.On("GetColumns", mock.Anything).Return([]entity.Column{}, nil)
+	queue.Add(3)
+
+	status := healthService.Status(context.Background())
+	assert.Equal(t, "v1.2.3", status.Version)
+	assert.True(t, status.Readiness.Ready)
+	assert.True(t, status.Database.Reachable)
+	assert.Equal(t, tables, status.Database.Tables)
+	assert.Equal(t, entity.IngestStatus{Pending: 3, Capacity: 10}, status.Ingest)
+	assert.GreaterOrEqual(t, status.UptimeSeconds, 0.0)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+)
+
+// markoutLookback bounds how far before an order or a markout horizon a stored book is still used as its mid.
+const markoutLookback = time.Minute
+
+// midPoint is the mid price of a stored order book snapshot.
+type midPoint struct {
+	at  time.Time
+	mid float64
+}
+
+/*
+GetMarkouts measures how the stored order book mid moved at each horizon after every order,
+signed by side so that negative markouts mean the price moved against the fill.
+The move is relative to the stored mid at placement, falling back to the recorded top of book.
+Only the mid in force at each placement and horizon is read, and horizons that run past the
+last stored snapshot are not measured.
+Results are aggregated per algorithm and pair.
+Also returns an error if one occures.
+*/
+
+func (s *analyticsServiceImpl) GetMarkouts(req *entity.MarkoutRequest) ([]*entity.MarkoutAnalysis, error) {
+	orders, err := s.repo.GetHistoryOrders(&req.HistoryFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	type bookKey struct{ exchange, pair string }
+	byBook := make(map[bookKey][]*entity.HistoryOrder)
+	for _, o := range orders {
+		if sideSign(o.Side) == 0 {
+			continue
+		}
+		key := bookKey{o.ExchangeName, o.Pair}
+		byBook[key] = append(byBook[key], o)
+	}
+
+	type groupKey struct{ pair, algorithm string }
+	markouts := make(map[groupKey][][]float64)
+	counts := make(map[groupKey]int)
+
+	for key, bookOrders := range byBook {
+		times := make([]time.Time, 0, len(bookOrders)*(len(req.Durations)+1))
+		for _, o := range bookOrders {
+			times = append(times, o.TimePlaced)
+			for _, d := range req.Durations {
+				times = append(times, o.TimePlaced.Add(d))
+			}
+		}
+
+		found, err := s.repo.GetMidsAsOf(key.exchange, []string{key.pair}, times, markoutLookback)
+		if err != nil && !errors.Is(err, entity.ErrNotFound) {
+			return nil, err
+		}
+		mids := midsByTime(found)
+
+		last, err := s.repo.GetLastOrderBookTime(key.exchange, key.pair)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, o := range bookOrders {
+			g := groupKey{o.Pair, o.AlgorithmNamePlaced}
+			if _, ok := markouts[g]; !ok {
+				markouts[g] = make([][]float64, len(req.Durations))
+			}
+			counts[g]++
+
+			arrival, ok := mids[o.TimePlaced.UnixMilli()]
+			if !ok {
+				if o.LowestSellPrc <= 0 || o.HighestBuyPrc <= 0 {
+					continue
+				}
+				arrival = (o.LowestSellPrc + o.HighestBuyPrc) / 2
+			}
+
+			for i, d := range req.Durations {
+				at := o.TimePlaced.Add(d)
+				if last.Before(at) {
+					continue
+				}
+				later, ok := mids[at.UnixMilli()]
+				if !ok {
+					continue
+				}
+				markouts[g][i] = append(markouts[g][i], sideSign(o.Side)*(later-arrival)/arrival*10000)
+			}
+		}
+	}
+
+	result := make([]*entity.MarkoutAnalysis, 0, len(markouts))
+	for g, perHorizon := range markouts {
+		analysis := &entity.MarkoutAnalysis{
+			Pair:                g.pair,
+			AlgorithmNamePlaced: g.algorithm,
+			OrderCount:          counts[g],
+			Horizons:            make([]entity.HorizonMarkout, len(req.Durations)),
+		}
+		for i, values := range perHorizon {
+			analysis.Horizons[i] = horizonMarkout(req.Horizons[i], values)
+		}
+		result = append(result, analysis)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AlgorithmNamePlaced != result[j].AlgorithmNamePlaced {
+			return result[i].AlgorithmNamePlaced < result[j].AlgorithmNamePlaced
+		}
+		return result[i].Pair < result[j].Pair
+	})
+
+	return result, nil
+}
+
+func horizonMarkout(horizon string, values []float64) entity.HorizonMarkout {
+	hm := entity.HorizonMarkout{Horizon: horizon, Count: len(values)}
+	if len(values) == 0 {
+		return hm
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	var adverse int
+	for _, v := range sorted {
+		sum += v
+		if v < 0 {
+			adverse++
+		}
+	}
+
+	hm.MeanBps = sum / float64(len(sorted))
+	hm.MedianBps = percentile(sorted, 50)
+	hm.AdverseRatio = float64(adverse) / float64(len(sorted))
+
+	return hm
+}
+
+// toMidPoints keeps the mids of the stored snapshots in timestamp order.
+func toMidPoints(mids []*entity.BookMid) []midPoint {
+	points := make([]midPoint, 0, len(mids))
+	for _, m := range mids {
+		points = append(points, midPoint{at: m.Timestamp, mid: m.Mid})
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].at.Before(points[j].at) })
+	return points
+}
+
+// midsByTime indexes the mids looked up as of given times by those times, to the millisecond ClickHouse keeps.
+func midsByTime(mids []*entity.BookMid) map[int64]float64 {
+	byTime := make(map[int64]float64, len(mids))
+	for _, m := range mids {
+		byTime[m.At.UnixMilli()] = m.Mid
+	}
+	return byTime
+}
+
+// midAsOf returns the mid of the last snapshot taken at or before t, ignoring snapshots older than notBefore.
+func midAsOf(mids []midPoint, t, notBefore time.Time) (float64, bool) {
+	idx := sort.Search(len(mids), func(i int) bool { return mids[i].at.After(t) }) - 1
+	if idx < 0 || mids[idx].at.Before(notBefore) {
+		return 0, false
+	}
+	return mids[idx].mid, true
+}
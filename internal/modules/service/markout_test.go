@@ -0,0 +1,136 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func book(at time.Time, bid, ask float64) *entity.OrderBook {
+	return &entity.OrderBook{
+		Exchange:  "exchange1",
+		Pair:      "pair1",
+		Asks:      []entity.DepthOrder{{Price: ask + 1, BaseQty: 1}, {Price: ask, BaseQty: 1}},
+		Bids:      []entity.DepthOrder{{Price: bid, BaseQty: 1}, {Price: bid - 1, BaseQty: 1}},
+		Timestamp: at,
+	}
+}
+
+// midAt is the mid of the book taken at taken, looked up as of at.
+func midAt(at, taken time.Time, mid float64) *entity.BookMid {
+	return &entity.BookMid{At: at, Exchange: "exchange1", Pair: "pair1", Timestamp: taken, Mid: mid}
+}
+
+func TestGetMarkouts(t *testing.T) {
+	mockRepo := new(mocks.MockAnalyticsRepository)
+	mockService := NewAnalyticsService(mockRepo)
+
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	orders := []*entity.HistoryOrder{
+		{ExchangeName: "exchange1", Pair: "pair1", AlgorithmNamePlaced: "algo1", Side: "buy", Price: 100, TimePlaced: t0},
+		{ExchangeName: "exchange1", Pair: "pair1", AlgorithmNamePlaced: "algo1", Side: "sell", Price: 100, TimePlaced: t0},
+	}
+	// Books were stored at t0-1s and t0+5s.
+	mids := []*entity.BookMid{
+		midAt(t0, t0.Add(-time.Second), 100),
+		midAt(t0.Add(time.Second), t0.Add(-time.Second), 100),
+		midAt(t0.Add(10*time.Second), t0.Add(5*time.Second), 102),
+	}
+
+	req := &entity.MarkoutRequest{Horizons: []string{"1s", "10s"}}
+	assert.NoError(t, req.Validate())
+
+	// Only the mids at each placement and horizon are looked up.
+	times := []time.Time{t0, t0.Add(time.Second), t0.Add(10 * time.Second), t0, t0.Add(time.Second), t0.Add(10 * time.Second)}
+	mockRepo.On("GetHistoryOrders", &req.HistoryFilter).Return(orders, nil)
+	mockRepo.On("GetMidsAsOf", "exchange1", []string{"pair1"}, times, markoutLookback).Return(mids, nil)
+	mockRepo.On("GetLastOrderBookTime", "exchange1", "pair1").Return(t0.Add(5*time.Second), nil)
+
+	result, err := mockService.GetMarkouts(req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 2, result[0].OrderCount)
+
+	// The 1s horizon still sees the arrival book, the 10s horizon runs past the last snapshot.
+	oneSecond := result[0].Horizons[0]
+	assert.Equal(t, "1s", oneSecond.Horizon)
+	assert.Equal(t, 2, oneSecond.Count)
+	assert.Zero(t, oneSecond.MeanBps)
+
+	tenSeconds := result[0].Horizons[1]
+	assert.Equal(t, 0, tenSeconds.Count)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetMarkouts_StaleHorizon(t *testing.T) {
+	mockRepo := new(mocks.MockAnalyticsRepository)
+	mockService := NewAnalyticsService(mockRepo)
+
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	orders := []*entity.HistoryOrder{
+		{ExchangeName: "exchange1", Pair: "pair1", AlgorithmNamePlaced: "algo1", Side: "buy", Price: 100, TimePlaced: t0},
+	}
+	// Books were stored at t0 and t0+5m, so none lies within markoutLookback of the 2m horizon.
+	mids := []*entity.BookMid{
+		midAt(t0, t0, 100),
+		midAt(t0.Add(5*time.Minute), t0.Add(5*time.Minute), 102),
+	}
+
+	req := &entity.MarkoutRequest{Horizons: []string{"2m", "5m"}}
+	assert.NoError(t, req.Validate())
+
+	mockRepo.On("GetHistoryOrders", &req.HistoryFilter).Return(orders, nil)
+	mockRepo.On("GetMidsAsOf", "exchange1", []string{"pair1"}, mock.Anything, markoutLookback).Return(mids, nil)
+	mockRepo.On("GetLastOrderBookTime", "exchange1", "pair1").Return(t0.Add(5*time.Minute), nil)
+
+	result, err := mockService.GetMarkouts(req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+
+	// The arrival book is too old to stand in for the 2m horizon.
+	assert.Equal(t, 0, result[0].Horizons[0].Count)
+	assert.Equal(t, 1, result[0].Horizons[1].Count)
+	assert.InDelta(t, 200.0, result[0].Horizons[1].MeanBps, 1e-9)
+}
+
+func TestGetMarkouts_Signed(t *testing.T) {
+	mockRepo := new(mocks.MockAnalyticsRepository)
+	mockService := NewAnalyticsService(mockRepo)
+
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	orders := []*entity.HistoryOrder{
+		{ExchangeName: "exchange1", Pair: "pair1", AlgorithmNamePlaced: "algo1", Side: "buy", Price: 100, TimePlaced: t0},
+		{ExchangeName: "exchange1", Pair: "pair1", AlgorithmNamePlaced: "algo2", Side: "sell", Price: 100, TimePlaced: t0},
+	}
+	mids := []*entity.BookMid{
+		midAt(t0, t0, 100),
+		midAt(t0.Add(time.Second), t0.Add(time.Second), 102),
+	}
+
+	req := &entity.MarkoutRequest{Horizons: []string{"1s"}}
+	assert.NoError(t, req.Validate())
+
+	mockRepo.On("GetHistoryOrders", &req.HistoryFilter).Return(orders, nil)
+	mockRepo.On("GetMidsAsOf", "exchange1", []string{"pair1"}, mock.Anything, markoutLookback).Return(mids, nil)
+	mockRepo.On("GetLastOrderBookTime", "exchange1", "pair1").Return(t0.Add(time.Second), nil)
+
+	result, err := mockService.GetMarkouts(req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	assert.Equal(t, "algo1", result[0].AlgorithmNamePlaced)
+	assert.InDelta(t, 200, result[0].Horizons[0].MeanBps, 1e-9)
+	assert.Zero(t, result[0].Horizons[0].AdverseRatio)
+
+	assert.Equal(t, "algo2", result[1].AlgorithmNamePlaced)
+	assert.InDelta(t, -200, result[1].Horizons[0].MedianBps, 1e-9)
+	assert.Equal(t, 1.0, result[1].Horizons[0].AdverseRatio)
+}
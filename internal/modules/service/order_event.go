@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+)
+
+type OrderEventService interface {
+	TransitionOrder(event entity.OrderEvent) error
+	GetOrderTimeline(orderID string) (*entity.OrderTimeline, error)
+}
+
+type orderEventServiceImpl struct {
+	repo repository.OrderEventRepository
+	// mu serialises transitions so two concurrent events cannot both pass validation.
+	mu sync.Mutex
+}
+
+func NewOrderEventService(repo repository.OrderEventRepository) OrderEventService {
+	return &orderEventServiceImpl{repo: repo}
+}
+
+/*
+TransitionOrder records a lifecycle event after checking it is a legal move from the order's current status
+and is later than the event that set it.
+Returns entity.ErrIllegalTransition for illegal or back-dated moves, or another error if one occures.
+*/
+
+func (s *orderEventServiceImpl) TransitionOrder(event entity.OrderEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latest, err := s.latestEvent(event.OrderID)
+	if err != nil {
+		return err
+	}
+
+	var current entity.OrderStatus
+	if latest != nil {
+		current = latest.Status
+		// Events are read back in event_time order, so a back-dated event would rewind the status
+		// and one at the same time could be read back before the event it follows.
+		if !event.EventTime.After(latest.EventTime) {
+			return fmt.Errorf("%w: event at %s is not later than the %q event at %s", entity.ErrIllegalTransition,
+				event.EventTime.Format(time.RFC3339Nano), current, latest.EventTime.Format(time.RFC3339Nano))
+		}
+	}
+
+	if !entity.CanTransition(current, event.Status) {
+		return fmt.Errorf("%w: %q to %q", entity.ErrIllegalTransition, current, event.Status)
+	}
+
+	return s.repo.SaveOrderEvent(event)
+}
+
+/*
+GetOrderTimeline returns every lifecycle event and fill of an order with its current status.
+An order with fills but no events, such as one imported without its lifecycle, has an empty status and event list.
+Returns entity.ErrNotFound if the order has neither, or another error if one occures.
+*/
+
+func (s *orderEventServiceImpl) GetOrderTimeline(orderID string) (*entity.OrderTimeline, error) {
+	events, err := s.repo.GetOrderEvents(orderID)
+	if err != nil && !errors.Is(err, entity.ErrNotFound) {
+		return nil, err
+	}
+
+	fills, err := s.repo.GetOrderFills(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 && len(fills) == 0 {
+		return nil, entity.ErrNotFound
+	}
+
+	timeline := &entity.OrderTimeline{OrderID: orderID, Events: []*entity.OrderEvent{}, Fills: fills}
+	if len(events) > 0 {
+		timeline.Status = events[len(events)-1].Status
+		timeline.Events = events
+	}
+	return timeline, nil
+}
+
+// latestEvent returns the latest event of an order, or nil if it has none.
+func (s *orderEventServiceImpl) latestEvent(orderID string) (*entity.OrderEvent, error) {
+	events, err := s.repo.GetOrderEvents(orderID)
+	if errors.Is(err, entity.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return events[len(events)-1], nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransitionOrder(t *testing.T) {
+	mockRepo := new(mocks.MockOrderEventRepository)
+	mockService := NewOrderEventService(mockRepo)
+
+	event := entity.OrderEvent{OrderID: "order1", Status: entity.OrderStatusNew, EventTime: time.Now()}
+
+	mockRepo.On("GetOrderEvents", "order1").Return([]*entity.OrderEvent(nil), entity.ErrNotFound)
+	mockRepo.On("SaveOrderEvent", event).Return(nil)
+
+	err := mockService.TransitionOrder(event)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransitionOrder_Illegal(t *testing.T) {
+	mockRepo := new(mocks.MockOrderEventRepository)
+	mockService := NewOrderEventService(mockRepo)
+
+	events := []*entity.OrderEvent{
+		{OrderID: "order1", Status: entity.OrderStatusNew},
+		{OrderID: "order1", Status: entity.OrderStatusFilled},
+	}
+	mockRepo.On("GetOrderEvents", "order1").Return(events, nil)
+
+	err := mockService.TransitionOrder(entity.OrderEvent{OrderID: "order1", Status: entity.OrderStatusCancelled})
+
+	assert.ErrorIs(t, err, entity.ErrIllegalTransition)
+	mockRepo.AssertNotCalled(t, "SaveOrderEvent")
+}
+
+func TestTransitionOrder_BackDated(t *testing.T) {
+	mockRepo := new(mocks.MockOrderEventRepository)
+	mockService := NewOrderEventService(mockRepo)
+
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	events := []*entity.OrderEvent{
+		{OrderID: "order1", Status: entity.OrderStatusNew, EventTime: t0},
+		{OrderID: "order1", Status: entity.OrderStatusPartiallyFilled, EventTime: t0.Add(time.Minute)},
+	}
+	mockRepo.On("GetOrderEvents", "order1").Return(events, nil)
+
+	// Legal from partially filled, but dated before it.
+	err := mockService.TransitionOrder(entity.OrderEvent{OrderID: "order1", Status: entity.OrderStatusPartiallyFilled, EventTime: t0.Add(30 * time.Second)})
+
+	assert.ErrorIs(t, err, entity.ErrIllegalTransition)
+
+	// Test case: an event at the same time as the latest one
+	err = mockService.TransitionOrder(entity.OrderEvent{OrderID: "order1", Status: entity.OrderStatusFilled, EventTime: t0.Add(time.Minute)})
+	assert.ErrorIs(t, err, entity.ErrIllegalTransition)
+	mockRepo.AssertNotCalled(t, "SaveOrderEvent")
+}
+
+func TestGetOrderTimeline(t *testing.T) {
+	mockRepo := new(mocks.MockOrderEventRepository)
+	mockService := NewOrderEventService(mockRepo)
+
+	events := []*entity.OrderEvent{
+		{OrderID: "order1", Status: entity.OrderStatusNew},
+		{OrderID: "order1", Status: entity.OrderStatusPartiallyFilled, FilledQty: 0.5},
+	}
+	fills := []*entity.HistoryOrder{{OrderID: "order1", BaseQty: 0.5}}
+
+	mockRepo.On("GetOrderEvents", "order1").Return(events, nil)
+	mockRepo.On("GetOrderFills", "order1").Return(fills, nil)
+
+	timeline, err := mockService.GetOrderTimeline("order1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OrderStatusPartiallyFilled, timeline.Status)
+	assert.Equal(t, events, timeline.Events)
+	assert.Equal(t, fills, timeline.Fills)
+}
+
+func TestGetOrderTimeline_FillsOnly(t *testing.T) {
+	mockRepo := new(mocks.MockOrderEventRepository)
+	mockService := NewOrderEventService(mockRepo)
+
+	fills := []*entity.HistoryOrder{{OrderID: "order1", BaseQty: 0.5}}
+	mockRepo.On("GetOrderEvents", "order1").Return([]*entity.OrderEvent(nil), entity.ErrNotFound)
+	mockRepo.On("GetOrderFills", "order1").Return(fills, nil)
+	mockRepo.On("GetOrderEvents", "order2").Return([]*entity.OrderEvent(nil), entity.ErrNotFound)
+	mockRepo.On("GetOrderFills", "order2").Return([]*entity.HistoryOrder{}, nil)
+
+	// Test case: an order with fills but no events
+	timeline, err := mockService.GetOrderTimeline("order1")
+	assert.NoError(t, err)
+	assert.Empty(t, timeline.Status)
+	assert.NotNil(t, timeline.Events)
+	assert.Empty(t, timeline.Events)
+	assert.Equal(t, fills, timeline.Fills)
+
+	// Test case: an unknown order
+	_, err = mockService.GetOrderTimeline("order2")
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+}
+
+func TestCanTransition(t *testing.T) {
+	assert.True(t, entity.CanTransition("", entity.OrderStatusNew))
+	assert.True(t, entity.CanTransition("", entity.OrderStatusRejected))
+	assert.False(t, entity.CanTransition("", entity.OrderStatusFilled))
+	assert.True(t, entity.CanTransition(entity.OrderStatusPartiallyFilled, entity.OrderStatusPartiallyFilled))
+	assert.False(t, entity.CanTransition(entity.OrderStatusPartiallyFilled, entity.OrderStatusRejected))
+	assert.False(t, entity.CanTransition(entity.OrderStatusExpired, entity.OrderStatusNew))
+}
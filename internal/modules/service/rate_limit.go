@@ -0,0 +1,202 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+	"github.com/egorque1/vortex-test/internal/ratelimit"
+)
+
+/*
+rateQuotaRefresh is how often the stored quotas are read again,
+and so how long a quota changed through another instance takes to apply here.
+*/
+const rateQuotaRefresh = time.Minute
+
+type RateLimitService interface {
+	Allow(principal, keyID, route, group string) (ratelimit.Result, bool)
+	Check(principal, keyID, route, group string) (ratelimit.Result, bool)
+	GetRateQuotas() ([]*entity.RateQuota, error)
+	SetRateQuota(quota entity.RateQuota) error
+	DeleteRateQuota(keyID, route string) error
+}
+
+type quotaKey struct {
+	keyID, route string
+}
+
+type rateLimitServiceImpl struct {
+	repo     repository.RateQuotaRepository
+	defaults map[string]entity.RateQuota
+	limiter  *ratelimit.Limiter
+
+	mu       sync.Mutex
+	stored   map[quotaKey]entity.RateQuota
+	loadedAt time.Time
+}
+
+// NewRateLimitService returns a service limiting the route groups of defaults to their quotas unless a stored quota overrides them.
+func NewRateLimitService(repo repository.RateQuotaRepository, defaults []entity.RateQuota) RateLimitService {
+	s := &rateLimitServiceImpl{repo: repo, defaults: make(map[string]entity.RateQuota), limiter: ratelimit.New()}
+	for _, q := range defaults {
+		s.defaults[q.Route] = q
+	}
+	return s
+}
+
+/*
+Allow takes a request of principal, authenticated with keyID or anonymous if keyID is empty, to route of group
+from the bucket of the first quota found for the key and the route, the key and the group, the route, and the group.
+Requests matching the same quota share a bucket. Returns false if no quota applies and the request is not limited.
+*/
+func (s *rateLimitServiceImpl) Allow(principal, keyID, route, group string) (ratelimit.Result, bool) {
+	quota, ok := s.quota(keyID, route, group)
+	if !ok {
+		return ratelimit.Result{Allowed: true}, false
+	}
+	return s.limiter.Allow(principal+"\x00"+quota.KeyID+"\x00"+quota.Route, quota.Rate, quota.Burst), true
+}
+
+// Check reports whether Allow would allow the request now, without taking it from the bucket.
+func (s *rateLimitServiceImpl) Check(principal, keyID, route, group string) (ratelimit.Result, bool) {
+	quota, ok := s.quota(keyID, route, group)
+	if !ok {
+		return ratelimit.Result{Allowed: true}, false
+	}
+	return s.limiter.Peek(principal+"\x00"+quota.KeyID+"\x00"+quota.Route, quota.Rate, quota.Burst), true
+}
+
+func (s *rateLimitServiceImpl) quota(keyID, route, group string) (entity.RateQuota, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.loadedAt) >= rateQuotaRefresh {
+		if err := s.load(); err != nil {
+			log.Printf("failed to refresh rate quotas, keeping the previous ones: %v", err)
+		}
+	}
+
+	lookups := []quotaKey{{"", route}, {"", group}}
+	if keyID != "" {
+		lookups = append([]quotaKey{{keyID, route}, {keyID, group}}, lookups...)
+	}
+	for _, k := range lookups {
+		if q, ok := s.stored[k]; ok {
+			return q, true
+		}
+	}
+
+	q, ok := s.defaults[group]
+	return q, ok
+}
+
+/*
+load replaces the stored quotas with those in the repository. The caller holds s.mu.
+A failed load is not retried before the next refresh, so a storage outage does not slow down every request.
+*/
+func (s *rateLimitServiceImpl) load() error {
+	s.loadedAt = time.Now()
+
+	quotas, err := s.repo.GetRateQuotas()
+	if err != nil && !errors.Is(err, entity.ErrNotFound) {
+		return err
+	}
+
+	s.stored = make(map[quotaKey]entity.RateQuota, len(quotas))
+	for _, q := range quotas {
+		s.stored[quotaKey{q.KeyID, q.Route}] = *q
+	}
+	return nil
+}
+
+/*
+GetRateQuotas returns the quotas in force: the stored ones and the defaults of the route groups no stored quota for every caller overrides.
+Also returns an error if one occures.
+*/
+
+func (s *rateLimitServiceImpl) GetRateQuotas() ([]*entity.RateQuota, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	var quotas []*entity.RateQuota
+	for group, q := range s.defaults {
+		if _, ok := s.stored[quotaKey{"", group}]; !ok {
+			quotas = append(quotas, &q)
+		}
+	}
+	for _, q := range s.stored {
+		quotas = append(quotas, &q)
+	}
+	sort.Slice(quotas, func(i, j int) bool {
+		if quotas[i].KeyID != quotas[j].KeyID {
+			return quotas[i].KeyID < quotas[j].KeyID
+		}
+		return quotas[i].Route < quotas[j].Route
+	})
+
+	return quotas, nil
+}
+
+/*
+SetRateQuota stores a quota for a route group or a single route, for one API key or every caller, and applies it at once.
+Returns a validation error for an unknown route group, or another error if one occures.
+*/
+
+func (s *rateLimitServiceImpl) SetRateQuota(quota entity.RateQuota) error {
+	if err := quota.Validate(); err != nil {
+		return err
+	}
+	if _, ok := s.defaults[quota.Route]; !ok && !strings.Contains(quota.Route, " ") {
+		return entity.Invalid("route", "route group %s does not exist", quota.Route)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+	if err := s.repo.SaveRateQuota(quota); err != nil {
+		return err
+	}
+	s.stored[quotaKey{quota.KeyID, quota.Route}] = quota
+
+	return nil
+}
+
+/*
+DeleteRateQuota deletes a stored quota, so the default of the route group applies again.
+Returns a "record not found" error if there is no such quota, or another error if one occures.
+*/
+
+func (s *rateLimitServiceImpl) DeleteRateQuota(keyID, route string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	k := quotaKey{keyID, route}
+	if _, ok := s.stored[k]; !ok {
+		return fmt.Errorf("%w: no rate quota for route %s and key %q", entity.ErrNotFound, route, keyID)
+	}
+
+	if err := s.repo.DeleteRateQuota(keyID, route); err != nil {
+		return err
+	}
+	delete(s.stored, k)
+
+	return nil
+}
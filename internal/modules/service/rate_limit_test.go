@@ -0,0 +1,100 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var defaultQuotas = []entity.RateQuota{{Route: "read", Rate: 1, Burst: 2}, {Route: "write", Rate: 1, Burst: 1}}
+
+func TestAllow(t *testing.T) {
+	mockRepo := new(mocks.MockRateQuotaRepository)
+	rateLimitService := NewRateLimitService(mockRepo, defaultQuotas)
+
+	mockRepo.On("GetRateQuotas").Return([]*entity.RateQuota{
+		{KeyID: "key1", Route: "read", Rate: 1, Burst: 5},
+		{Route: "GET /v1/history/{clientName}", Rate: 1, Burst: 1},
+	}, nil).Once()
+
+	// The default quota of the group applies, per principal.
+	for i := 0; i < 2; i++ {
+		result, limited := rateLimitService.Allow("key:key2", "key2", "GET /orderbook", "read")
+		assert.True(t, limited)
+		assert.True(t, result.Allowed)
+	}
+	result, _ := rateLimitService.Allow("key:key2", "key2", "GET /orderbook", "read")
+	assert.False(t, result.Allowed)
+	result, _ = rateLimitService.Allow("key:key3", "key3", "GET /orderbook", "read")
+	assert.True(t, result.Allowed)
+
+	// A quota of the key overrides the group default.
+	result, _ = rateLimitService.Allow("key:key1", "key1", "GET /orderbook", "read")
+	assert.Equal(t, 5, result.Limit)
+
+	// A quota of the route overrides the group default and has its own bucket.
+	result, _ = rateLimitService.Allow("key:key2", "key2", "GET /v1/history/{clientName}", "read")
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Limit)
+
+	// Routes of groups without a quota are not limited.
+	_, limited := rateLimitService.Allow("key:key2", "key2", "GET /health", "")
+	assert.False(t, limited)
+
+	mockRepo.AssertNumberOfCalls(t, "GetRateQuotas", 1)
+}
+
+func TestSetRateQuota(t *testing.T) {
+	mockRepo := new(mocks.MockRateQuotaRepository)
+	rateLimitService := NewRateLimitService(mockRepo, defaultQuotas)
+
+	quota := entity.RateQuota{KeyID: "key1", Route: "write", Rate: 10, Burst: 20}
+	mockRepo.On("GetRateQuotas").Return([]*entity.RateQuota(nil), entity.ErrNotFound)
+	mockRepo.On("SaveRateQuota", quota).Return(nil)
+
+	assert.NoError(t, rateLimitService.SetRateQuota(quota))
+
+	result, _ := rateLimitService.Allow("key:key1", "key1", "POST /orderbook", "write")
+	assert.Equal(t, 20, result.Limit)
+
+	err := rateLimitService.SetRateQuota(entity.RateQuota{Route: "exports", Rate: 1, Burst: 1})
+	assert.ErrorIs(t, err, entity.ErrValidation)
+	err = rateLimitService.SetRateQuota(entity.RateQuota{Route: "read", Rate: 1})
+	assert.ErrorIs(t, err, entity.ErrValidation)
+	mockRepo.AssertNumberOfCalls(t, "SaveRateQuota", 1)
+}
+
+func TestGetRateQuotas(t *testing.T) {
+	mockRepo := new(mocks.MockRateQuotaRepository)
+	rateLimitService := NewRateLimitService(mockRepo, defaultQuotas)
+
+	mockRepo.On("GetRateQuotas").Return([]*entity.RateQuota{
+		{Route: "read", Rate: 50, Burst: 50},
+		{KeyID: "key1", Route: "write", Rate: 10, Burst: 20},
+	}, nil)
+
+	quotas, err := rateLimitService.GetRateQuotas()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.RateQuota{
+		{Route: "read", Rate: 50, Burst: 50},
+		{Route: "write", Rate: 1, Burst: 1},
+		{KeyID: "key1", Route: "write", Rate: 10, Burst: 20},
+	}, quotas)
+}
+
+func TestDeleteRateQuota(t *testing.T) {
+	mockRepo := new(mocks.MockRateQuotaRepository)
+	rateLimitService := NewRateLimitService(mockRepo, defaultQuotas)
+
+	mockRepo.On("GetRateQuotas").Return([]*entity.RateQuota{{KeyID: "key1", Route: "write", Rate: 10, Burst: 20}}, nil)
+	mockRepo.On("DeleteRateQuota", "key1", "write").Return(nil)
+
+	assert.NoError(t, rateLimitService.DeleteRateQuota("key1", "write"))
+	assert.ErrorIs(t, rateLimitService.DeleteRateQuota("key2", "write"), entity.ErrNotFound)
+	mockRepo.AssertNumberOfCalls(t, "DeleteRateQuota", 1)
+	mockRepo.AssertNotCalled(t, "DeleteRateQuota", "key2", mock.Anything)
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+)
+
+type ReportService interface {
+	GetCommissionReport(req *entity.CommissionReportRequest) ([]*entity.CommissionReport, error)
+}
+
+type reportServiceImpl struct {
+	repo repository.ReportRepository
+}
+
+func NewReportService(repo repository.ReportRepository) ReportService {
+	return &reportServiceImpl{repo: repo}
+}
+
+/*
+GetCommissionReport returns aggregated commissions with the effective fee rate
+in basis points of traded notional.
+Also returns an error if one occures.
+*/
+
+func (s *reportServiceImpl) GetCommissionReport(req *entity.CommissionReportRequest) ([]*entity.CommissionReport, error) {
+	report, err := s.repo.GetCommissionReport(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range report {
+		row.FeeRateBps = feeRateBps(row.Commission, row.Notional)
+	}
+
+	return report, nil
+}
+
+func feeRateBps(commission, notional float64) float64 {
+	if notional == 0 {
+		return 0
+	}
+	return commission / notional * 10000
+}
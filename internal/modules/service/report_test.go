@@ -0,0 +1,60 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCommissionReport(t *testing.T) {
+	mockRepo := new(mocks.MockReportRepository)
+	mockService := NewReportService(mockRepo)
+
+	req := &entity.CommissionReportRequest{
+		ClientName: "client1",
+		Period:     entity.ReportPeriodMonth,
+		GroupBy:    []string{"exchange_name"},
+	}
+	report := []*entity.CommissionReport{
+		{
+			Period:       time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			ExchangeName: "exchange1",
+			OrderCount:   10,
+			Notional:     200000,
+			Commission:   20,
+		},
+		{
+			Period:       time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			ExchangeName: "exchange2",
+			OrderCount:   1,
+		},
+	}
+
+	mockRepo.On("GetCommissionReport", req).Return(report, nil)
+
+	result, err := mockService.GetCommissionReport(req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.InDelta(t, 1.0, result[0].FeeRateBps, 1e-9)
+	assert.Zero(t, result[1].FeeRateBps)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetCommissionReport_NotFound(t *testing.T) {
+	mockRepo := new(mocks.MockReportRepository)
+	mockService := NewReportService(mockRepo)
+
+	req := &entity.CommissionReportRequest{Period: entity.ReportPeriodDay}
+
+	mockRepo.On("GetCommissionReport", req).Return([]*entity.CommissionReport(nil), entity.ErrNotFound)
+
+	result, err := mockService.GetCommissionReport(req)
+
+	assert.ErrorIs(t, err, entity.ErrNotFound)
+	assert.Nil(t, result)
+}
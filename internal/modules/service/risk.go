@@ -0,0 +1,288 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/cache"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+)
+
+type RiskService interface {
+	SetRiskLimit(limit entity.RiskLimit) error
+	GetRiskLimits(clientName string) ([]*entity.RiskLimit, error)
+	CheckOrder(req *entity.RiskCheckRequest) (*entity.RiskCheck, error)
+	EvaluateFill(order *entity.HistoryOrder) ([]*entity.RiskBreach, error)
+	GetRiskBreaches(clientName string) ([]*entity.RiskBreach, error)
+}
+
+/*
+riskExposureTTL is how long the position snapshot of a client in a pair is kept up to date in memory
+before it is summed from history_orders again, and so how long fills saved in batches or through
+another instance take to count here.
+*/
+const riskExposureTTL = time.Minute
+
+type riskServiceImpl struct {
+	repo repository.RiskRepository
+
+	// mu serialises reading and updating the snapshots in exposures so no fill is applied twice.
+	mu        sync.Mutex
+	exposures *cache.Cache
+}
+
+func NewRiskService(repo repository.RiskRepository) RiskService {
+	return &riskServiceImpl{repo: repo, exposures: cache.New(riskExposureTTL)}
+}
+
+// exposureSnapshot is the exposure of a client in a pair, with the daily notional counted from dayStart.
+type exposureSnapshot struct {
+	exposure entity.RiskExposure
+	dayStart time.Time
+}
+
+// riskState is the exposure of a client in a pair valued at the mark price.
+type riskState struct {
+	position      float64
+	dailyNotional float64
+	pnl           float64
+}
+
+/*
+SetRiskLimit saves the limits of a client on an exchange, for a pair or, with an empty pair, for every pair.
+Returns an error if one occures.
+*/
+
+func (s *riskServiceImpl) SetRiskLimit(limit entity.RiskLimit) error {
+	limit.UpdatedAt = time.Now()
+	return s.repo.SaveRiskLimit(limit)
+}
+
+/*
+GetRiskLimits returns the risk limits of a client, or of every client if clientName is empty.
+Also returns an error if one occures.
+*/
+
+func (s *riskServiceImpl) GetRiskLimits(clientName string) ([]*entity.RiskLimit, error) {
+	return s.repo.GetRiskLimits(clientName)
+}
+
+/*
+CheckOrder reports whether a proposed order keeps the client within its limits.
+The current position comes from the saved fills and is valued at the mid price of the latest order book.
+Orders that reduce the position are allowed even when the position or loss is already over its limit.
+An order without limits is always allowed. Returns entity.ErrNoMarkPrice when the order has no price
+and no order book is stored for the pair, or another error if one occures.
+*/
+
+func (s *riskServiceImpl) CheckOrder(req *entity.RiskCheckRequest) (*entity.RiskCheck, error) {
+	limit, err := s.repo.GetRiskLimit(req.ClientName, req.ExchangeName, req.Pair)
+	if errors.Is(err, entity.ErrNotFound) {
+		return &entity.RiskCheck{Allowed: true, Breaches: []*entity.RiskBreach{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mark, err := s.markPrice(req.ExchangeName, req.Pair)
+	if err != nil && !errors.Is(err, entity.ErrNoMarkPrice) {
+		return nil, err
+	}
+	price := req.Price
+	if price == 0 {
+		price = mark
+	}
+	if price == 0 {
+		return nil, entity.ErrNoMarkPrice
+	}
+	if mark == 0 {
+		mark = price
+	}
+
+	s.mu.Lock()
+	snapshot, _, err := s.exposure(req.ClientName, req.ExchangeName, req.Pair)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	current := valued(snapshot.exposure, mark)
+
+	notional := req.BaseQty * price
+	projected := riskState{
+		position:      current.position + sideSign(req.Side)*req.BaseQty,
+		dailyNotional: current.dailyNotional + notional,
+		pnl:           current.pnl + sideSign(req.Side)*req.BaseQty*(mark-price),
+	}
+
+	breaches := limitBreaches(limit, current, projected, notional)
+	for _, b := range breaches {
+		b.ClientName, b.ExchangeName, b.Pair = req.ClientName, req.ExchangeName, req.Pair
+		b.DetectedAt = time.Now()
+	}
+
+	return &entity.RiskCheck{
+		Allowed:                len(breaches) == 0,
+		Limit:                  limit,
+		MarkPrice:              mark,
+		OrderNotional:          notional,
+		Position:               current.position,
+		ProjectedPosition:      projected.position,
+		DailyNotional:          current.dailyNotional,
+		ProjectedDailyNotional: projected.dailyNotional,
+		PnL:                    current.pnl,
+		Breaches:               breaches,
+	}, nil
+}
+
+/*
+EvaluateFill checks the limits of a client after a fill was saved and saves the breaches it finds.
+The fill is valued at the latest mid price, or at its own price when no order book is stored.
+The position is summed from history_orders at most once per riskExposureTTL, later fills are added to it in memory.
+Also returns an error if one occures.
+*/
+
+func (s *riskServiceImpl) EvaluateFill(order *entity.HistoryOrder) ([]*entity.RiskBreach, error) {
+	limit, err := s.repo.GetRiskLimit(order.ClientName, order.ExchangeName, order.Pair)
+	if errors.Is(err, entity.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mark, err := s.markPrice(order.ExchangeName, order.Pair)
+	if err != nil && !errors.Is(err, entity.ErrNoMarkPrice) {
+		return nil, err
+	}
+	if mark == 0 {
+		mark = order.Price
+	}
+
+	// A snapshot summed now already holds the saved fill, one kept in memory has it added.
+	s.mu.Lock()
+	snapshot, loaded, err := s.exposure(order.ClientName, order.ExchangeName, order.Pair)
+	if err == nil && !loaded {
+		snapshot.apply(order)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	after := valued(snapshot.exposure, mark)
+
+	notional := order.BaseQty * order.Price
+	before := riskState{
+		position:      after.position - sideSign(order.Side)*order.BaseQty,
+		dailyNotional: after.dailyNotional - notional,
+		pnl:           after.pnl,
+	}
+
+	breaches := limitBreaches(limit, before, after, notional)
+	for _, b := range breaches {
+		b.ClientName, b.ExchangeName, b.Pair = order.ClientName, order.ExchangeName, order.Pair
+		b.OrderID = order.OrderID
+		b.DetectedAt = time.Now()
+	}
+
+	if err := s.repo.SaveRiskBreaches(breaches); err != nil {
+		return nil, err
+	}
+
+	return breaches, nil
+}
+
+/*
+GetRiskBreaches returns the latest risk breaches of a client, or of every client if clientName is empty.
+Also returns an error if one occures.
+*/
+
+func (s *riskServiceImpl) GetRiskBreaches(clientName string) ([]*entity.RiskBreach, error) {
+	return s.repo.GetRiskBreaches(clientName)
+}
+
+// markPrice returns the mid price of the latest order book, or entity.ErrNoMarkPrice if there is none.
+func (s *riskServiceImpl) markPrice(exchangeName, pair string) (float64, error) {
+	book, err := s.repo.GetLatestOrderBook(exchangeName, pair)
+	if errors.Is(err, entity.ErrNotFound) {
+		return 0, entity.ErrNoMarkPrice
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	mid, ok := book.MidPrice()
+	if !ok {
+		return 0, entity.ErrNoMarkPrice
+	}
+	return mid, nil
+}
+
+/*
+exposure returns the position snapshot of a client in a pair, summing it from history_orders when it is
+missing, expired or from a previous UTC day, and reports whether it was summed. The caller holds s.mu.
+*/
+func (s *riskServiceImpl) exposure(clientName, exchangeName, pair string) (*exposureSnapshot, bool, error) {
+	key := clientName + "\x00" + exchangeName + "\x00" + pair
+	startOfDay := time.Now().UTC().Truncate(24 * time.Hour)
+	if cached, ok := s.exposures.Get(key); ok {
+		if snapshot := cached.(*exposureSnapshot); snapshot.dayStart.Equal(startOfDay) {
+			return snapshot, false, nil
+		}
+	}
+
+	exposure, err := s.repo.GetExposure(clientName, exchangeName, pair, startOfDay)
+	if err != nil {
+		return nil, false, err
+	}
+
+	snapshot := &exposureSnapshot{exposure: *exposure, dayStart: startOfDay}
+	s.exposures.Set(key, snapshot)
+	return snapshot, true, nil
+}
+
+// apply adds a saved fill to the snapshot.
+func (e *exposureSnapshot) apply(order *entity.HistoryOrder) {
+	signed := sideSign(order.Side) * order.BaseQty
+	e.exposure.Position += signed
+	e.exposure.Cost += signed * order.Price
+	e.exposure.Fees += order.CommissionQuoteQty
+	if !order.TimePlaced.Before(e.dayStart) {
+		e.exposure.DailyNotional += order.BaseQty * order.Price
+	}
+}
+
+// valued returns the state of an exposure with its position valued at mark.
+func valued(exposure entity.RiskExposure, mark float64) riskState {
+	return riskState{
+		position:      exposure.Position,
+		dailyNotional: exposure.DailyNotional,
+		pnl:           exposure.Position*mark - exposure.Cost - exposure.Fees,
+	}
+}
+
+/*
+limitBreaches compares the state after an order with the limits. Position and loss limits
+only count as breached when the order grows the position, so a client can always trade out of a breach.
+*/
+func limitBreaches(limit *entity.RiskLimit, before, after riskState, notional float64) []*entity.RiskBreach {
+	breaches := []*entity.RiskBreach{}
+	add := func(name string, threshold, value float64) {
+		if threshold > 0 && value > threshold {
+			breaches = append(breaches, &entity.RiskBreach{Limit: name, Threshold: threshold, Value: value})
+		}
+	}
+
+	grows := math.Abs(after.position) > math.Abs(before.position)
+
+	add(entity.RiskLimitMaxOrderNotional, limit.MaxOrderNotional, notional)
+	add(entity.RiskLimitMaxDailyNotional, limit.MaxDailyNotional, after.dailyNotional)
+	if grows {
+		add(entity.RiskLimitMaxPosition, limit.MaxPosition, math.Abs(after.position))
+		add(entity.RiskLimitMaxLoss, limit.MaxLoss, -after.pnl)
+	}
+
+	return breaches
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newRiskBook(bid, ask float64) *entity.OrderBook {
+	return &entity.OrderBook{
+		Asks: []entity.DepthOrder{{Price: ask, BaseQty: 1}},
+		Bids: []entity.DepthOrder{{Price: bid, BaseQty: 1}},
+	}
+}
+
+func TestCheckOrder(t *testing.T) {
+	limit := &entity.RiskLimit{
+		ClientName:       "client1",
+		ExchangeName:     "exchange1",
+		MaxPosition:      10,
+		MaxOrderNotional: 500,
+		MaxDailyNotional: 1000,
+		MaxLoss:          50,
+	}
+	// Long 8 bought at 100 on average, marked at 95: 40 lost, plus 5 in fees.
+	exposure := &entity.RiskExposure{Position: 8, Cost: 800, Fees: 5, DailyNotional: 600}
+
+	tests := []struct {
+		name     string
+		req      entity.RiskCheckRequest
+		allowed  bool
+		breaches []string
+	}{
+		{
+			name:    "within limits",
+			req:     entity.RiskCheckRequest{Side: entity.SideBuy, BaseQty: 1},
+			allowed: true,
+		},
+		{
+			name:     "grows position over limits",
+			req:      entity.RiskCheckRequest{Side: entity.SideBuy, BaseQty: 6, Price: 96},
+			breaches: []string{entity.RiskLimitMaxOrderNotional, entity.RiskLimitMaxDailyNotional, entity.RiskLimitMaxPosition, entity.RiskLimitMaxLoss},
+		},
+		{
+			name:    "reduces position",
+			req:     entity.RiskCheckRequest{Side: entity.SideSell, BaseQty: 4},
+			allowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockRiskRepository)
+			mockService := NewRiskService(mockRepo)
+
+			mockRepo.On("GetRiskLimit", "client1", "exchange1", "pair1").Return(limit, nil)
+			mockRepo.On("GetLatestOrderBook", "exchange1", "pair1").Return(newRiskBook(94, 96), nil)
+			mockRepo.On("GetExposure", "client1", "exchange1", "pair1", mock.Anything).Return(exposure, nil)
+
+			req := tt.req
+			req.ClientName, req.ExchangeName, req.Pair = "client1", "exchange1", "pair1"
+			check, err := mockService.CheckOrder(&req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.allowed, check.Allowed)
+			assert.Equal(t, 95.0, check.MarkPrice)
+			assert.Equal(t, -45.0, check.PnL)
+
+			var names []string
+			for _, b := range check.Breaches {
+				names = append(names, b.Limit)
+				assert.Equal(t, "client1", b.ClientName)
+			}
+			assert.Equal(t, tt.breaches, names)
+		})
+	}
+}
+
+func TestCheckOrder_NoLimits(t *testing.T) {
+	mockRepo := new(mocks.MockRiskRepository)
+	mockService := NewRiskService(mockRepo)
+
+	mockRepo.On("GetRiskLimit", "client1", "exchange1", "pair1").Return((*entity.RiskLimit)(nil), entity.ErrNotFound)
+
+	check, err := mockService.CheckOrder(&entity.RiskCheckRequest{ClientName: "client1", ExchangeName: "exchange1", Pair: "pair1", Side: entity.SideBuy, BaseQty: 1})
+
+	assert.NoError(t, err)
+	assert.True(t, check.Allowed)
+	mockRepo.AssertNotCalled(t, "GetExposure", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCheckOrder_NoMarkPrice(t *testing.T) {
+	mockRepo := new(mocks.MockRiskRepository)
+	mockService := NewRiskService(mockRepo)
+
+	mockRepo.On("GetRiskLimit", "client1", "exchange1", "pair1").Return(&entity.RiskLimit{MaxOrderNotional: 100}, nil)
+	mockRepo.On("GetLatestOrderBook", "exchange1", "pair1").Return((*entity.OrderBook)(nil), entity.ErrNotFound)
+
+	_, err := mockService.CheckOrder(&entity.RiskCheckRequest{ClientName: "client1", ExchangeName: "exchange1", Pair: "pair1", Side: entity.SideBuy, BaseQty: 1})
+
+	assert.ErrorIs(t, err, entity.ErrNoMarkPrice)
+}
+
+func TestEvaluateFill(t *testing.T) {
+	mockRepo := new(mocks.MockRiskRepository)
+	mockService := NewRiskService(mockRepo)
+
+	order := &entity.HistoryOrder{
+		OrderID:      "order1",
+		ClientName:   "client1",
+		ExchangeName: "exchange1",
+		Pair:         "pair1",
+		Side:         entity.SideBuy,
+		BaseQty:      3,
+		Price:        100,
+	}
+
+	mockRepo.On("GetRiskLimit", "client1", "exchange1", "pair1").Return(&entity.RiskLimit{MaxPosition: 10}, nil)
+	mockRepo.On("GetLatestOrderBook", "exchange1", "pair1").Return((*entity.OrderBook)(nil), entity.ErrNotFound)
+	// The exposure already includes the saved fill.
+	mockRepo.On("GetExposure", "client1", "exchange1", "pair1", mock.Anything).Return(&entity.RiskExposure{Position: 12, Cost: 1200}, nil)
+	mockRepo.On("SaveRiskBreaches", mock.MatchedBy(func(b []*entity.RiskBreach) bool {
+		return len(b) == 1 && b[0].Limit == entity.RiskLimitMaxPosition && b[0].Value == 12 && b[0].OrderID == "order1"
+	})).Return(nil)
+
+	breaches, err := mockService.EvaluateFill(order)
+
+	assert.NoError(t, err)
+	assert.Len(t, breaches, 1)
+	mockRepo.AssertExpectations(t)
+
+	// The next fill is added to the snapshot instead of summing history_orders again.
+	order.OrderID, order.Side, order.BaseQty = "order2", entity.SideSell, 4
+	mockRepo.On("SaveRiskBreaches", []*entity.RiskBreach{}).Return(nil)
+
+	breaches, err = mockService.EvaluateFill(order)
+
+	assert.NoError(t, err)
+	assert.Empty(t, breaches)
+	mockRepo.AssertNumberOfCalls(t, "GetExposure", 1)
+}
@@ -1,23 +1,37 @@
 package service
 
 import (
+	"log"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/cache"
 	"github.com/egorque1/vortex-test/internal/entity"
 	"github.com/egorque1/vortex-test/internal/modules/repository"
+	"github.com/google/uuid"
 )
 
+// dedupTTL is how long saved dedup keys are remembered in process before only storage is consulted.
+const dedupTTL = 10 * time.Minute
+
 type OrderService interface {
 	GetOrderBook(exchange_name, pair string) ([]*entity.OrderBook, error)
-	SaveOrderBook(orderBook []*entity.OrderBook) error
+	SaveOrderBook(orderBook []*entity.OrderBook) (*entity.SaveResult, error)
+	SaveOrderBookBatch(orderBook []*entity.OrderBook) (*entity.SaveResult, error)
 	GetOrderHistory(client *entity.Client) ([]*entity.HistoryOrder, error)
-	SaveOrderHistory(order entity.HistoryOrder) error
+	FindOrderHistory(filter *entity.HistoryFilter) ([]*entity.HistoryOrder, error)
+	SaveOrderHistory(order entity.HistoryOrder) (*entity.SaveResult, error)
 }
 
 type orderServiceImpl struct {
-	repo repository.OrderRepository
+	repo    repository.OrderRepository
+	clients ClientService
+	risk    RiskService
+	stream  StreamService
+	seen    *cache.Cache
 }
 
-func NewOrderService(repo repository.OrderRepository) OrderService {
-	return &orderServiceImpl{repo: repo}
+func NewOrderService(repo repository.OrderRepository, clients ClientService, risk RiskService, stream StreamService) OrderService {
+	return &orderServiceImpl{repo: repo, clients: clients, risk: risk, stream: stream, seen: cache.New(dedupTTL)}
 }
 
 /*
@@ -31,11 +45,57 @@ func (s *orderServiceImpl) GetOrderBook(exchange_name, pair string) ([]*entity.O
 
 /*
 SaveOrderBook saves the order book to ClickHouse.
+Snapshots without a timestamp are stamped with the time they were received.
+Snapshots whose natural key was already saved are skipped and reported as duplicates.
+Saved snapshots are published to the subscribers of their books.
 Returns an error if one occures.
 */
 
-func (s *orderServiceImpl) SaveOrderBook(orderBook []*entity.OrderBook) error {
-	return s.repo.SaveOrderBook(orderBook)
+func (s *orderServiceImpl) SaveOrderBook(orderBook []*entity.OrderBook) (*entity.SaveResult, error) {
+	return s.saveOrderBooks(orderBook, s.repo.SaveOrderBook)
+}
+
+/*
+SaveOrderBookBatch saves the order book to ClickHouse like SaveOrderBook, with a single insert.
+Returns an error if one occures, in which case nothing is saved.
+*/
+
+func (s *orderServiceImpl) SaveOrderBookBatch(orderBook []*entity.OrderBook) (*entity.SaveResult, error) {
+	return s.saveOrderBooks(orderBook, s.repo.SaveOrderBookBatch)
+}
+
+func (s *orderServiceImpl) saveOrderBooks(orderBook []*entity.OrderBook, save func([]*entity.OrderBook) error) (*entity.SaveResult, error) {
+	now := time.Now()
+	keys := make([]string, len(orderBook))
+	for i, ob := range orderBook {
+		if ob.Timestamp.IsZero() {
+			ob.Timestamp = now
+		}
+		keys[i] = ob.NaturalKey()
+	}
+
+	fresh, duplicates, err := s.dedup("orderbook:", keys, s.repo.GetExistingOrderBookKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	books := make([]*entity.OrderBook, 0, len(orderBook))
+	for i, ob := range orderBook {
+		if fresh[i] {
+			ob.DedupKey = dedupKeyOrRandom(keys[i])
+			books = append(books, ob)
+		}
+	}
+
+	if len(books) > 0 {
+		if err := save(books); err != nil {
+			return nil, err
+		}
+		s.stream.PublishOrderBooks(books)
+	}
+	s.remember("orderbook:", keys, fresh)
+
+	return &entity.SaveResult{Saved: len(books), Duplicates: duplicates}, nil
 }
 
 /*
@@ -47,10 +107,115 @@ func (s *orderServiceImpl) GetOrderHistory(client *entity.Client) ([]*entity.His
 	return s.repo.GetOrderHistory(client)
 }
 
+/*
+FindOrderHistory returns the fills matching a filter, oldest first.
+Also returns an error if one occures.
+*/
+
+func (s *orderServiceImpl) FindOrderHistory(filter *entity.HistoryFilter) ([]*entity.HistoryOrder, error) {
+	return s.repo.FindOrderHistory(filter)
+}
+
 /*
 SaveOrderHistory saves the order history to ClickHouse.
+Orders of unregistered client accounts are rejected with entity.ErrUnregisteredClient.
+An order whose natural key was already saved is skipped and reported as a duplicate.
+A commission charged in the base or quote asset is also stored valued in quote.
+Saved fills are published to the fills stream.
+The risk limits of the client are evaluated after the save and any breaches are reported with the result;
+the fill has already happened, so a failed evaluation is logged and does not fail the save.
 Returns an error if one occures.
 */
-func (s *orderServiceImpl) SaveOrderHistory(order entity.HistoryOrder) error {
-	return s.repo.SaveOrderHistory(order)
+func (s *orderServiceImpl) SaveOrderHistory(order entity.HistoryOrder) (*entity.SaveResult, error) {
+	if err := s.clients.ValidateOrder(&order); err != nil {
+		return nil, err
+	}
+
+	keys := []string{order.NaturalKey()}
+
+	fresh, duplicates, err := s.dedup("history:", keys, s.repo.GetExistingHistoryOrderKeys)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh[0] {
+		return &entity.SaveResult{Duplicates: duplicates}, nil
+	}
+
+	order.DedupKey = dedupKeyOrRandom(keys[0])
+	order.NormalizeCommission()
+	if err := s.repo.SaveOrderHistory(order); err != nil {
+		return nil, err
+	}
+	s.remember("history:", keys, fresh)
+	s.stream.PublishFill(&order)
+
+	breaches, err := s.risk.EvaluateFill(&order)
+	if err != nil {
+		log.Printf("error evaluating risk limits of order %s: %v", order.DedupKey, err)
+	}
+
+	return &entity.SaveResult{Saved: 1, Duplicates: duplicates, RiskBreaches: breaches}, nil
+}
+
+/*
+dedup reports which records are new, checking their keys against earlier records in the batch,
+keys saved recently by this process and keys already in storage.
+Records without a key are always new. Duplicate keys are returned in record order.
+ReplacingMergeTree storage collapses whatever slips through concurrent saves.
+*/
+func (s *orderServiceImpl) dedup(prefix string, keys []string, existing func([]string) ([]string, error)) ([]bool, []string, error) {
+	fresh := make([]bool, len(keys))
+	inBatch := make(map[string]bool)
+	var lookup []string
+	for i, k := range keys {
+		if k == "" {
+			fresh[i] = true
+			continue
+		}
+		if _, ok := s.seen.Get(prefix + k); ok || inBatch[k] {
+			continue
+		}
+		inBatch[k] = true
+		fresh[i] = true
+		lookup = append(lookup, k)
+	}
+
+	stored := make(map[string]bool)
+	if len(lookup) > 0 {
+		found, err := existing(lookup)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, k := range found {
+			stored[k] = true
+		}
+	}
+
+	duplicates := []string{}
+	for i, k := range keys {
+		if fresh[i] && stored[k] {
+			fresh[i] = false
+		}
+		if !fresh[i] {
+			duplicates = append(duplicates, k)
+		}
+	}
+
+	return fresh, duplicates, nil
+}
+
+func (s *orderServiceImpl) remember(prefix string, keys []string, fresh []bool) {
+	for i, k := range keys {
+		if fresh[i] && k != "" {
+			s.seen.Set(prefix+k, true)
+		}
+	}
+}
+
+// dedupKeyOrRandom gives records without a natural key a unique key so storage never collapses them.
+func dedupKeyOrRandom(key string) string {
+	if key == "" {
+		return uuid.NewString()
+	}
+	return key
 }
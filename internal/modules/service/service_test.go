@@ -7,11 +7,12 @@ import (
 	"github.com/egorque1/vortex-test/internal/entity"
 	"github.com/egorque1/vortex-test/internal/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetOrderBook(t *testing.T) {
 	mockRepo := new(mocks.MockOrderRepository)
-	mockService := NewOrderService(mockRepo)
+	mockService := NewOrderService(mockRepo, new(mocks.MockClientService), new(mocks.MockRiskService), new(mocks.MockStreamService))
 
 	exchange := "exchange1"
 	pair := "pair1"
@@ -38,7 +39,8 @@ func TestGetOrderBook(t *testing.T) {
 
 func TestSaveOrderBook(t *testing.T) {
 	mockRepo := new(mocks.MockOrderRepository)
-	mockService := NewOrderService(mockRepo)
+	mockStream := new(mocks.MockStreamService)
+	mockService := NewOrderService(mockRepo, new(mocks.MockClientService), new(mocks.MockRiskService), mockStream)
 
 	orderBook := []*entity.OrderBook{
 		{
@@ -51,17 +53,42 @@ func TestSaveOrderBook(t *testing.T) {
 	}
 
 	mockRepo.On("SaveOrderBook", orderBook).Return(nil)
+	mockStream.On("PublishOrderBooks", orderBook).Return()
 
-	err := mockService.SaveOrderBook(orderBook)
+	result, err := mockService.SaveOrderBook(orderBook)
 
 	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Saved)
+	assert.NotEmpty(t, orderBook[0].DedupKey)
 
 	mockRepo.AssertExpectations(t)
+	mockStream.AssertExpectations(t)
+}
+
+func TestSaveOrderBookBatch(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	mockStream := new(mocks.MockStreamService)
+	mockService := NewOrderService(mockRepo, new(mocks.MockClientService), new(mocks.MockRiskService), mockStream)
+
+	orderBook := []*entity.OrderBook{{Exchange: "exchange1", Pair: "pair1"}, {Exchange: "exchange1", Pair: "pair2"}}
+
+	mockRepo.On("SaveOrderBookBatch", orderBook).Return(nil)
+	mockStream.On("PublishOrderBooks", orderBook).Return()
+
+	result, err := mockService.SaveOrderBookBatch(orderBook)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Saved)
+	assert.False(t, orderBook[0].Timestamp.IsZero())
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "SaveOrderBook", mock.Anything)
+	mockStream.AssertExpectations(t)
 }
 
 func TestGetOrderHistory(t *testing.T) {
 	mockRepo := new(mocks.MockOrderRepository)
-	mockService := NewOrderService(mockRepo)
+	mockService := NewOrderService(mockRepo, new(mocks.MockClientService), new(mocks.MockRiskService), new(mocks.MockStreamService))
 
 	client := &entity.Client{
 		ClientName:   "client1",
@@ -101,7 +128,10 @@ func TestGetOrderHistory(t *testing.T) {
 
 func TestSaveOrderHistory(t *testing.T) {
 	mockRepo := new(mocks.MockOrderRepository)
-	mockService := NewOrderService(mockRepo)
+	mockClients := new(mocks.MockClientService)
+	mockRisk := new(mocks.MockRiskService)
+	mockStream := new(mocks.MockStreamService)
+	mockService := NewOrderService(mockRepo, mockClients, mockRisk, mockStream)
 
 	order := entity.HistoryOrder{
 		ClientName:          "client1",
@@ -119,11 +149,124 @@ func TestSaveOrderHistory(t *testing.T) {
 		TimePlaced:          time.Now(),
 	}
 
-	mockRepo.On("SaveOrderHistory", order).Return(nil)
+	mockClients.On("ValidateOrder", mock.Anything).Return(nil)
+	mockRepo.On("SaveOrderHistory", mock.MatchedBy(func(o entity.HistoryOrder) bool {
+		return o.ClientName == order.ClientName && o.DedupKey != ""
+	})).Return(nil)
+	breaches := []*entity.RiskBreach{{Limit: entity.RiskLimitMaxPosition, Threshold: 10, Value: 11}}
+	mockRisk.On("EvaluateFill", mock.Anything).Return(breaches, nil)
+	mockStream.On("PublishFill", mock.MatchedBy(func(o *entity.HistoryOrder) bool {
+		return o.ClientName == order.ClientName && o.DedupKey != ""
+	})).Return()
 
-	err := mockService.SaveOrderHistory(order)
+	result, err := mockService.SaveOrderHistory(order)
 
 	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Saved)
+	assert.Equal(t, breaches, result.RiskBreaches)
+
+	mockRepo.AssertExpectations(t)
+	mockStream.AssertExpectations(t)
+}
+
+func TestSaveOrderHistory_BaseCommission(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	mockClients := new(mocks.MockClientService)
+	mockRisk := new(mocks.MockRiskService)
+	mockStream := new(mocks.MockStreamService)
+	mockService := NewOrderService(mockRepo, mockClients, mockRisk, mockStream)
+
+	order := entity.HistoryOrder{
+		ClientName:      "client1",
+		ExchangeName:    "exchange1",
+		Pair:            "BTC/USDT",
+		Side:            entity.SideBuy,
+		Type:            entity.OrderTypeMarket,
+		BaseQty:         0.5,
+		Price:           60000,
+		CommissionAsset: "btc",
+		CommissionQty:   0.0005,
+	}
+
+	mockClients.On("ValidateOrder", mock.Anything).Return(nil)
+	mockRepo.On("SaveOrderHistory", mock.MatchedBy(func(o entity.HistoryOrder) bool {
+		return o.CommissionAsset == "btc" && o.CommissionQty == 0.0005 && o.CommissionQuoteQty == 30
+	})).Return(nil)
+	mockRisk.On("EvaluateFill", mock.Anything).Return([]*entity.RiskBreach(nil), nil)
+	mockStream.On("PublishFill", mock.Anything).Return()
+
+	result, err := mockService.SaveOrderHistory(order)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Saved)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSaveOrderBook_Duplicates(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	mockStream := new(mocks.MockStreamService)
+	mockService := NewOrderService(mockRepo, new(mocks.MockClientService), new(mocks.MockRiskService), mockStream)
+
+	orderBook := []*entity.OrderBook{
+		{Exchange: "exchange1", Pair: "pair1", Sequence: 1},
+		{Exchange: "exchange1", Pair: "pair1", Sequence: 2},
+		{Exchange: "exchange1", Pair: "pair1", Sequence: 2},
+		{Exchange: "exchange1", Pair: "pair1"},
+	}
 
+	mockRepo.On("GetExistingOrderBookKeys", []string{"exchange1:pair1:1", "exchange1:pair1:2"}).Return([]string{"exchange1:pair1:1"}, nil)
+	mockRepo.On("SaveOrderBook", []*entity.OrderBook{orderBook[1], orderBook[3]}).Return(nil)
+	// Only the saved snapshots are published.
+	mockStream.On("PublishOrderBooks", []*entity.OrderBook{orderBook[1], orderBook[3]}).Return().Once()
+
+	result, err := mockService.SaveOrderBook(orderBook)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Saved)
+	assert.Equal(t, []string{"exchange1:pair1:1", "exchange1:pair1:2"}, result.Duplicates)
+	assert.Equal(t, "exchange1:pair1:2", orderBook[1].DedupKey)
 	mockRepo.AssertExpectations(t)
+
+	// A retry of the saved snapshot is caught by the in-process cache without touching storage.
+	result, err = mockService.SaveOrderBook([]*entity.OrderBook{{Exchange: "exchange1", Pair: "pair1", Sequence: 2}})
+
+	assert.NoError(t, err)
+	assert.Zero(t, result.Saved)
+	assert.Equal(t, []string{"exchange1:pair1:2"}, result.Duplicates)
+	mockRepo.AssertNumberOfCalls(t, "GetExistingOrderBookKeys", 1)
+	mockRepo.AssertNumberOfCalls(t, "SaveOrderBook", 1)
+}
+
+func TestSaveOrderHistory_Duplicate(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	mockClients := new(mocks.MockClientService)
+	mockService := NewOrderService(mockRepo, mockClients, new(mocks.MockRiskService), new(mocks.MockStreamService))
+
+	order := entity.HistoryOrder{OrderID: "order1", FillID: "fill1"}
+
+	mockClients.On("ValidateOrder", &order).Return(nil)
+	mockRepo.On("GetExistingHistoryOrderKeys", []string{"order1:fill1"}).Return([]string{"order1:fill1"}, nil)
+
+	result, err := mockService.SaveOrderHistory(order)
+
+	assert.NoError(t, err)
+	assert.Zero(t, result.Saved)
+	assert.Equal(t, []string{"order1:fill1"}, result.Duplicates)
+	mockRepo.AssertNotCalled(t, "SaveOrderHistory", mock.Anything)
+}
+
+func TestSaveOrderHistory_UnregisteredClient(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	mockClients := new(mocks.MockClientService)
+	mockService := NewOrderService(mockRepo, mockClients, new(mocks.MockRiskService), new(mocks.MockStreamService))
+
+	order := entity.HistoryOrder{ClientName: "clinet1", ExchangeName: "exchange1", Label: "label1"}
+
+	mockClients.On("ValidateOrder", &order).Return(entity.ErrUnregisteredClient)
+
+	result, err := mockService.SaveOrderHistory(order)
+
+	assert.ErrorIs(t, err, entity.ErrUnregisteredClient)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "SaveOrderHistory", mock.Anything)
 }
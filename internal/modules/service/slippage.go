@@ -0,0 +1,151 @@
+package service
+
+import (
+	"math"
+	"sort"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+)
+
+/*
+GetSlippageAnalysis computes per-order slippage versus mid and versus the touch recorded at placement
+and returns their distributions grouped by exchange, pair and algorithm.
+Orders without a recognised side or a recorded top of book are left out.
+Also returns an error if one occures.
+*/
+
+func (s *analyticsServiceImpl) GetSlippageAnalysis(req *entity.SlippageRequest) ([]*entity.SlippageAnalysis, error) {
+	orders, err := s.repo.GetHistoryOrders(&req.HistoryFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	type groupKey struct{ exchange, pair, algorithm string }
+
+	groups := make(map[groupKey][]entity.OrderSlippage)
+	for _, o := range orders {
+		slippage, ok := orderSlippage(o)
+		if !ok {
+			continue
+		}
+		key := groupKey{o.ExchangeName, o.Pair, o.AlgorithmNamePlaced}
+		groups[key] = append(groups[key], slippage)
+	}
+
+	result := make([]*entity.SlippageAnalysis, 0, len(groups))
+	for key, slippages := range groups {
+		analysis := &entity.SlippageAnalysis{
+			ExchangeName:        key.exchange,
+			Pair:                key.pair,
+			AlgorithmNamePlaced: key.algorithm,
+			OrderCount:          len(slippages),
+		}
+
+		mid := make([]float64, 0, len(slippages))
+		touch := make([]float64, 0, len(slippages))
+		for _, sl := range slippages {
+			mid = append(mid, sl.MidSlippageBps)
+			touch = append(touch, sl.TouchSlippageBps)
+			if sl.OutsideSpread {
+				analysis.OutsideSpreadCount++
+			}
+		}
+		analysis.MidSlippage = newDistribution(mid, req.BucketBps)
+		analysis.TouchSlippage = newDistribution(touch, req.BucketBps)
+
+		if req.IncludeOrders {
+			analysis.Orders = slippages
+		}
+		result = append(result, analysis)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.ExchangeName != b.ExchangeName {
+			return a.ExchangeName < b.ExchangeName
+		}
+		if a.Pair != b.Pair {
+			return a.Pair < b.Pair
+		}
+		return a.AlgorithmNamePlaced < b.AlgorithmNamePlaced
+	})
+
+	return result, nil
+}
+
+/*
+orderSlippage measures an order against the top of book recorded at placement.
+Positive slippage means the order paid more (buys) or received less (sells) than the reference.
+The second result is false when the side is unknown or either side of the book is missing.
+*/
+func orderSlippage(o *entity.HistoryOrder) (entity.OrderSlippage, bool) {
+	sign := sideSign(o.Side)
+	if sign == 0 || o.LowestSellPrc <= 0 || o.HighestBuyPrc <= 0 {
+		return entity.OrderSlippage{}, false
+	}
+
+	touch, _ := touchSlippageBps(o)
+	mid := (o.LowestSellPrc + o.HighestBuyPrc) / 2
+
+	return entity.OrderSlippage{
+		ExchangeName:        o.ExchangeName,
+		Pair:                o.Pair,
+		AlgorithmNamePlaced: o.AlgorithmNamePlaced,
+		Side:                o.Side,
+		Price:               o.Price,
+		MidPrice:            mid,
+		MidSlippageBps:      sign * (o.Price - mid) / mid * 10000,
+		TouchSlippageBps:    touch,
+		OutsideSpread:       o.Price > o.LowestSellPrc || o.Price < o.HighestBuyPrc,
+		TimePlaced:          o.TimePlaced,
+	}, true
+}
+
+// newDistribution summarises values with percentiles and a sparse histogram of bucketWidth-wide buckets.
+func newDistribution(values []float64, bucketWidth float64) entity.Distribution {
+	if len(values) == 0 {
+		return entity.Distribution{}
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	counts := make(map[float64]int)
+	for _, v := range sorted {
+		sum += v
+		counts[math.Floor(v/bucketWidth)]++
+	}
+
+	histogram := make([]entity.HistogramBucket, 0, len(counts))
+	for idx, count := range counts {
+		histogram = append(histogram, entity.HistogramBucket{
+			LowerBps: idx * bucketWidth,
+			UpperBps: (idx + 1) * bucketWidth,
+			Count:    count,
+		})
+	}
+	sort.Slice(histogram, func(i, j int) bool { return histogram[i].LowerBps < histogram[j].LowerBps })
+
+	return entity.Distribution{
+		Mean:      sum / float64(len(sorted)),
+		Min:       sorted[0],
+		P50:       percentile(sorted, 50),
+		P90:       percentile(sorted, 90),
+		P95:       percentile(sorted, 95),
+		P99:       percentile(sorted, 99),
+		Max:       sorted[len(sorted)-1],
+		Histogram: histogram,
+	}
+}
+
+// percentile interpolates the p-th percentile of an ascending, non-empty slice.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSlippageAnalysis(t *testing.T) {
+	mockRepo := new(mocks.MockAnalyticsRepository)
+	mockService := NewAnalyticsService(mockRepo)
+
+	orders := []*entity.HistoryOrder{
+		{ExchangeName: "exchange1", Pair: "pair1", AlgorithmNamePlaced: "algo1", Side: "buy", Price: 100, LowestSellPrc: 100, HighestBuyPrc: 98},
+		{ExchangeName: "exchange1", Pair: "pair1", AlgorithmNamePlaced: "algo1", Side: "buy", Price: 101, LowestSellPrc: 100, HighestBuyPrc: 98},
+		{ExchangeName: "exchange1", Pair: "pair1", AlgorithmNamePlaced: "algo2", Side: "sell", Price: 98, LowestSellPrc: 100, HighestBuyPrc: 98},
+		{ExchangeName: "exchange1", Pair: "pair1", AlgorithmNamePlaced: "algo2", Side: "sell", Price: 98},
+	}
+
+	req := &entity.SlippageRequest{BucketBps: 50, IncludeOrders: true}
+	mockRepo.On("GetHistoryOrders", &req.HistoryFilter).Return(orders, nil)
+
+	result, err := mockService.GetSlippageAnalysis(req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+
+	algo1 := result[0]
+	assert.Equal(t, "algo1", algo1.AlgorithmNamePlaced)
+	assert.Equal(t, 2, algo1.OrderCount)
+	assert.Equal(t, 1, algo1.OutsideSpreadCount)
+	assert.InDelta(t, 50, algo1.TouchSlippage.Mean, 1e-9)
+	assert.InDelta(t, 100, algo1.TouchSlippage.Max, 1e-9)
+	assert.InDelta(t, 101.0101010101, algo1.MidSlippage.Min, 1e-6)
+	assert.Equal(t, []entity.HistogramBucket{
+		{LowerBps: 0, UpperBps: 50, Count: 1},
+		{LowerBps: 100, UpperBps: 150, Count: 1},
+	}, algo1.TouchSlippage.Histogram)
+	assert.Len(t, algo1.Orders, 2)
+
+	algo2 := result[1]
+	assert.Equal(t, 1, algo2.OrderCount)
+	assert.Zero(t, algo2.OutsideSpreadCount)
+	assert.InDelta(t, 101.0101010101, algo2.MidSlippage.P50, 1e-6)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5}
+
+	assert.Equal(t, 1.0, percentile(sorted, 0))
+	assert.Equal(t, 3.0, percentile(sorted, 50))
+	assert.InDelta(t, 4.6, percentile(sorted, 90), 1e-9)
+	assert.Equal(t, 5.0, percentile(sorted, 100))
+}
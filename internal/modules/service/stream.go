@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+	"github.com/egorque1/vortex-test/internal/pubsub"
+)
+
+const (
+	// orderBookStreamBuffer is the number of updates a subscriber may fall behind before it is dropped.
+	orderBookStreamBuffer = 256
+	// fillStreamBuffer is the number of fills a subscriber may fall behind before it is dropped.
+	fillStreamBuffer = 256
+	// fillReplaySize is the number of the latest fills kept for subscribers resuming after a disconnect.
+	fillReplaySize = 1000
+)
+
+type StreamService interface {
+	PublishOrderBooks(books []*entity.OrderBook)
+	SubscribeOrderBooks(keys []entity.BookKey) (*pubsub.Subscription[*entity.OrderBook], []*entity.OrderBook, error)
+	PublishFill(order *entity.HistoryOrder)
+	SubscribeFills(lastEventID uint64) (*pubsub.Subscription[*entity.FillEvent], []*entity.FillEvent)
+}
+
+type streamServiceImpl struct {
+	repo  repository.OrderRepository
+	books *pubsub.Broker[*entity.OrderBook]
+	fills *pubsub.Broker[*entity.FillEvent]
+
+	// epoch is chosen at random when the process starts and makes up the upper 32 bits of its fill IDs,
+	// so an ID handed out by another process or before a restart is never mistaken for one of ours.
+	epoch uint64
+
+	// mu orders the fill IDs, the replay buffer and the subscriptions to fills.
+	mu         sync.Mutex
+	lastFillID uint64
+	replay     []*entity.FillEvent
+}
+
+func NewStreamService(repo repository.OrderRepository) StreamService {
+	epoch := uint64(rand.Uint32N(1<<31)) + 1
+	return &streamServiceImpl{
+		repo:       repo,
+		books:      pubsub.New[*entity.OrderBook](orderBookStreamBuffer),
+		fills:      pubsub.New[*entity.FillEvent](fillStreamBuffer),
+		epoch:      epoch,
+		lastFillID: epoch << 32,
+	}
+}
+
+// PublishOrderBooks sends saved order book snapshots to the subscribers of their books.
+func (s *streamServiceImpl) PublishOrderBooks(books []*entity.OrderBook) {
+	for _, book := range books {
+		s.books.Publish(entity.BookKey{Exchange: book.Exchange, Pair: book.Pair}.Topic(), book)
+	}
+}
+
+/*
+SubscribeOrderBooks subscribes to the updates of books and returns the latest stored snapshot of each,
+leaving out books that have none. The subscription starts before the snapshots are read so no update
+is missed; updates that do not follow the snapshot of their book may still arrive and can be skipped.
+The caller closes the subscription. Also returns an error if one occures.
+*/
+
+func (s *streamServiceImpl) SubscribeOrderBooks(keys []entity.BookKey) (*pubsub.Subscription[*entity.OrderBook], []*entity.OrderBook, error) {
+	topics := make([]string, len(keys))
+	for i, key := range keys {
+		topics[i] = key.Topic()
+	}
+	sub := s.books.Subscribe(topics...)
+
+	snapshots := make([]*entity.OrderBook, 0, len(keys))
+	for _, key := range keys {
+		book, err := s.repo.GetLatestOrderBook(key.Exchange, key.Pair)
+		if errors.Is(err, entity.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			sub.Close()
+			return nil, nil, err
+		}
+		snapshots = append(snapshots, book)
+	}
+
+	return sub, snapshots, nil
+}
+
+// PublishFill numbers a saved fill, keeps it for replay and sends it to the subscribers of fills.
+func (s *streamServiceImpl) PublishFill(order *entity.HistoryOrder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastFillID++
+	event := &entity.FillEvent{ID: s.lastFillID, Order: order}
+
+	if len(s.replay) == fillReplaySize {
+		copy(s.replay, s.replay[1:])
+		s.replay = s.replay[:fillReplaySize-1]
+	}
+	s.replay = append(s.replay, event)
+
+	s.fills.Publish("", event)
+}
+
+/*
+SubscribeFills subscribes to the fills saved from now on and returns the kept fills saved after lastEventID,
+oldest first, so a subscriber resuming with the ID of the last fill it received misses none still kept.
+An ID of zero, one from another epoch because the process restarted or the subscriber reconnected to another
+instance, or one newer than any fill replays every kept fill.
+The caller closes the subscription.
+*/
+func (s *streamServiceImpl) SubscribeFills(lastEventID uint64) (*pubsub.Subscription[*entity.FillEvent], []*entity.FillEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lastEventID>>32 != s.epoch || lastEventID > s.lastFillID {
+		lastEventID = 0
+	}
+
+	var missed []*entity.FillEvent
+	for i, event := range s.replay {
+		if event.ID > lastEventID {
+			missed = append(missed, s.replay[i:]...)
+			break
+		}
+	}
+
+	return s.fills.Subscribe(""), missed
+}
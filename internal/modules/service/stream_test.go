@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribeOrderBooks(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	svc := NewStreamService(mockRepo)
+
+	latest := &entity.OrderBook{Exchange: "exchange1", Pair: "BTC/USDT", Sequence: 1}
+	mockRepo.On("GetLatestOrderBook", "exchange1", "btc/usdt").Return(latest, nil)
+	mockRepo.On("GetLatestOrderBook", "exchange1", "ETH/USDT").Return((*entity.OrderBook)(nil), entity.ErrNotFound)
+
+	sub, snapshots, err := svc.SubscribeOrderBooks([]entity.BookKey{{Exchange: "exchange1", Pair: "btc/usdt"}, {Exchange: "exchange1", Pair: "ETH/USDT"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []*entity.OrderBook{latest}, snapshots)
+	defer sub.Close()
+
+	update := &entity.OrderBook{Exchange: "exchange1", Pair: "BTC/USDT", Sequence: 2}
+	svc.PublishOrderBooks([]*entity.OrderBook{update, {Exchange: "exchange2", Pair: "BTC/USDT", Sequence: 3}})
+
+	assert.Equal(t, update, <-sub.C)
+	assert.Len(t, sub.C, 0)
+
+	mockRepo.On("GetLatestOrderBook", "exchange1", "SOL/USDT").Return((*entity.OrderBook)(nil), errors.New("db error"))
+
+	_, _, err = svc.SubscribeOrderBooks([]entity.BookKey{{Exchange: "exchange1", Pair: "SOL/USDT"}})
+	assert.Error(t, err)
+}
+
+func TestSubscribeFills(t *testing.T) {
+	svc := NewStreamService(new(mocks.MockOrderRepository))
+	id := func(n uint64) uint64 { return svc.(*streamServiceImpl).epoch<<32 | n }
+
+	for i := 0; i < fillReplaySize+2; i++ {
+		svc.PublishFill(&entity.HistoryOrder{FillID: "fill"})
+	}
+
+	sub, missed := svc.SubscribeFills(id(fillReplaySize))
+	defer sub.Close()
+	assert.Len(t, missed, 2)
+	assert.Equal(t, id(fillReplaySize+1), missed[0].ID)
+
+	// Fills older than the replay buffer are gone.
+	resumed, missed := svc.SubscribeFills(id(1))
+	resumed.Close()
+	assert.Len(t, missed, fillReplaySize)
+	assert.Equal(t, id(3), missed[0].ID)
+
+	// An ID from before a restart or from another instance replays everything kept, even if it looks newer.
+	for _, other := range []uint64{fillReplaySize + 2, (svc.(*streamServiceImpl).epoch+1)<<32 | 1, id(fillReplaySize + 3)} {
+		resumed, missed = svc.SubscribeFills(other)
+		resumed.Close()
+		assert.Len(t, missed, fillReplaySize)
+	}
+
+	resumed, missed = svc.SubscribeFills(id(fillReplaySize + 2))
+	resumed.Close()
+	assert.Empty(t, missed)
+
+	order := &entity.HistoryOrder{FillID: "fill2"}
+	svc.PublishFill(order)
+	assert.Equal(t, &entity.FillEvent{ID: id(fillReplaySize + 3), Order: order}, <-sub.C)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/parquet-go/parquet-go"
+)
+
+type columnKind int
+
+const (
+	stringColumn columnKind = iota
+	int64Column
+	float64Column
+	timeColumn
+)
+
+type column struct {
+	name string
+	kind columnKind
+}
+
+// parquetRowGroupSize is the number of rows buffered before a Parquet row group is written out.
+const parquetRowGroupSize = 10000
+
+/*
+tableWriter writes rows of a fixed set of columns.
+A nil value is written as an empty CSV field or a Parquet null.
+*/
+type tableWriter interface {
+	WriteRow(values []any) error
+	Close() error
+}
+
+func newTableWriter(format string, w io.Writer, columns []column) (tableWriter, error) {
+	switch format {
+	case entity.ExportFormatCSV:
+		return newCSVTableWriter(w, columns)
+	case entity.ExportFormatParquet:
+		return newParquetTableWriter(w, columns), nil
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+type csvTableWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVTableWriter(w io.Writer, columns []column) (*csvTableWriter, error) {
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.name
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &csvTableWriter{w: cw, record: make([]string, len(columns))}, nil
+}
+
+func (t *csvTableWriter) WriteRow(values []any) error {
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+			t.record[i] = ""
+		case string:
+			t.record[i] = v
+		case int64:
+			t.record[i] = strconv.FormatInt(v, 10)
+		case float64:
+			t.record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case time.Time:
+			t.record[i] = v.UTC().Format(time.RFC3339Nano)
+		default:
+			return fmt.Errorf("unsupported value type %T", v)
+		}
+	}
+	return t.w.Write(t.record)
+}
+
+func (t *csvTableWriter) Close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// parquetTableWriter stores every column as optional so levels missing from a book can be left null.
+type parquetTableWriter struct {
+	w       *parquet.Writer
+	indexes []int
+	row     parquet.Row
+	rows    int
+}
+
+func newParquetTableWriter(w io.Writer, columns []column) *parquetTableWriter {
+	group := parquet.Group{}
+	for _, c := range columns {
+		var node parquet.Node
+		switch c.kind {
+		case stringColumn:
+			node = parquet.String()
+		case int64Column:
+			node = parquet.Int(64)
+		case float64Column:
+			node = parquet.Leaf(parquet.DoubleType)
+		case timeColumn:
+			node = parquet.Timestamp(parquet.Millisecond)
+		}
+		group[c.name] = parquet.Optional(node)
+	}
+	schema := parquet.NewSchema("export", group)
+
+	// The schema orders its columns by name, the row is laid out in that order.
+	indexes := make([]int, len(columns))
+	for i, c := range columns {
+		leaf, _ := schema.Lookup(c.name)
+		indexes[i] = leaf.ColumnIndex
+	}
+
+	return &parquetTableWriter{
+		w:       parquet.NewWriter(w, schema),
+		indexes: indexes,
+		row:     make(parquet.Row, len(columns)),
+	}
+}
+
+func (t *parquetTableWriter) WriteRow(values []any) error {
+	for i, v := range values {
+		var value parquet.Value
+		switch v := v.(type) {
+		case nil:
+			t.row[t.indexes[i]] = parquet.NullValue().Level(0, 0, t.indexes[i])
+			continue
+		case string:
+			value = parquet.ByteArrayValue([]byte(v))
+		case int64:
+			value = parquet.Int64Value(v)
+		case float64:
+			value = parquet.DoubleValue(v)
+		case time.Time:
+			value = parquet.Int64Value(v.UnixMilli())
+		default:
+			return fmt.Errorf("unsupported value type %T", v)
+		}
+		t.row[t.indexes[i]] = value.Level(0, 1, t.indexes[i])
+	}
+
+	if _, err := t.w.WriteRows([]parquet.Row{t.row}); err != nil {
+		return err
+	}
+
+	t.rows++
+	if t.rows%parquetRowGroupSize == 0 {
+		return t.w.Flush()
+	}
+	return nil
+}
+
+func (t *parquetTableWriter) Close() error {
+	return t.w.Close()
+}
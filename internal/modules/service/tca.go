@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/modules/repository"
+)
+
+type TCAService interface {
+	GetTCAReports(req *entity.TCARequest) ([]*entity.TCAReport, error)
+}
+
+type tcaServiceImpl struct {
+	repo repository.AnalyticsRepository
+}
+
+func NewTCAService(repo repository.AnalyticsRepository) TCAService {
+	return &tcaServiceImpl{repo: repo}
+}
+
+/*
+GetTCAReports groups the filtered fills into parent orders by label or algorithm
+(within a client, exchange and pair) and returns a transaction cost analysis for each.
+Also returns an error if one occures.
+*/
+
+func (s *tcaServiceImpl) GetTCAReports(req *entity.TCARequest) ([]*entity.TCAReport, error) {
+	fills, err := s.repo.GetHistoryOrders(&req.HistoryFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	type parentKey struct{ id, client, exchange, pair string }
+	type bookKey struct{ exchange, pair string }
+
+	parents := make(map[parentKey][]*entity.HistoryOrder)
+	byBook := make(map[bookKey][]parentKey)
+	for _, f := range fills {
+		id := f.Label
+		if req.GroupBy == entity.TCAGroupByAlgorithm {
+			id = f.AlgorithmNamePlaced
+		}
+		key := parentKey{id, f.ClientName, f.ExchangeName, f.Pair}
+		if _, ok := parents[key]; !ok {
+			b := bookKey{f.ExchangeName, f.Pair}
+			byBook[b] = append(byBook[b], key)
+		}
+		parents[key] = append(parents[key], f)
+	}
+
+	reports := make([]*entity.TCAReport, 0, len(parents))
+	for key, keys := range byBook {
+		// The mids of a pair are loaded once for every parent order trading it.
+		from, to := parents[keys[0]][0].TimePlaced, parents[keys[0]][0].TimePlaced
+		for _, k := range keys {
+			parentFills := parents[k]
+			if start := parentFills[0].TimePlaced; start.Before(from) {
+				from = start
+			}
+			if end := parentFills[len(parentFills)-1].TimePlaced; end.After(to) {
+				to = end
+			}
+		}
+
+		found, err := s.repo.GetMidsBetween(key.exchange, key.pair, from.Add(-markoutLookback), to)
+		if err != nil && !errors.Is(err, entity.ErrNotFound) {
+			return nil, err
+		}
+		mids := toMidPoints(found)
+
+		for _, k := range keys {
+			parentFills := parents[k]
+			bar, err := s.repo.GetIntervalBar(key.exchange, key.pair, parentFills[0].TimePlaced, parentFills[len(parentFills)-1].TimePlaced)
+			if err != nil {
+				return nil, err
+			}
+			reports = append(reports, parentReport(k.id, parentFills, mids, bar))
+		}
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		if !reports[i].StartTime.Equal(reports[j].StartTime) {
+			return reports[i].StartTime.Before(reports[j].StartTime)
+		}
+		return reports[i].ParentID < reports[j].ParentID
+	})
+
+	return reports, nil
+}
+
+/*
+parentReport analyses the fills of one parent order, oldest first, against the book mids of its pair
+and the bar of every fill stored while it was working.
+The parent order is on the side of its net quantity, and its average price comes from the fills on that side.
+The arrival price is the stored book mid at the first placement, falling back to the top of book
+recorded on the first fill. The shortfall is what every fill paid over the arrival price, so round trips
+within the parent order count as costs.
+*/
+func parentReport(id string, fills []*entity.HistoryOrder, mids []midPoint, bar *entity.TradeBar) *entity.TCAReport {
+	first, last := fills[0], fills[len(fills)-1]
+
+	report := &entity.TCAReport{
+		ParentID:     id,
+		ClientName:   first.ClientName,
+		ExchangeName: first.ExchangeName,
+		Pair:         first.Pair,
+		FillCount:    len(fills),
+		StartTime:    first.TimePlaced,
+		EndTime:      last.TimePlaced,
+	}
+
+	var buyNotional, sellNotional float64
+	for _, f := range fills {
+		report.Fees += f.CommissionQuoteQty
+		switch f.Side {
+		case entity.SideBuy:
+			report.BuyQty += f.BaseQty
+			buyNotional += f.Price * f.BaseQty
+		case entity.SideSell:
+			report.SellQty += f.BaseQty
+			sellNotional += f.Price * f.BaseQty
+		}
+	}
+	if report.BuyQty != 0 {
+		report.AvgBuyPrice = buyNotional / report.BuyQty
+	}
+	if report.SellQty != 0 {
+		report.AvgSellPrice = sellNotional / report.SellQty
+	}
+
+	switch {
+	case report.BuyQty > report.SellQty:
+		report.Side = entity.SideBuy
+	case report.SellQty > report.BuyQty:
+		report.Side = entity.SideSell
+	default:
+		report.Side = first.Side
+	}
+	report.Quantity = math.Abs(report.BuyQty - report.SellQty)
+	report.AvgFillPrice = report.AvgBuyPrice
+	if report.Side == entity.SideSell {
+		report.AvgFillPrice = report.AvgSellPrice
+	}
+	sign := sideSign(report.Side)
+
+	if mid, ok := midAsOf(mids, first.TimePlaced, first.TimePlaced.Add(-markoutLookback)); ok {
+		report.ArrivalPrice = mid
+	} else if first.LowestSellPrc > 0 && first.HighestBuyPrc > 0 {
+		report.ArrivalPrice = (first.LowestSellPrc + first.HighestBuyPrc) / 2
+	}
+	report.IntervalTwap = intervalTwap(mids, first.TimePlaced, last.TimePlaced)
+	if bar.Volume != 0 {
+		report.IntervalVwap = bar.Notional / bar.Volume
+		report.Participation = (report.BuyQty + report.SellQty) / bar.Volume
+	}
+
+	// Costs share one base: the net quantity at the arrival price, or everything traded when the fills net to zero.
+	reference := report.ArrivalPrice
+	if reference == 0 {
+		reference = report.AvgFillPrice
+	}
+	quantity := report.Quantity
+	if quantity == 0 {
+		quantity = report.BuyQty + report.SellQty
+	}
+	base := quantity * reference
+
+	report.FeesBps = feeRateBps(report.Fees, base)
+	if report.ArrivalPrice != 0 && base != 0 {
+		var shortfall float64
+		for _, f := range fills {
+			shortfall += sideSign(f.Side) * (f.Price - report.ArrivalPrice) * f.BaseQty
+		}
+		report.ShortfallBps = shortfall / base * 10000
+	}
+	if report.IntervalVwap != 0 && report.AvgFillPrice != 0 {
+		report.VwapSlippageBps = sign * (report.AvgFillPrice - report.IntervalVwap) / report.IntervalVwap * 10000
+	}
+	if report.IntervalTwap != 0 && report.AvgFillPrice != 0 {
+		report.TwapSlippageBps = sign * (report.AvgFillPrice - report.IntervalTwap) / report.IntervalTwap * 10000
+	}
+	report.TotalCostBps = report.ShortfallBps + report.FeesBps
+
+	return report
+}
+
+/*
+intervalTwap averages the mids over [from, to], each weighted by how long it was in force, starting with the mid
+in force at from, so a burst of snapshots counts no more than the time it spans. An interval of no length takes
+the mid in force at from. Returns 0 if there is none.
+*/
+func intervalTwap(mids []midPoint, from, to time.Time) float64 {
+	mid, ok := midAsOf(mids, from, from.Add(-markoutLookback))
+	since := from
+
+	var sum, seconds float64
+	for _, m := range mids {
+		if !m.at.After(from) {
+			continue
+		}
+		if m.at.After(to) {
+			break
+		}
+		if ok {
+			sum += mid * m.at.Sub(since).Seconds()
+			seconds += m.at.Sub(since).Seconds()
+		}
+		mid, ok, since = m.mid, true, m.at
+	}
+	if ok {
+		sum += mid * to.Sub(since).Seconds()
+		seconds += to.Sub(since).Seconds()
+	}
+
+	switch {
+	case seconds > 0:
+		return sum / seconds
+	case ok:
+		return mid
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/egorque1/vortex-test/internal/entity"
+	"github.com/egorque1/vortex-test/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTCAReports(t *testing.T) {
+	mockRepo := new(mocks.MockAnalyticsRepository)
+	mockService := NewTCAService(mockRepo)
+
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fills := []*entity.HistoryOrder{
+		{ClientName: "client1", ExchangeName: "exchange1", Pair: "pair1", Label: "parent1", Side: entity.SideBuy, BaseQty: 1, Price: 100, CommissionQuoteQty: 0.1, TimePlaced: t0},
+		{ClientName: "client1", ExchangeName: "exchange1", Pair: "pair1", Label: "parent1", Side: entity.SideBuy, BaseQty: 2, Price: 102, CommissionQuoteQty: 0.1, TimePlaced: t0.Add(time.Minute)},
+		{ClientName: "client1", ExchangeName: "exchange1", Pair: "pair1", Label: "parent1", Side: entity.SideSell, BaseQty: 1, Price: 99, TimePlaced: t0.Add(2 * time.Minute)},
+		{ClientName: "client1", ExchangeName: "exchange1", Pair: "pair1", Label: "parent2", Side: entity.SideSell, BaseQty: 1, Price: 101, TimePlaced: t0.Add(3 * time.Minute)},
+	}
+	// A burst of snapshots a second after the mid moved to 102 weighs no more than the time it spans.
+	mids := []*entity.BookMid{
+		midAt(t0.Add(-time.Second), t0.Add(-time.Second), 100),
+		midAt(t0.Add(time.Minute), t0.Add(time.Minute), 102),
+		midAt(t0.Add(time.Minute+time.Second), t0.Add(time.Minute+time.Second), 102),
+		midAt(t0.Add(time.Minute+2*time.Second), t0.Add(time.Minute+2*time.Second), 102),
+	}
+
+	req := &entity.TCARequest{GroupBy: entity.TCAGroupByLabel}
+	mockRepo.On("GetHistoryOrders", &req.HistoryFilter).Return(fills, nil)
+	mockRepo.On("GetMidsBetween", "exchange1", "pair1", t0.Add(-markoutLookback), t0.Add(3*time.Minute)).Return(mids, nil).Once()
+	// Other clients traded 6 more at 100 while parent1 was working.
+	mockRepo.On("GetIntervalBar", "exchange1", "pair1", t0, t0.Add(2*time.Minute)).Return(&entity.TradeBar{Volume: 10, Notional: 1003}, nil)
+	mockRepo.On("GetIntervalBar", "exchange1", "pair1", t0.Add(3*time.Minute), t0.Add(3*time.Minute)).Return(&entity.TradeBar{Volume: 1, Notional: 101}, nil)
+
+	reports, err := mockService.GetTCAReports(req)
+
+	assert.NoError(t, err)
+	assert.Len(t, reports, 2)
+
+	// Buying 3 and selling 1 nets to a buy of 2, costed on the buys alone.
+	r := reports[0]
+	assert.Equal(t, "parent1", r.ParentID)
+	assert.Equal(t, entity.SideBuy, r.Side)
+	assert.Equal(t, 3, r.FillCount)
+	assert.InDelta(t, 2, r.Quantity, 1e-9)
+	assert.InDelta(t, 3, r.BuyQty, 1e-9)
+	assert.InDelta(t, 1, r.SellQty, 1e-9)
+	assert.InDelta(t, 99, r.AvgSellPrice, 1e-9)
+	assert.InDelta(t, 100, r.ArrivalPrice, 1e-9)
+	assert.InDelta(t, 304.0/3, r.AvgFillPrice, 1e-9)
+	assert.InDelta(t, 101, r.IntervalTwap, 1e-9)
+	assert.InDelta(t, 100.3, r.IntervalVwap, 1e-9)
+	assert.InDelta(t, 0.4, r.Participation, 1e-9)
+	assert.InDelta(t, (304.0/3-101)/101*10000, r.TwapSlippageBps, 1e-9)
+	assert.InDelta(t, (304.0/3-100.3)/100.3*10000, r.VwapSlippageBps, 1e-9)
+
+	// Buying 2 at an arrival of 100 would have cost 200; the fills cost 5 more and 0.2 in fees.
+	assert.InDelta(t, 250, r.ShortfallBps, 1e-9)
+	assert.InDelta(t, 10, r.FeesBps, 1e-9)
+	assert.InDelta(t, 260, r.TotalCostBps, 1e-9)
+
+	// The second parent order starts long after the arrival lookback of the last book.
+	assert.Equal(t, "parent2", reports[1].ParentID)
+	assert.Equal(t, entity.SideSell, reports[1].Side)
+	assert.Zero(t, reports[1].ArrivalPrice)
+
+	mockRepo.AssertExpectations(t)
+}
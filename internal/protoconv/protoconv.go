@@ -0,0 +1,240 @@
+// Package protoconv converts entities to and from the protobuf messages of the order API.
+package protoconv
+
+import (
+	"time"
+
+	orderv1 "github.com/egorque1/vortex-test/api/order/v1"
+	"github.com/egorque1/vortex-test/internal/entity"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var sides = map[entity.Side]orderv1.Side{
+	entity.SideBuy:  orderv1.Side_SIDE_BUY,
+	entity.SideSell: orderv1.Side_SIDE_SELL,
+}
+
+var orderTypes = map[entity.OrderType]orderv1.OrderType{
+	entity.OrderTypeMarket:    orderv1.OrderType_ORDER_TYPE_MARKET,
+	entity.OrderTypeLimit:     orderv1.OrderType_ORDER_TYPE_LIMIT,
+	entity.OrderTypeStop:      orderv1.OrderType_ORDER_TYPE_STOP,
+	entity.OrderTypeStopLimit: orderv1.OrderType_ORDER_TYPE_STOP_LIMIT,
+	entity.OrderTypePostOnly:  orderv1.OrderType_ORDER_TYPE_POST_ONLY,
+	entity.OrderTypeIOC:       orderv1.OrderType_ORDER_TYPE_IOC,
+	entity.OrderTypeFOK:       orderv1.OrderType_ORDER_TYPE_FOK,
+}
+
+var ingestTypes = map[string]orderv1.IngestMessage_Type{
+	entity.IngestSnapshot: orderv1.IngestMessage_TYPE_SNAPSHOT,
+	entity.IngestDelta:    orderv1.IngestMessage_TYPE_DELTA,
+}
+
+var ingestReplyTypes = map[string]orderv1.IngestReply_Type{
+	entity.IngestWelcome: orderv1.IngestReply_TYPE_WELCOME,
+	entity.IngestAck:     orderv1.IngestReply_TYPE_ACK,
+	entity.IngestNack:    orderv1.IngestReply_TYPE_NACK,
+}
+
+// Timestamp returns nil for the zero time, so unset times stay unset on the wire.
+func Timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func FromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func ToDepthOrders(levels []entity.DepthOrder) []*orderv1.DepthOrder {
+	result := make([]*orderv1.DepthOrder, len(levels))
+	for i, l := range levels {
+		result[i] = &orderv1.DepthOrder{Price: l.Price, BaseQty: l.BaseQty}
+	}
+	return result
+}
+
+func FromDepthOrders(levels []*orderv1.DepthOrder) []entity.DepthOrder {
+	result := make([]entity.DepthOrder, len(levels))
+	for i, l := range levels {
+		result[i] = entity.DepthOrder{Price: l.GetPrice(), BaseQty: l.GetBaseQty()}
+	}
+	return result
+}
+
+func ToOrderBook(b *entity.OrderBook) *orderv1.OrderBook {
+	return &orderv1.OrderBook{
+		Id:        b.ID,
+		Exchange:  b.Exchange,
+		Pair:      b.Pair,
+		Asks:      ToDepthOrders(b.Asks),
+		Bids:      ToDepthOrders(b.Bids),
+		Timestamp: Timestamp(b.Timestamp),
+		Sequence:  b.Sequence,
+		DedupKey:  b.DedupKey,
+	}
+}
+
+func FromOrderBook(b *orderv1.OrderBook) *entity.OrderBook {
+	return &entity.OrderBook{
+		ID:        b.GetId(),
+		Exchange:  b.GetExchange(),
+		Pair:      b.GetPair(),
+		Asks:      FromDepthOrders(b.GetAsks()),
+		Bids:      FromDepthOrders(b.GetBids()),
+		Timestamp: FromTimestamp(b.GetTimestamp()),
+		Sequence:  b.GetSequence(),
+		DedupKey:  b.GetDedupKey(),
+	}
+}
+
+func FromOrderBooks(books []*orderv1.OrderBook) []*entity.OrderBook {
+	result := make([]*entity.OrderBook, len(books))
+	for i, b := range books {
+		result[i] = FromOrderBook(b)
+	}
+	return result
+}
+
+func FromClient(c *orderv1.Client) *entity.Client {
+	return &entity.Client{ClientName: c.GetClientName(), ExchangeName: c.GetExchangeName(), Label: c.GetLabel(), Pair: c.GetPair()}
+}
+
+func ToOrderBooks(books []*entity.OrderBook) []*orderv1.OrderBook {
+	result := make([]*orderv1.OrderBook, len(books))
+	for i, b := range books {
+		result[i] = ToOrderBook(b)
+	}
+	return result
+}
+
+func ToHistoryOrder(o *entity.HistoryOrder) *orderv1.HistoryOrder {
+	return &orderv1.HistoryOrder{
+		OrderId:             o.OrderID,
+		ExchangeOrderId:     o.ExchangeOrderID,
+		FillId:              o.FillID,
+		DedupKey:            o.DedupKey,
+		ClientName:          o.ClientName,
+		ExchangeName:        o.ExchangeName,
+		Label:               o.Label,
+		Pair:                o.Pair,
+		Side:                sides[o.Side],
+		Type:                orderTypes[o.Type],
+		BaseQty:             o.BaseQty,
+		Price:               o.Price,
+		AlgorithmNamePlaced: o.AlgorithmNamePlaced,
+		LowestSellPrc:       o.LowestSellPrc,
+		HighestBuyPrc:       o.HighestBuyPrc,
+		CommissionQuoteQty:  o.CommissionQuoteQty,
+		CommissionAsset:     o.CommissionAsset,
+		CommissionQty:       o.CommissionQty,
+		TimePlaced:          Timestamp(o.TimePlaced),
+	}
+}
+
+// FromHistoryOrder leaves the side and type empty when they are unspecified, for Validate to reject.
+func FromHistoryOrder(o *orderv1.HistoryOrder) entity.HistoryOrder {
+	order := entity.HistoryOrder{
+		OrderID:             o.GetOrderId(),
+		ExchangeOrderID:     o.GetExchangeOrderId(),
+		FillID:              o.GetFillId(),
+		DedupKey:            o.GetDedupKey(),
+		ClientName:          o.GetClientName(),
+		ExchangeName:        o.GetExchangeName(),
+		Label:               o.GetLabel(),
+		Pair:                o.GetPair(),
+		BaseQty:             o.GetBaseQty(),
+		Price:               o.GetPrice(),
+		AlgorithmNamePlaced: o.GetAlgorithmNamePlaced(),
+		LowestSellPrc:       o.GetLowestSellPrc(),
+		HighestBuyPrc:       o.GetHighestBuyPrc(),
+		CommissionQuoteQty:  o.GetCommissionQuoteQty(),
+		CommissionAsset:     o.GetCommissionAsset(),
+		CommissionQty:       o.GetCommissionQty(),
+		TimePlaced:          FromTimestamp(o.GetTimePlaced()),
+	}
+	for side, v := range sides {
+		if v == o.GetSide() {
+			order.Side = side
+		}
+	}
+	for orderType, v := range orderTypes {
+		if v == o.GetType() {
+			order.Type = orderType
+		}
+	}
+	return order
+}
+
+func ToHistoryOrders(orders []*entity.HistoryOrder) []*orderv1.HistoryOrder {
+	result := make([]*orderv1.HistoryOrder, len(orders))
+	for i, o := range orders {
+		result[i] = ToHistoryOrder(o)
+	}
+	return result
+}
+
+func FromHistoryFilter(f *orderv1.HistoryFilter) entity.HistoryFilter {
+	return entity.HistoryFilter{
+		ClientName:     f.GetClientName(),
+		ExchangeName:   f.GetExchangeName(),
+		Pairs:          f.GetPairs(),
+		Labels:         f.GetLabels(),
+		AlgorithmNames: f.GetAlgorithmNames(),
+		From:           FromTimestamp(f.GetFrom()),
+		To:             FromTimestamp(f.GetTo()),
+	}
+}
+
+func ToSaveResult(r *entity.SaveResult) *orderv1.SaveResult {
+	result := &orderv1.SaveResult{Saved: int32(r.Saved), Duplicates: r.Duplicates, Replayed: r.Replayed}
+	for _, b := range r.RiskBreaches {
+		result.RiskBreaches = append(result.RiskBreaches, &orderv1.RiskBreach{
+			ClientName:   b.ClientName,
+			ExchangeName: b.ExchangeName,
+			Pair:         b.Pair,
+			Limit:        b.Limit,
+			Threshold:    b.Threshold,
+			Value:        b.Value,
+			OrderId:      b.OrderID,
+			DetectedAt:   Timestamp(b.DetectedAt),
+		})
+	}
+	return result
+}
+
+// FromIngestMessage leaves the type empty when it is unspecified, for Validate to reject.
+func FromIngestMessage(m *orderv1.IngestMessage) entity.IngestMessage {
+	msg := entity.IngestMessage{ID: m.GetId(), Books: FromOrderBooks(m.GetBooks())}
+	for t, v := range ingestTypes {
+		if v == m.GetType() {
+			msg.Type = t
+		}
+	}
+	if d := m.GetDelta(); d != nil {
+		msg.Delta = &entity.OrderBookDelta{
+			Exchange:  d.GetExchange(),
+			Pair:      d.GetPair(),
+			Asks:      FromDepthOrders(d.GetAsks()),
+			Bids:      FromDepthOrders(d.GetBids()),
+			Timestamp: FromTimestamp(d.GetTimestamp()),
+			Sequence:  d.GetSequence(),
+		}
+	}
+	return msg
+}
+
+func ToIngestReply(r *entity.IngestReply) *orderv1.IngestReply {
+	reply := &orderv1.IngestReply{Type: ingestReplyTypes[r.Type], Id: r.ID, Books: int32(r.Books), Window: int32(r.Window)}
+	if r.Error != nil {
+		reply.Error = &orderv1.ErrorBody{Code: r.Error.Code, Message: r.Error.Message, RequestId: r.Error.RequestID}
+		for _, d := range r.Error.Details {
+			reply.Error.Details = append(reply.Error.Details, &orderv1.ErrorBody_FieldError{Field: d.Field, Message: d.Message})
+		}
+	}
+	return reply
+}
@@ -0,0 +1,99 @@
+package pubsub
+
+import "sync"
+
+/*
+Broker fans values published on a topic out to the subscribers of that topic.
+Publishing never blocks: a subscriber whose buffer is full is dropped and its channel closed,
+so one slow consumer cannot hold up the publisher or the other subscribers.
+*/
+type Broker[T any] struct {
+	mu     sync.Mutex
+	buffer int
+	topics map[string]map[*Subscription[T]]struct{}
+}
+
+// Subscription receives the values published on its topics on C until it is closed or dropped.
+type Subscription[T any] struct {
+	C       <-chan T
+	c       chan T
+	topics  []string
+	broker  *Broker[T]
+	closed  bool
+	dropped bool
+}
+
+func New[T any](buffer int) *Broker[T] {
+	return &Broker[T]{buffer: buffer, topics: make(map[string]map[*Subscription[T]]struct{})}
+}
+
+// Subscribe returns a subscription to topics buffering up to the broker buffer size of values.
+func (b *Broker[T]) Subscribe(topics ...string) *Subscription[T] {
+	c := make(chan T, b.buffer)
+	s := &Subscription[T]{C: c, c: c, topics: topics, broker: b}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, topic := range topics {
+		subs, ok := b.topics[topic]
+		if !ok {
+			subs = make(map[*Subscription[T]]struct{})
+			b.topics[topic] = subs
+		}
+		subs[s] = struct{}{}
+	}
+	return s
+}
+
+// Publish sends v to every subscriber of topic, dropping those that have not kept up.
+func (b *Broker[T]) Publish(topic string, v T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.topics[topic] {
+		select {
+		case s.c <- v:
+		default:
+			s.dropped = true
+			b.remove(s)
+		}
+	}
+}
+
+// Subscribers returns the number of subscriptions to topic.
+func (b *Broker[T]) Subscribers(topic string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.topics[topic])
+}
+
+// remove unsubscribes s from all its topics and closes its channel. The caller holds the lock.
+func (b *Broker[T]) remove(s *Subscription[T]) {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.c)
+
+	for _, topic := range s.topics {
+		delete(b.topics[topic], s)
+		if len(b.topics[topic]) == 0 {
+			delete(b.topics, topic)
+		}
+	}
+}
+
+// Close unsubscribes and closes C. It is safe to call more than once and after the subscription was dropped.
+func (s *Subscription[T]) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
+
+// Dropped reports whether the subscription was closed because its buffer filled up.
+func (s *Subscription[T]) Dropped() bool {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	return s.dropped
+}
@@ -0,0 +1,51 @@
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroker(t *testing.T) {
+	b := New[int](2)
+
+	s1 := b.Subscribe("a", "b")
+	s2 := b.Subscribe("b")
+	assert.Equal(t, 2, b.Subscribers("b"))
+
+	b.Publish("a", 1)
+	b.Publish("b", 2)
+	b.Publish("c", 3)
+
+	assert.Equal(t, 1, <-s1.C)
+	assert.Equal(t, 2, <-s1.C)
+	assert.Equal(t, 2, <-s2.C)
+
+	s2.Close()
+	s2.Close()
+	_, ok := <-s2.C
+	assert.False(t, ok)
+	assert.False(t, s2.Dropped())
+	assert.Equal(t, 1, b.Subscribers("b"))
+}
+
+func TestBrokerDropsSlowSubscribers(t *testing.T) {
+	b := New[int](1)
+
+	slow := b.Subscribe("a")
+	fast := b.Subscribe("a")
+
+	b.Publish("a", 1)
+	<-fast.C
+	b.Publish("a", 2)
+
+	assert.True(t, slow.Dropped())
+	assert.Equal(t, 1, <-slow.C)
+	_, ok := <-slow.C
+	assert.False(t, ok)
+
+	assert.Equal(t, 2, <-fast.C)
+	assert.False(t, fast.Dropped())
+	assert.Equal(t, 1, b.Subscribers("a"))
+	slow.Close()
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often buckets that have refilled completely, and so hold no state worth keeping, are dropped.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	rate   float64
+	burst  int
+}
+
+// refill returns the tokens in the bucket at now.
+func (b *bucket) refill(now time.Time) float64 {
+	return math.Min(float64(b.burst), b.tokens+now.Sub(b.last).Seconds()*b.rate)
+}
+
+/*
+Limiter is a concurrency-safe set of token buckets, one per key. A bucket holds up to burst tokens,
+refills at rate tokens per second and every allowed request takes a token.
+*/
+type Limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	// now is a field so tests can move the clock.
+	now func() time.Time
+}
+
+// Result is the outcome of a request against the bucket of its key.
+type Result struct {
+	Allowed bool
+	// Limit is the burst of the bucket, the most requests allowed at once.
+	Limit int
+	// Remaining is the number of requests allowed right now.
+	Remaining int
+	// Reset is how long until the bucket has refilled completely.
+	Reset time.Duration
+	// RetryAfter is how long until a request is allowed again, zero if one is allowed now.
+	RetryAfter time.Duration
+}
+
+func New() *Limiter {
+	return &Limiter{buckets: make(map[string]*bucket), lastSweep: time.Now(), now: time.Now}
+}
+
+/*
+Allow takes a token from the bucket of key, which refills at rate tokens per second up to burst.
+A bucket seen for the first time is full; a changed rate or burst applies to the tokens already in it.
+*/
+func (l *Limiter) Allow(key string, rate float64, burst int) Result {
+	return l.take(key, rate, burst, 1)
+}
+
+// Peek reports whether Allow would allow a request of key now, without taking a token from its bucket.
+func (l *Limiter) Peek(key string, rate float64, burst int) Result {
+	return l.take(key, rate, burst, 0)
+}
+
+// take refills the bucket of key and takes n tokens from it if it holds at least one.
+func (l *Limiter) take(key string, rate float64, burst int, n float64) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), last: now}
+		l.buckets[key] = b
+	}
+	b.rate, b.burst = rate, burst
+	b.tokens, b.last = b.refill(now), now
+
+	result := Result{Limit: burst}
+	if b.tokens >= 1 {
+		b.tokens -= n
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((float64(burst) - b.tokens) / rate)
+
+	return result
+}
+
+// sweep drops the buckets that have refilled completely, at most once per sweepInterval.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	for k, b := range l.buckets {
+		if b.refill(now) >= float64(b.burst) {
+			delete(l.buckets, k)
+		}
+	}
+	l.lastSweep = now
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllow(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	l := New()
+	l.now = func() time.Time { return now }
+
+	// A new bucket allows a burst.
+	for i := 0; i < 3; i++ {
+		result := l.Allow("key1", 1, 3)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2-i, result.Remaining)
+	}
+
+	result := l.Allow("key1", 1, 3)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 3, result.Limit)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+
+	// Peeking does not take a token.
+	assert.False(t, l.Peek("key1", 1, 3).Allowed)
+	assert.True(t, l.Peek("key2", 1, 3).Allowed)
+	assert.Equal(t, 3, l.Peek("key2", 1, 3).Remaining)
+
+	// Other keys have their own bucket.
+	assert.True(t, l.Allow("key2", 1, 3).Allowed)
+
+	// The bucket refills at the rate.
+	now = now.Add(1500 * time.Millisecond)
+	result = l.Allow("key1", 1, 3)
+	assert.True(t, result.Allowed)
+	assert.Zero(t, result.Remaining)
+	assert.False(t, l.Allow("key1", 1, 3).Allowed)
+
+	// A raised quota applies to the tokens already in the bucket.
+	now = now.Add(time.Second)
+	assert.True(t, l.Allow("key1", 10, 20).Allowed)
+}
+
+func TestAllow_Sweep(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	l := New()
+	l.now = func() time.Time { return now }
+	l.lastSweep = now
+
+	l.Allow("idle", 1, 5)
+	l.Allow("slow", 0.001, 5)
+
+	now = now.Add(sweepInterval)
+	l.Allow("busy", 1, 5)
+
+	assert.NotContains(t, l.buckets, "idle")
+	assert.Contains(t, l.buckets, "slow")
+	assert.Contains(t, l.buckets, "busy")
+}
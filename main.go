@@ -2,25 +2,43 @@ package main
 
 import (
 	"log"
+	"net"
 	"net/http"
-	"time"
+	"os"
+	"runtime/debug"
 
 	_ "github.com/egorque1/vortex-test/docs"
 	"github.com/egorque1/vortex-test/internal/db"
+	"github.com/egorque1/vortex-test/internal/entity"
 	"github.com/egorque1/vortex-test/internal/modules/controller"
 	"github.com/egorque1/vortex-test/internal/modules/repository"
+	"github.com/egorque1/vortex-test/internal/modules/rpc"
 	"github.com/egorque1/vortex-test/internal/modules/service"
 	"github.com/go-chi/chi"
-	"github.com/go-chi/httprate"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+// version is the version of the build, set with -ldflags "-X main.version=...".
+var version = "dev"
+
 // @title swagger Order Management API
 // @version 1.0
 // @description This is a sample server for managing orders.
 // @BasePath /
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description An API key or, when JWT_SECRET is set, a JWT, as "Bearer <token>".
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "apikey" {
+		os.Exit(runAPIKey(os.Args[2:]))
+	}
+
 	database, err := db.Connect(".env")
 	if err != nil {
 		log.Fatalf("failed to connect to database: %v", err)
@@ -31,27 +49,191 @@ func main() {
 		log.Fatalf("failed to migrate database: %v", err)
 	}
 
+	apiKeyRepo := repository.NewAPIKeyRepository(database)
+	authService := service.NewAuthService(apiKeyRepo, []byte(os.Getenv("JWT_SECRET")))
+	apiKeyController := controller.NewAPIKeyController(authService)
+
+	// The default quotas of the route groups, which PUT /admin/rate-limits overrides for routes and API keys.
+	// The ip group limits the requests of each client IP that fail authentication.
+	rateQuotaRepo := repository.NewRateQuotaRepository(database)
+	rateLimitService := service.NewRateLimitService(rateQuotaRepo, []entity.RateQuota{
+		{Route: "ip", Rate: 10, Burst: 20},
+		{Route: "read", Rate: 100, Burst: 200},
+		{Route: "write", Rate: 200, Burst: 400},
+	})
+	rateLimitController := controller.NewRateLimitController(rateLimitService)
+
+	clientRepo := repository.NewClientRepository(database)
+	clientService := service.NewClientService(clientRepo)
+	clientController := controller.NewClientController(clientService)
+
+	riskRepo := repository.NewRiskRepository(database)
+	riskService := service.NewRiskService(riskRepo)
+	riskController := controller.NewRiskController(riskService)
+
 	orderBookRepo := repository.NewOrderRepository(database)
-	orderBookService := service.NewOrderService(orderBookRepo)
+	streamService := service.NewStreamService(orderBookRepo)
+	streamController := controller.NewStreamController(streamService)
+	orderBookService := service.NewOrderService(orderBookRepo, clientService, riskService, streamService)
 	orderBookController := controller.NewController(orderBookRepo, orderBookService)
+	// The service stops being ready once collectors have sent this many order books that are not saved yet.
+	ingestQueue := service.NewIngestQueue(10000)
+	ingestController := controller.NewIngestController(orderBookService, ingestQueue)
+
+	healthRepo := repository.NewHealthRepository(database)
+	healthService := service.NewHealthService(healthRepo, ingestQueue, db.Tables, db.Columns, buildVersion())
+	healthController := controller.NewHealthController(healthService)
+
+	reportRepo := repository.NewReportRepository(database)
+	reportService := service.NewReportService(reportRepo)
+	reportController := controller.NewReportController(reportService)
+
+	analyticsRepo := repository.NewAnalyticsRepository(database)
+	analyticsService := service.NewAnalyticsService(analyticsRepo)
+	analyticsController := controller.NewAnalyticsController(analyticsService)
+
+	conversionRepo := repository.NewConversionRepository(database)
+	conversionService := service.NewConversionService(conversionRepo, analyticsRepo)
+	conversionController := controller.NewConversionController(conversionService)
+
+	feeRepo := repository.NewFeeRepository(database)
+	feeService := service.NewFeeService(feeRepo, analyticsRepo, conversionService)
+	feeController := controller.NewFeeController(feeService)
+
+	tcaService := service.NewTCAService(analyticsRepo)
+	tcaController := controller.NewTCAController(tcaService)
+
+	orderEventRepo := repository.NewOrderEventRepository(database)
+	orderEventService := service.NewOrderEventService(orderEventRepo)
+	orderEventController := controller.NewOrderEventController(orderEventService)
+
+	exportRepo := repository.NewExportRepository(database)
+	exportService := service.NewExportService(exportRepo)
+	exportController := controller.NewExportController(exportService)
 
 	r := chi.NewMux()
+	r.Use(controller.RequestID)
+	r.NotFound(controller.NotFoundHandler)
+	r.MethodNotAllowed(controller.MethodNotAllowedHandler)
 
 	r.Mount("/swagger", httpSwagger.WrapHandler)
 
+	// Probes are neither authenticated nor rate limited so orchestrators can always reach them.
+	r.Get("/healthz", healthController.HealthzHandler)
+	r.Get("/readyz", healthController.ReadyzHandler)
+
 	r.Group(func(r chi.Router) {
-		r.Use(httprate.LimitByIP(100, 1*time.Second))
+		r.Use(controller.Authenticate(authService, rateLimitService))
+		r.Use(controller.RateLimit(rateLimitService, "read"))
+
+		r.Group(func(r chi.Router) {
+			r.Use(controller.RequireScope(entity.ScopeReadBooks))
 
-		r.Get("/orderbook", orderBookController.GetOrderBookHandler)
-		r.Get("/history", orderBookController.GetOrderHistoryHandler)
+			r.With(controller.Deprecated("/v1/orderbooks/{exchange}/{pair}")).Get("/orderbook", orderBookController.GetOrderBookHandler)
+			r.Get("/v1/orderbooks/{exchange}/{pair}", orderBookController.GetOrderBookV1Handler)
+			r.Get("/v1/stream/orderbooks", streamController.OrderBookStreamHandler)
+			r.Get("/v1/orderbooks/{exchange}/{pair}/export", exportController.ExportOrderBookHandler)
+		})
+		// The handlers check the client of each request against the key.
+		r.Group(func(r chi.Router) {
+			r.Use(controller.RequireScope(entity.ScopeReadHistory))
+
+			// The body-based reads stay for existing callers until they move to /v1.
+			r.With(controller.Deprecated("/v1/history/{clientName}")).Get("/history", orderBookController.GetOrderHistoryHandler)
+			r.Get("/v1/history/{clientName}", orderBookController.GetOrderHistoryV1Handler)
+			r.Get("/v1/stream/fills", streamController.FillStreamHandler)
+			r.Get("/v1/history/{clientName}/export", exportController.ExportOrderHistoryHandler)
+			r.Post("/risk/check", riskController.CheckOrderHandler)
+			r.Get("/risk/limits", riskController.GetRiskLimitsHandler)
+			r.Get("/risk/breaches", riskController.GetRiskBreachesHandler)
+		})
+		// Reports, analytics and accounts span clients, so keys restricted to some clients cannot read them.
+		r.Group(func(r chi.Router) {
+			r.Use(controller.RequireAllClients(entity.ScopeReadHistory))
+
+			r.Get("/reports/commission", reportController.GetCommissionReportHandler)
+			r.Get("/reports/fees", feeController.ReconcileCommissionsHandler)
+			r.Get("/reports/commission/converted", conversionController.GetConvertedCommissionsHandler)
+			r.Get("/fees/schedules", feeController.GetFeeSchedulesHandler)
+			r.Get("/analytics/algorithms", analyticsController.GetAlgorithmPerformanceHandler)
+			r.Get("/analytics/slippage", analyticsController.GetSlippageAnalysisHandler)
+			r.Get("/analytics/markouts", analyticsController.GetMarkoutsHandler)
+			r.Get("/analytics/bars", analyticsController.GetTradeBarsHandler)
+			r.Get("/tca", tcaController.GetTCAReportsHandler)
+			r.Get("/clients", clientController.GetClientsHandler)
+			r.Get("/clients/{clientName}/{exchangeName}/{label}", clientController.GetClientHandler)
+			r.Get("/orders/{orderID}/timeline", orderEventController.GetOrderTimelineHandler)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(controller.RequireScope(entity.ScopeAdmin))
+
+			r.Get("/admin/api-keys", apiKeyController.GetAPIKeysHandler)
+			r.Get("/admin/rate-limits", rateLimitController.GetRateQuotasHandler)
+			r.Get("/status", healthController.StatusHandler)
+		})
 	})
 	r.Group(func(r chi.Router) {
-		r.Use(httprate.LimitByIP(200, 1*time.Second))
+		r.Use(controller.Authenticate(authService, rateLimitService))
+		r.Use(controller.RateLimit(rateLimitService, "write"))
+
+		r.Group(func(r chi.Router) {
+			r.Use(controller.RequireScope(entity.ScopeWriteBooks))
 
-		r.Post("/orderbook", orderBookController.SaveOrderBookHandler)
-		r.Post("/history", orderBookController.SaveOrderHistoryHandler)
+			r.Post("/orderbook", orderBookController.SaveOrderBookHandler)
+			r.Get("/v1/ingest/orderbooks", ingestController.IngestOrderBooksHandler)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(controller.RequireScope(entity.ScopeWriteHistory))
+
+			r.Post("/history", orderBookController.SaveOrderHistoryHandler)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(controller.RequireAllClients(entity.ScopeWriteHistory))
+
+			r.Post("/orders/events", orderEventController.SaveOrderEventHandler)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(controller.RequireScope(entity.ScopeAdmin))
+
+			r.Post("/clients", clientController.CreateClientHandler)
+			r.Put("/clients/{clientName}/{exchangeName}/{label}", clientController.UpdateClientHandler)
+			r.Delete("/clients/{clientName}/{exchangeName}/{label}", clientController.DeleteClientHandler)
+			r.Put("/risk/limits", riskController.SetRiskLimitHandler)
+			r.Put("/fees/schedules", feeController.SetFeeScheduleHandler)
+			r.Post("/admin/api-keys", apiKeyController.CreateAPIKeyHandler)
+			r.Delete("/admin/api-keys/{id}", apiKeyController.RevokeAPIKeyHandler)
+			r.Put("/admin/rate-limits", rateLimitController.SetRateQuotaHandler)
+			r.Delete("/admin/rate-limits", rateLimitController.DeleteRateQuotaHandler)
+		})
 	})
 
+	grpcServer := rpc.NewServer(rpc.NewOrderServer(orderBookService, streamService), authService, rateLimitService)
+	go func() {
+		lis, err := net.Listen("tcp", ":9090")
+		if err != nil {
+			log.Fatalf("failed to listen for gRPC: %v", err)
+		}
+		log.Println("gRPC server is running on port 9090")
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("failed to serve gRPC: %v", err)
+		}
+	}()
+
 	log.Println("Server is running on port 8080")
 	http.ListenAndServe(":8080", r)
 }
+
+// buildVersion returns the version set at build time or, failing that, the commit the binary was built from.
+func buildVersion() string {
+	if version != "dev" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				return setting.Value
+			}
+		}
+	}
+	return version
+}